@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Tbits007/auth/internal/config"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+)
+
+const keysUsage = `usage: auth keys <command>
+
+commands:
+  list           show the keys in the keyring file
+  promote <kid>  sign new tokens with <kid>; the current key keeps
+                 verifying for one token TTL
+  prune          drop keys whose verification window has ended
+
+Add a new key with status "verify" and reload every instance (SIGHUP)
+before promoting it, so all of them accept its tokens.`
+
+func loadKeyring(cfg config.Auth) (*jwt.Keyring, error) {
+	if cfg.KeyringPath != "" {
+		return jwt.LoadKeyring(cfg.KeyringPath)
+	}
+
+	key, err := jwt.LoadKey(cfg.KeyID, cfg.SigningAlgorithm, cfg.SecretKey, cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return jwt.NewKeyring(key)
+}
+
+func reloadKeyring(log *slog.Logger, cfg config.Auth, keyring *jwt.Keyring) {
+	reloaded, err := loadKeyring(cfg)
+	if err != nil {
+		log.Error("failed to reload signing keys", sl.Err(err))
+		return
+	}
+
+	keyring.Replace(reloaded)
+	log.Info("signing keys reloaded", slog.String("active_kid", keyring.Active().ID()))
+}
+
+func runKeysCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(keysUsage)
+	}
+
+	path := cfg.Auth.KeyringPath
+	if path == "" {
+		return errors.New("auth.keyringPath is not set")
+	}
+
+	f, err := jwt.ReadKeyFile(path)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	switch args[0] {
+	case "list":
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "KID\tALG\tSTATUS\tRETIRE AT")
+		for _, entry := range f.Keys {
+			retireAt := "-"
+			if entry.RetireAt != nil {
+				retireAt = entry.RetireAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", entry.ID, entry.Algorithm, entry.Status, retireAt)
+		}
+		return w.Flush()
+
+	case "promote":
+		if len(args) != 2 {
+			return errors.New(keysUsage)
+		}
+		if err := f.Promote(args[1], now, cfg.Auth.TokenTTL); err != nil {
+			return err
+		}
+		if _, err := f.Keyring(); err != nil {
+			return fmt.Errorf("refusing to write invalid keyring: %w", err)
+		}
+		if err := f.WriteFile(path); err != nil {
+			return err
+		}
+		fmt.Printf("promoted %s; previous keys retire at %s\n", args[1], now.Add(cfg.Auth.TokenTTL).Format(time.RFC3339))
+		return nil
+
+	case "prune":
+		removed := f.Prune(now)
+		if len(removed) == 0 {
+			fmt.Println("nothing to prune")
+			return nil
+		}
+		if err := f.WriteFile(path); err != nil {
+			return err
+		}
+		fmt.Printf("pruned %v\n", removed)
+		return nil
+
+	default:
+		return errors.New(keysUsage)
+	}
+}
@@ -13,7 +13,11 @@ import (
 
 	"github.com/Tbits007/auth/internal/app"
 	"github.com/Tbits007/auth/internal/config"
+	"github.com/Tbits007/auth/internal/lib/jwt"
 	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/passkey"
+	"github.com/Tbits007/auth/internal/lib/secretbox"
+	"github.com/Tbits007/auth/internal/services/auth"
 	"github.com/go-redis/redis_rate/v10"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/prometheus/client_golang/prometheus"
@@ -30,20 +34,29 @@ func main() {
 
 	cfg := config.MustLoad()
 
+	if len(os.Args) > 1 && os.Args[1] == "keys" {
+		if err := runKeysCommand(cfg, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "outbox" {
+		if err := runOutboxCommand(cfg, os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	log := setupLogger(cfg.Env)
     log = log.With(slog.String("env", cfg.Env))
 
     log.Info("initializing server", slog.Int("port", cfg.GRPCServer.Port))
     log.Debug("logger debug mode enabled")
 
-	connString := fmt.Sprintf(
-		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
-		cfg.Postgres.User,
-        cfg.Postgres.Password,
-        cfg.Postgres.Host,
-        cfg.Postgres.Port,
-        cfg.Postgres.DBName,
-	)
+	connString := postgresConnString(cfg.Postgres)
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
@@ -65,6 +78,67 @@ func main() {
 	
 	rateLimit := redis_rate.NewLimiter(rdb)
 
+	keyring, err := loadKeyring(cfg.Auth)
+	if err != nil {
+		log.Error("failed to load signing keys", sl.Err(err))
+		os.Exit(1)
+	}
+
+	tokenIssuer := jwt.NewIssuer(keyring, cfg.Auth.Issuer, cfg.Auth.Audience)
+
+	passwordPolicy, err := newPasswordPolicy(cfg.Auth.PasswordPolicy)
+	if err != nil {
+		log.Error("failed to initialize password policy", sl.Err(err))
+		os.Exit(1)
+	}
+
+	passwordHasher, err := newPasswordHasher(cfg.Auth.PasswordHashing)
+	if err != nil {
+		log.Error("failed to initialize password hasher", sl.Err(err))
+		os.Exit(1)
+	}
+
+	secretBox, err := secretbox.NewFromBase64(cfg.Auth.MFA.EncryptionKey)
+	if err != nil {
+		log.Error("failed to initialize mfa encryption key", sl.Err(err))
+		os.Exit(1)
+	}
+
+	tokenBox, err := secretbox.NewFromBase64(cfg.Auth.TokenEncryptionKey)
+	if err != nil {
+		log.Error("failed to initialize token encryption key", sl.Err(err))
+		os.Exit(1)
+	}
+
+	relyingParty, err := passkey.New(passkey.Config{
+		RPID:          cfg.Auth.Passkey.RPID,
+		RPDisplayName: cfg.Auth.Passkey.RPDisplayName,
+		RPOrigins:     cfg.Auth.Passkey.RPOrigins,
+		Timeout:       cfg.Auth.Passkey.ChallengeTTL,
+	})
+	if err != nil {
+		log.Error("failed to initialize passkeys", sl.Err(err))
+		os.Exit(1)
+	}
+
+	rateLimitPolicy, err := newRateLimitPolicy(cfg.RateLimit)
+	if err != nil {
+		log.Error("failed to initialize rate limits", sl.Err(err))
+		os.Exit(1)
+	}
+
+	outboxCfg, err := newOutboxConfig(cfg.Outbox)
+	if err != nil {
+		log.Error("invalid outbox config", sl.Err(err))
+		os.Exit(1)
+	}
+
+	publisher, err := newPublisher(log, rdb, cfg.Outbox)
+	if err != nil {
+		log.Error("failed to initialize outbox publisher", sl.Err(err))
+		os.Exit(1)
+	}
+
 	metricsServer := &http.Server{Addr: ":8081"}
 	reg := prometheus.NewRegistry()	
 
@@ -73,18 +147,56 @@ func main() {
 		db,
 		rdb,
 		rateLimit,
-		cfg.Auth.SecretKey,
+		rateLimitPolicy,
+		cfg.RateLimit.APIKeys,
+		tokenIssuer,
+		passwordPolicy,
+		passwordHasher,
+		secretBox,
+		tokenBox,
+		relyingParty,
 		cfg.GRPCServer.Port,
-		cfg.Auth.TokenTTL,
+		cfg.GRPCServer.TrustProxyHeaders,
+		auth.Config{
+			TokenTTL:                 cfg.Auth.TokenTTL,
+			RefreshTokenTTL:          cfg.Auth.RefreshTokenTTL,
+			VerificationTokenTTL:     cfg.Auth.VerificationTokenTTL,
+			PasswordResetTokenTTL:    cfg.Auth.PasswordResetTokenTTL,
+			ResendVerificationLimit:  cfg.Auth.ResendVerificationLimit,
+			ResendVerificationPeriod: cfg.Auth.ResendVerificationPeriod,
+			LoginLockoutThreshold:    cfg.Auth.LoginLockout.Threshold,
+			IPLockoutThreshold:       cfg.Auth.LoginLockout.IPThreshold,
+			LoginLockoutWindow:       cfg.Auth.LoginLockout.Window,
+			LoginLockoutDuration:     cfg.Auth.LoginLockout.Duration,
+			MaxLoginLockoutDuration:  cfg.Auth.LoginLockout.MaxDuration,
+			MFAIssuer:                cfg.Auth.MFA.Issuer,
+			MFAChallengeTTL:          cfg.Auth.MFA.ChallengeTTL,
+			MFAMaxAttempts:           cfg.Auth.MFA.MaxAttempts,
+			RecoveryCodeCount:        cfg.Auth.MFA.RecoveryCodes,
+			PasskeyChallengeTTL:      cfg.Auth.Passkey.ChallengeTTL,
+			UserCacheTTL:             cfg.Auth.UserCacheTTL,
+		},
+		cfg.Authz.CheckCacheTTL,
+		outboxCfg,
+		publisher,
 		metricsServer,
 		reg,
 	)
 
-	application.GRPCServer.MustRun()
-	
+	// Register before the servers start so that an early SIGHUP reloads
+	// the keyring instead of killing the process.
 	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
-	<-stop
+	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT, syscall.SIGHUP)
+
+	go application.OutboxRelay.Run()
+	go application.GRPCServer.MustRun()
+
+	for sig := range stop {
+		if sig != syscall.SIGHUP {
+			break
+		}
+		reloadKeyring(log, cfg.Auth, keyring)
+	}
 	gracefulShutdown(log, application, db, rdb)
 }
 
@@ -94,29 +206,28 @@ func gracefulShutdown(log *slog.Logger, application *app.App, db *pgxpool.Pool,
 	done := make(chan struct{})
 
 	log.Info("starting graceful shutdown...")
-		
-	var wg sync.WaitGroup
-	wg.Add(3)
-		
-	go func() {
-		defer wg.Done()
-		application.GRPCServer.Stop(shutdownCtx)
-	}()
-		
-	go func() {
-		defer wg.Done()
-		db.Close()
-	}()
 
+	// The server and the relay still use db and rdb while they drain, so
+	// the connections are closed only after both have stopped.
 	go func() {
-		defer wg.Done()
-		rdb.Close()
-	}()
+		var wg sync.WaitGroup
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+			application.GRPCServer.Stop(shutdownCtx)
+		}()
+
+		go func() {
+			defer wg.Done()
+			application.OutboxRelay.Stop(shutdownCtx)
+		}()
 
-    go func() {
-        wg.Wait()
+		wg.Wait()
+		db.Close()
+		rdb.Close()
 		close(done)
-    }()
+	}()
 	
 	select {
 	case <-done:
@@ -127,6 +238,17 @@ func gracefulShutdown(log *slog.Logger, application *app.App, db *pgxpool.Pool,
 	}	
 }
 
+func postgresConnString(cfg config.Postgres) string {
+	return fmt.Sprintf(
+		"postgres://%s:%s@%s:%d/%s?sslmode=disable",
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.DBName,
+	)
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
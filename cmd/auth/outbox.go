@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Tbits007/auth/internal/config"
+	"github.com/Tbits007/auth/internal/lib/logger/slogdiscard"
+	"github.com/Tbits007/auth/internal/services/outbox"
+	"github.com/Tbits007/auth/internal/storage/postgres/eventRepo"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const outboxUsage = `usage: auth outbox <command>
+
+commands:
+  failed [limit]      list failed events (default limit 50)
+  show <id>           print an event with its last error and payload,
+                      with token fields redacted
+  requeue <id>|all    move failed events back to pending with a fresh
+                      attempt budget
+  cleanup             delete processed events older than outbox.retention`
+
+func runOutboxCommand(cfg *config.Config, args []string) error {
+	if len(args) == 0 {
+		return errors.New(outboxUsage)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	db, err := pgxpool.New(ctx, postgresConnString(cfg.Postgres))
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	repo := eventRepo.NewEventRepo(db)
+
+	switch args[0] {
+	case "failed":
+		limit := 50
+		if len(args) > 1 {
+			if limit, err = strconv.Atoi(args[1]); err != nil || limit <= 0 {
+				return fmt.Errorf("invalid limit %q", args[1])
+			}
+		}
+
+		events, err := repo.ListFailed(ctx, limit)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tTYPE\tATTEMPTS\tCREATED AT\tLAST ERROR")
+		for _, event := range events {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n",
+				event.ID,
+				event.EventType,
+				event.Attempts,
+				event.CreatedAt.Format(time.RFC3339),
+				event.LastError,
+			)
+		}
+		return w.Flush()
+
+	case "show":
+		if len(args) != 2 {
+			return errors.New("usage: auth outbox show <id>")
+		}
+		id, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid event id %q", args[1])
+		}
+
+		event, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("id:         %s\n", event.ID)
+		fmt.Printf("type:       %s\n", event.EventType)
+		fmt.Printf("status:     %s\n", event.Status)
+		fmt.Printf("attempts:   %d\n", event.Attempts)
+		fmt.Printf("created at: %s\n", event.CreatedAt.Format(time.RFC3339))
+		fmt.Printf("last error: %s\n", event.LastError)
+		fmt.Printf("payload:    %s\n", redactPayload(event.Payload))
+		return nil
+
+	case "requeue":
+		if len(args) != 2 {
+			return errors.New("usage: auth outbox requeue <id>|all")
+		}
+
+		if args[1] == "all" {
+			n, err := repo.RequeueAllFailed(ctx)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("requeued %d events\n", n)
+			return nil
+		}
+
+		id, err := uuid.Parse(args[1])
+		if err != nil {
+			return fmt.Errorf("invalid event id %q", args[1])
+		}
+		if err := repo.Requeue(ctx, id); err != nil {
+			return err
+		}
+		fmt.Printf("requeued %s\n", id)
+		return nil
+
+	case "cleanup":
+		if cfg.Outbox.Retention <= 0 {
+			return errors.New("outbox.retention is not set")
+		}
+
+		outboxCfg, err := newOutboxConfig(cfg.Outbox)
+		if err != nil {
+			return err
+		}
+
+		relay := outbox.NewRelay(slogdiscard.NewDiscardLogger(), repo, nil, outboxCfg)
+		n, err := relay.Cleanup(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("deleted %d events\n", n)
+		return nil
+
+	default:
+		return errors.New(outboxUsage)
+	}
+}
+
+// newOutboxConfig checks cfg before it reaches the relay, which would spin
+// on a zero batch size and panic on a zero interval.
+func newOutboxConfig(cfg config.Outbox) (outbox.Config, error) {
+	if cfg.BatchSize <= 0 || cfg.CleanupBatchSize <= 0 {
+		return outbox.Config{}, errors.New("outbox batch sizes must be positive")
+	}
+	if cfg.PollInterval <= 0 || cfg.CleanupInterval <= 0 || cfg.ClaimTTL <= 0 {
+		return outbox.Config{}, errors.New("outbox poll interval, cleanup interval and claim ttl must be positive")
+	}
+	if cfg.MaxAttempts <= 0 {
+		return outbox.Config{}, fmt.Errorf("outbox max attempts must be positive, got %d", cfg.MaxAttempts)
+	}
+	if cfg.BaseBackoff <= 0 || cfg.MaxBackoff < cfg.BaseBackoff {
+		return outbox.Config{}, errors.New("outbox backoff must be positive with max backoff at least base backoff")
+	}
+	if cfg.Retention < 0 {
+		return outbox.Config{}, fmt.Errorf("outbox retention must not be negative, got %s", cfg.Retention)
+	}
+
+	return outbox.Config{
+		BatchSize:        cfg.BatchSize,
+		PollInterval:     cfg.PollInterval,
+		MaxAttempts:      cfg.MaxAttempts,
+		BaseBackoff:      cfg.BaseBackoff,
+		MaxBackoff:       cfg.MaxBackoff,
+		ClaimTTL:         cfg.ClaimTTL,
+		Retention:        cfg.Retention,
+		CleanupInterval:  cfg.CleanupInterval,
+		CleanupBatchSize: cfg.CleanupBatchSize,
+	}, nil
+}
+
+// redactPayload hides the token fields of an event's data, such as sealed
+// reset tokens, so that they do not end up in terminals or tickets.
+func redactPayload(payload []byte) string {
+	var envelope map[string]any
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return "<invalid payload>"
+	}
+
+	if data, ok := envelope["data"].(map[string]any); ok {
+		for key := range data {
+			if strings.Contains(key, "token") {
+				data[key] = "[redacted]"
+			}
+		}
+	}
+
+	redacted, err := json.Marshal(envelope)
+	if err != nil {
+		return "<invalid payload>"
+	}
+
+	return string(redacted)
+}
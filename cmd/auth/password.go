@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Tbits007/auth/internal/config"
+	"github.com/Tbits007/auth/internal/lib/password"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func newPasswordPolicy(cfg config.PasswordPolicy) (*password.Policy, error) {
+	var breached *password.BreachedList
+	if cfg.BreachedListPath != "" {
+		list, err := password.LoadBreachedList(cfg.BreachedListPath)
+		if err != nil {
+			return nil, err
+		}
+		breached = list
+	}
+
+	return password.NewPolicy(password.Config{
+		MinLength:        cfg.MinLength,
+		MaxLength:        cfg.MaxLength,
+		RequireLowercase: cfg.RequireLowercase,
+		RequireUppercase: cfg.RequireUppercase,
+		RequireDigit:     cfg.RequireDigit,
+		RequireSymbol:    cfg.RequireSymbol,
+	}, breached)
+}
+
+func newPasswordHasher(cfg config.PasswordHashing) (*password.Hasher, error) {
+	if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("bcrypt cost must be between %d and %d, got %d", bcrypt.MinCost, bcrypt.MaxCost, cfg.BcryptCost)
+	}
+	if cfg.Argon2Memory == 0 || cfg.Argon2Iterations == 0 || cfg.Argon2Parallelism == 0 {
+		return nil, fmt.Errorf("argon2id memory, iterations and parallelism must be positive")
+	}
+
+	bcryptAlg := password.Bcrypt{Cost: cfg.BcryptCost}
+	argon2Alg := password.Argon2id{
+		Memory:      cfg.Argon2Memory,
+		Iterations:  cfg.Argon2Iterations,
+		Parallelism: cfg.Argon2Parallelism,
+		SaltLength:  16,
+		KeyLength:   32,
+	}
+
+	switch cfg.Algorithm {
+	case "bcrypt":
+		return password.NewHasher(bcryptAlg, argon2Alg), nil
+	case "argon2id":
+		return password.NewHasher(argon2Alg, bcryptAlg), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hashing algorithm %q", cfg.Algorithm)
+	}
+}
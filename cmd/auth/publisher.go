@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Tbits007/auth/internal/config"
+	"github.com/Tbits007/auth/internal/publishers/logPublisher"
+	"github.com/Tbits007/auth/internal/publishers/redisStreamPublisher"
+	"github.com/Tbits007/auth/internal/publishers/webhookPublisher"
+	"github.com/Tbits007/auth/internal/services/outbox"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	publisherLog     = "log"
+	publisherRedis   = "redis"
+	publisherWebhook = "webhook"
+)
+
+// newPublisher selects the outbox sink configured in cfg.Publisher.
+func newPublisher(log *slog.Logger, rdb *redis.Client, cfg config.Outbox) (outbox.Publisher, error) {
+	switch cfg.Publisher {
+	case publisherLog:
+		return logPublisher.NewLogPublisher(log), nil
+	case publisherRedis:
+		return redisStreamPublisher.NewRedisStreamPublisher(rdb, cfg.RedisStream.Stream, cfg.RedisStream.MaxLen), nil
+	case publisherWebhook:
+		if cfg.Webhook.URL == "" {
+			return nil, errors.New("outbox webhook url is not set")
+		}
+		return webhookPublisher.NewWebhookPublisher(cfg.Webhook.URL, cfg.Webhook.Timeout), nil
+	default:
+		return nil, fmt.Errorf("unknown outbox publisher %q", cfg.Publisher)
+	}
+}
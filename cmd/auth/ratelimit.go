@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/Tbits007/auth/internal/config"
+	"github.com/Tbits007/auth/internal/lib/ratelimiter"
+)
+
+func newRateLimitPolicy(cfg config.RateLimit) (ratelimiter.Policy, error) {
+	methods := make(map[string]ratelimiter.Limit, len(cfg.Methods))
+	for method, rule := range cfg.Methods {
+		methods[method] = rateLimit(rule)
+	}
+
+	policy := ratelimiter.Policy{
+		Default:   rateLimit(cfg.Default),
+		Methods:   methods,
+		OnFailure: ratelimiter.FailurePolicy(cfg.OnFailure),
+	}
+
+	return policy, policy.Validate()
+}
+
+func rateLimit(rule config.RateLimitRule) ratelimiter.Limit {
+	return ratelimiter.Limit{
+		Rate:   rule.Rate,
+		Burst:  rule.Burst,
+		Period: rule.Period,
+	}
+}
@@ -6,11 +6,24 @@ import (
 	"time"
 
 	"github.com/Tbits007/auth/internal/app/grpcapp"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/lib/passkey"
+	"github.com/Tbits007/auth/internal/lib/password"
 	"github.com/Tbits007/auth/internal/lib/ratelimiter"
+	"github.com/Tbits007/auth/internal/lib/secretbox"
+	"github.com/Tbits007/auth/internal/services/admin"
 	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/authz"
+	"github.com/Tbits007/auth/internal/services/outbox"
 	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
 	"github.com/Tbits007/auth/internal/storage/postgres/userRepo"
 	"github.com/Tbits007/auth/internal/storage/postgres/eventRepo"
+	"github.com/Tbits007/auth/internal/storage/postgres/mfaRepo"
+	"github.com/Tbits007/auth/internal/storage/postgres/passkeyRepo"
+	"github.com/Tbits007/auth/internal/storage/postgres/refreshTokenRepo"
+	"github.com/Tbits007/auth/internal/storage/postgres/relationRepo"
+	"github.com/Tbits007/auth/internal/storage/postgres/sessionRepo"
+	"github.com/Tbits007/auth/internal/storage/postgres/userTokenRepo"
 	"github.com/Tbits007/auth/internal/storage/redis_"
 	"github.com/go-redis/redis_rate/v10"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -19,7 +32,8 @@ import (
 )
 
 type App struct {
-	GRPCServer *grpcapp.GRPCApp
+	GRPCServer  *grpcapp.GRPCApp
+	OutboxRelay *outbox.Relay
 }
 
 func NewApp(
@@ -27,9 +41,20 @@ func NewApp(
 	db 		  		*pgxpool.Pool,
 	rdb		  		*redis.Client,
 	rateLimit 		*redis_rate.Limiter,
-	secretKey  		 string,
+	rateLimitPolicy ratelimiter.Policy,
+	apiKeys         map[string]string,
+	tokenIssuer 	*jwt.Issuer,
+	passwordPolicy  *password.Policy,
+	passwordHasher  *password.Hasher,
+	secretBox       *secretbox.Box,
+	tokenBox        *secretbox.Box,
+	relyingParty    *passkey.RelyingParty,
 	grpcPort   		 int,
-	tokenTTL   		 time.Duration,
+	trustProxyHeaders bool,
+	authCfg          auth.Config,
+	checkCacheTTL    time.Duration,
+	outboxCfg        outbox.Config,
+	publisher        outbox.Publisher,
 	metricsServer	*http.Server,
 	reg				*prometheus.Registry,
 ) *App {
@@ -38,27 +63,86 @@ func NewApp(
 	userRepo := userRepo.NewUserRepo(db)
 	eventRepo := eventRepo.NewEventRepo(db)
 	cacheRepo := redis_.NewCacheRepo(rdb)
+	refreshTokenRepo := refreshTokenRepo.NewRefreshTokenRepo(db)
+	userTokenRepo := userTokenRepo.NewUserTokenRepo(db)
 	rateLimiter := ratelimiter.NewLimiter(rateLimit)
 	authService := auth.NewAuthService(
+		log,
+		auth.AuthDeps{
+			TxManager:        txManager,
+			UserRepo:         userRepo,
+			EventRepo:        eventRepo,
+			CacheRepo:        cacheRepo,
+			RefreshTokenRepo: refreshTokenRepo,
+			UserTokenRepo:    userTokenRepo,
+			RateLimiter:      redis_.NewAttemptLimiter(rateLimiter),
+			LoginAttemptRepo: redis_.NewLoginAttemptRepo(rdb),
+			MFARepo:          mfaRepo.NewMFARepo(db),
+			SecretBox:        secretBox,
+			TokenBox:         tokenBox,
+			PasskeyRepo:      passkeyRepo.NewPasskeyRepo(db),
+			RelyingParty:     relyingParty,
+			SessionRepo:      sessionRepo.NewSessionRepo(db),
+			TokenIssuer:      tokenIssuer,
+			PasswordPolicy:   passwordPolicy,
+			PasswordHasher:   passwordHasher,
+		},
+		authCfg,
+	)
+
+	rateLimitInterceptor := ratelimiter.UnaryServerInterceptor(
+		log,
+		rateLimitPolicy,
+		rateLimiter,
+		ratelimiter.NewLocalLimiter(ratelimiter.DefaultMaxKeys),
+		ratelimiter.ClientIdentity(apiKeys, func(token string) (string, error) {
+			claims, err := tokenIssuer.ParseToken(token)
+			if err != nil {
+				return "", err
+			}
+			return claims.UserID.String(), nil
+		}),
+	)
+
+	authzService := authz.NewAuthzService(
+		log,
+		relationRepo.NewRelationRepo(db),
+		cacheRepo,
+		authService,
+		checkCacheTTL,
+	)
+
+	adminService := admin.NewAdminService(
 		log,
 		txManager,
 		userRepo,
 		eventRepo,
 		cacheRepo,
-		tokenTTL,
-		secretKey,
+		authService,
+		authService,
 	)
 
 	grpcApp := grpcapp.NewGRPCApp(
 		log,
-		rateLimiter,
+		rateLimitInterceptor,
 		authService,
+		authzService,
+		adminService,
         metricsServer,
         reg,
 		grpcPort,
+		trustProxyHeaders,
+	)
+
+	outboxRelay := outbox.NewRelay(
+		log,
+		eventRepo,
+		publisher,
+		outboxCfg,
 	)
 
 	return &App{
-		GRPCServer: grpcApp,
+		GRPCServer:  grpcApp,
+		OutboxRelay: outboxRelay,
 	}
 }
\ No newline at end of file
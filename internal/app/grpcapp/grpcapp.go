@@ -8,12 +8,15 @@ import (
 	"net/http"
 	"sync"
 
+	"github.com/Tbits007/auth/internal/handlers/grpc/admin"
 	"github.com/Tbits007/auth/internal/handlers/grpc/auth"
+	"github.com/Tbits007/auth/internal/handlers/http/jwks"
+	"github.com/Tbits007/auth/internal/lib/clientip"
 	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/requestid"
+	"github.com/Tbits007/auth/internal/lib/useragent"
 	grpcprom "github.com/grpc-ecosystem/go-grpc-middleware/providers/prometheus"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
-	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/ratelimit"
-
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -28,28 +31,35 @@ func InterceptorLogger(l *slog.Logger) logging.Logger {
 	})
 }
 
+// loggingInterceptor logs the start and outcome of every call. Payloads are
+// never logged: requests and responses carry passwords, tokens, TOTP secrets
+// and recovery codes.
+func loggingInterceptor(l *slog.Logger) grpc.UnaryServerInterceptor {
+	return logging.UnaryServerInterceptor(InterceptorLogger(l),
+		logging.WithLogOnEvents(logging.StartCall, logging.FinishCall),
+	)
+}
+
 type GRPCApp struct {
     log             *slog.Logger
     gRPCServer      *grpc.Server
     metricsServer   *http.Server
+    jwksHandler      http.Handler
     reg             *prometheus.Registry
     port             int 
 } 
 
 func NewGRPCApp(
     log           *slog.Logger, 
-    rateLimiter    ratelimit.Limiter, 
+    rateLimiter    grpc.UnaryServerInterceptor,
     authService    auth.AuthService,
+    authzService   auth.AuthzService,
+    adminService   admin.AdminService,
     metricsServer *http.Server,
     reg           *prometheus.Registry,
     port           int,
+    trustProxyHeaders bool,
 ) *GRPCApp {
-    loggingOpts := []logging.Option{
-        logging.WithLogOnEvents(
-            logging.PayloadReceived, logging.PayloadSent,
-        ),
-    }    
-
     recoveryOpts := []recovery.Option{
         recovery.WithRecoveryHandler(func(p any) (err error) {
             log.Error("Recovered from panic", slog.Any("panic", p))
@@ -67,18 +77,23 @@ func NewGRPCApp(
     )
 
     gRPCServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+        requestid.UnaryServerInterceptor(),
+        clientip.UnaryServerInterceptor(trustProxyHeaders),
+        useragent.UnaryServerInterceptor(),
         srvMetrics.UnaryServerInterceptor(),
-        logging.UnaryServerInterceptor(InterceptorLogger(log), loggingOpts...),
+        loggingInterceptor(log),
         recovery.UnaryServerInterceptor(recoveryOpts...),   
-        // ratelimit.UnaryServerInterceptor(rateLimiter),  # depends on Redis
+        rateLimiter,
     ))
 
-    auth.NewAuthServer(gRPCServer, authService)
+    auth.NewAuthServer(gRPCServer, authService, authzService)
+    admin.NewAdminServer(gRPCServer, adminService)
 
     return &GRPCApp{
         log:           log,
         gRPCServer:    gRPCServer,
         metricsServer: metricsServer,
+        jwksHandler:   jwks.NewHandler(authService),
         reg:           reg,
         port:          port,
     }    
@@ -108,6 +123,7 @@ func (ga *GRPCApp) Run() error {
 				EnableOpenMetrics: true,
 			},
 		))
+		m.Handle(jwks.Path, ga.jwksHandler)
 		ga.metricsServer.Handler = m
 		ga.log.Info("starting HTTP server for prometheus")
 		if err := ga.metricsServer.ListenAndServe(); err != nil {
@@ -132,7 +148,7 @@ func (ga *GRPCApp) Stop(shutdownCtx context.Context) {
 
     done := make(chan struct{})
 
-    var wg *sync.WaitGroup
+    var wg sync.WaitGroup
     wg.Add(2)
 
     go func() {
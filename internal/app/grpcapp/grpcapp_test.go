@@ -0,0 +1,28 @@
+package grpcapp
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestLoggingInterceptor_OmitsPayloads(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	req := wrapperspb.String("request-secret-password")
+	info := &grpc.UnaryServerInfo{FullMethod: "/auth.Auth/Login"}
+	_, err := loggingInterceptor(log)(context.Background(), req, info, func(ctx context.Context, req any) (any, error) {
+		return wrapperspb.String("response-secret-token"), nil
+	})
+
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "finished call")
+	assert.NotContains(t, buf.String(), "request-secret-password")
+	assert.NotContains(t, buf.String(), "response-secret-token")
+}
@@ -17,15 +17,120 @@ type Config struct {
 	Postgres    Postgres   	  `yaml:"postgres"`
 	Redis       Redis		  `yaml:"redis"`
 	Auth	 	Auth 		  `yaml:"auth"`	
+	Outbox      Outbox        `yaml:"outbox"`
+	Authz       Authz         `yaml:"authz"`
+	RateLimit   RateLimit     `yaml:"rate_limit"`
 }
 
 type Auth struct {
-	TokenTTL 	time.Duration `yaml:"tokenTTL"`	
-	SecretKey 	string		  `yaml:"secretKey"`
+	TokenTTL 	    time.Duration `yaml:"tokenTTL"`	
+	RefreshTokenTTL time.Duration `yaml:"refreshTokenTTL" env-default:"720h"`
+	SecretKey 	    string		  `yaml:"secretKey"`
+	SigningAlgorithm string       `yaml:"signingAlgorithm" env-default:"HS256"`
+	PrivateKeyPath  string        `yaml:"privateKeyPath"`
+	KeyID           string        `yaml:"keyID" env-default:"default"`
+	KeyringPath     string        `yaml:"keyringPath"`
+	Issuer          string        `yaml:"issuer" env-default:"auth"`
+	Audience        []string      `yaml:"audience"`
+	VerificationTokenTTL     time.Duration `yaml:"verificationTokenTTL" env-default:"24h"`
+	PasswordResetTokenTTL    time.Duration `yaml:"passwordResetTokenTTL" env-default:"1h"`
+	// TokenEncryptionKey is the base64 encoded 32-byte key that mailed
+	// tokens are sealed with in outbox events; the mailer opens them with
+	// the same key.
+	TokenEncryptionKey       string        `yaml:"tokenEncryptionKey"`
+	ResendVerificationLimit  int           `yaml:"resendVerificationLimit" env-default:"3"`
+	ResendVerificationPeriod time.Duration `yaml:"resendVerificationPeriod" env-default:"1h"`
+	// UserCacheTTL bounds how long account status and admin flags are
+	// cached in Redis; 0 disables the cache.
+	UserCacheTTL             time.Duration `yaml:"userCacheTTL" env-default:"5m"`
+	PasswordPolicy           PasswordPolicy `yaml:"passwordPolicy"`
+	PasswordHashing          PasswordHashing `yaml:"passwordHashing"`
+	LoginLockout             LoginLockout    `yaml:"loginLockout"`
+	MFA                      MFA             `yaml:"mfa"`
+	Passkey                  Passkey         `yaml:"passkey"`
+}
+
+type PasswordPolicy struct {
+	MinLength        int    `yaml:"minLength" env-default:"8"`
+	MaxLength        int    `yaml:"maxLength" env-default:"64"`
+	RequireLowercase bool   `yaml:"requireLowercase"`
+	RequireUppercase bool   `yaml:"requireUppercase"`
+	RequireDigit     bool   `yaml:"requireDigit"`
+	RequireSymbol    bool   `yaml:"requireSymbol"`
+	// BreachedListPath points to a file of SHA-1 digests of common or
+	// breached passwords; see password.LoadBreachedList.
+	BreachedListPath string `yaml:"breachedListPath"`
+}
+
+// PasswordHashing selects the algorithm new password hashes use. Hashes
+// of the other algorithm still verify and are upgraded on login.
+type PasswordHashing struct {
+	Algorithm         string `yaml:"algorithm" env-default:"bcrypt"`
+	BcryptCost        int    `yaml:"bcryptCost" env-default:"10"`
+	Argon2Memory      uint32 `yaml:"argon2Memory" env-default:"65536"`
+	Argon2Iterations  uint32 `yaml:"argon2Iterations" env-default:"3"`
+	Argon2Parallelism uint8  `yaml:"argon2Parallelism" env-default:"2"`
+}
+
+// LoginLockout locks out logins for an email or a client IP after
+// repeated failures; see auth.Config. A zero threshold disables it.
+type LoginLockout struct {
+	Threshold   int           `yaml:"threshold" env-default:"5"`
+	IPThreshold int           `yaml:"ipThreshold" env-default:"20"`
+	Window      time.Duration `yaml:"window" env-default:"15m"`
+	Duration    time.Duration `yaml:"duration" env-default:"1m"`
+	MaxDuration time.Duration `yaml:"maxDuration" env-default:"1h"`
+}
+
+// MFA configures TOTP second factors. EncryptionKey is the base64 encoded
+// 32-byte key TOTP secrets are encrypted with at rest; changing it makes
+// existing enrollments unusable.
+type MFA struct {
+	Issuer        string        `yaml:"issuer" env-default:"auth"`
+	EncryptionKey string        `yaml:"encryptionKey"`
+	ChallengeTTL  time.Duration `yaml:"challengeTTL" env-default:"5m"`
+	MaxAttempts   int           `yaml:"maxAttempts" env-default:"5"`
+	RecoveryCodes int           `yaml:"recoveryCodes" env-default:"10"`
+}
+
+// Passkey configures WebAuthn. RPID is the domain passkeys are bound to
+// and RPOrigins the origins the browser may report; changing RPID makes
+// existing passkeys unusable.
+type Passkey struct {
+	RPID          string        `yaml:"rpID" env-default:"localhost"`
+	RPDisplayName string        `yaml:"rpDisplayName" env-default:"auth"`
+	RPOrigins     []string      `yaml:"rpOrigins" env-default:"http://localhost"`
+	ChallengeTTL  time.Duration `yaml:"challengeTTL" env-default:"5m"`
+}
+
+// RateLimit limits gRPC calls per method and client. Methods maps full
+// method names such as "/auth.Auth/Login" to their limit; the others get
+// Default. A zero rate disables the limit.
+type RateLimit struct {
+	Default   RateLimitRule            `yaml:"default"`
+	Methods   map[string]RateLimitRule `yaml:"methods"`
+	// OnFailure is "open", "closed" or "local"; see ratelimiter.FailurePolicy.
+	OnFailure string                   `yaml:"onFailure" env-default:"local"`
+	// APIKeys maps client names to the keys they send in x-api-key, so
+	// that each gets its own limit.
+	APIKeys   map[string]string        `yaml:"apiKeys"`
+}
+
+type RateLimitRule struct {
+	Rate   int           `yaml:"rate" env-default:"20"`
+	Burst  int           `yaml:"burst" env-default:"40"`
+	Period time.Duration `yaml:"period" env-default:"1s"`
+}
+
+type Authz struct {
+	CheckCacheTTL time.Duration `yaml:"check_cache_ttl" env-default:"5m"`
 }
 
 type GRPCServer struct {  
     Port    int           `yaml:"port"`  
+    // TrustProxyHeaders takes the client IP from x-forwarded-for. Only
+    // enable it behind a proxy that sets the header.
+    TrustProxyHeaders bool `yaml:"trustProxyHeaders"`
 }
 
 type Postgres struct {
@@ -44,6 +149,31 @@ type Redis struct {
 	Timeout     time.Duration `yaml:"timeout"`
 }
 
+type Outbox struct {
+	BatchSize        int           `yaml:"batch_size" env-default:"100"`
+	PollInterval     time.Duration `yaml:"poll_interval" env-default:"1s"`
+	MaxAttempts      int           `yaml:"max_attempts" env-default:"10"`
+	BaseBackoff      time.Duration `yaml:"base_backoff" env-default:"1s"`
+	MaxBackoff       time.Duration `yaml:"max_backoff" env-default:"5m"`
+	ClaimTTL         time.Duration `yaml:"claim_ttl" env-default:"5m"`
+	Retention        time.Duration `yaml:"retention" env-default:"168h"`
+	CleanupInterval  time.Duration `yaml:"cleanup_interval" env-default:"1h"`
+	CleanupBatchSize int           `yaml:"cleanup_batch_size" env-default:"1000"`
+	Publisher        string        `yaml:"publisher" env-default:"log"`
+	RedisStream      RedisStream   `yaml:"redis_stream"`
+	Webhook          Webhook       `yaml:"webhook"`
+}
+
+type RedisStream struct {
+	Stream string `yaml:"stream" env-default:"auth.events"`
+	MaxLen int64  `yaml:"max_len" env-default:"100000"`
+}
+
+type Webhook struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout" env-default:"5s"`
+}
+
 func MustLoad() *Config {
 	projectRoot := getProjectRoot()
 	envPath := filepath.Join(projectRoot, ".env")
@@ -0,0 +1,71 @@
+package eventModel
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	SpecVersion     = "1.0"
+	Source          = "auth"
+	DataContentType = "application/json"
+)
+
+// Envelope is the CloudEvents (structured mode) representation stored in
+// Event.Payload. ID equals the outbox row ID and doubles as the
+// idempotency key for consumers.
+type Envelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              uuid.UUID       `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	CorrelationID   string          `json:"correlationid,omitempty"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// NewEvent wraps data into an Envelope and returns a pending outbox event
+// of the given type. subject is the user the event is about and may be
+// uuid.Nil when the user is unknown.
+func NewEvent(
+	eventType string,
+	subject uuid.UUID,
+	correlationID string,
+	occurredAt time.Time,
+	data any,
+) (Event, error) {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	envelope := Envelope{
+		SpecVersion:     SpecVersion,
+		ID:              uuid.New(),
+		Source:          Source,
+		Type:            eventType,
+		Time:            occurredAt.UTC(),
+		DataContentType: DataContentType,
+		CorrelationID:   correlationID,
+		Data:            dataBytes,
+	}
+	if subject != uuid.Nil {
+		envelope.Subject = subject.String()
+	}
+
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:        envelope.ID,
+		EventType: eventType,
+		Payload:   payload,
+		Status:    PENDING,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package eventModel
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEvent_Envelope(t *testing.T) {
+	userID := uuid.New()
+	occurredAt := time.Date(2025, 5, 20, 9, 15, 44, 0, time.UTC)
+
+	event, err := NewEvent(UserRegisteredV1, userID, "req-1", occurredAt, UserRegistered{
+		UserID: userID,
+		Email:  "test@example.com",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, UserRegisteredV1, event.EventType)
+	assert.Equal(t, PENDING, event.Status)
+
+	var envelope Envelope
+	require.NoError(t, json.Unmarshal(event.Payload, &envelope))
+	assert.Equal(t, SpecVersion, envelope.SpecVersion)
+	assert.Equal(t, event.ID, envelope.ID)
+	assert.Equal(t, UserRegisteredV1, envelope.Type)
+	assert.Equal(t, userID.String(), envelope.Subject)
+	assert.Equal(t, "req-1", envelope.CorrelationID)
+	assert.True(t, occurredAt.Equal(envelope.Time))
+
+	var data UserRegistered
+	require.NoError(t, json.Unmarshal(envelope.Data, &data))
+	assert.Equal(t, userID, data.UserID)
+	assert.Equal(t, "test@example.com", data.Email)
+}
+
+func TestNewEvent_NoSubject(t *testing.T) {
+	event, err := NewEvent(UserLoginFailedV1, uuid.Nil, "", time.Now(), UserLoginFailed{
+		Email:  "test@example.com",
+		Reason: LoginFailedUnknownUser,
+	})
+	require.NoError(t, err)
+
+	assert.NotContains(t, string(event.Payload), `"subject"`)
+	assert.NotContains(t, string(event.Payload), `"user_id"`)
+}
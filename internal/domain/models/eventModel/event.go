@@ -1,6 +1,10 @@
 package eventModel
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 
 type EventStatus string
@@ -16,4 +20,7 @@ type Event struct {
 	EventType    string
 	Payload 	 []byte
 	Status       EventStatus 
-}
\ No newline at end of file
+	Attempts     int
+	LastError    string
+	CreatedAt    time.Time
+}
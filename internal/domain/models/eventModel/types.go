@@ -0,0 +1,145 @@
+package eventModel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types are versioned; a breaking change to a data struct gets a new
+// type (and struct) rather than changing the existing one.
+const (
+	UserRegisteredV1    = "user.registered.v1"
+	UserLoggedInV1      = "user.logged_in.v1"
+	UserLoginFailedV1   = "user.login_failed.v1"
+	UserLoggedOutV1     = "user.logged_out.v1"
+	UserRoleAssignedV1  = "user.role_assigned.v1"
+	UserRoleRevokedV1   = "user.role_revoked.v1"
+	UserUpdatedV1       = "user.updated.v1"
+	UserStatusChangedV1 = "user.status_changed.v1"
+	UserLockedV1        = "user.locked.v1"
+	UserMFAEnabledV1    = "user.mfa_enabled.v1"
+
+	UserPasskeyRegisteredV1 = "user.passkey_registered.v1"
+	UserSessionsRevokedV1   = "user.sessions_revoked.v1"
+
+	UserVerificationRequestedV1  = "user.verification_requested.v1"
+	UserPasswordResetRequestedV1 = "user.password_reset_requested.v1"
+	UserPasswordResetV1          = "user.password_reset.v1"
+	UserPasswordChangedV1        = "user.password_changed.v1"
+)
+
+const (
+	LoginFailedUnknownUser     = "unknown_user"
+	LoginFailedInvalidPassword = "invalid_password"
+	LoginFailedInvalidMFACode  = "invalid_mfa_code"
+	LoginFailedInvalidPasskey  = "invalid_passkey"
+)
+
+const (
+	MFAMethodTOTP = "totp"
+)
+
+type UserRegistered struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+type UserLoggedIn struct {
+	UserID uuid.UUID `json:"user_id"`
+	Email  string    `json:"email"`
+}
+
+type UserLoginFailed struct {
+	UserID *uuid.UUID `json:"user_id,omitempty"`
+	Email  string     `json:"email"`
+	Reason string     `json:"reason"`
+}
+
+// UserLocked records a temporary login lockout after repeated failures.
+// UserID is unset when the email belongs to no account.
+type UserLocked struct {
+	UserID      *uuid.UUID `json:"user_id,omitempty"`
+	Email       string     `json:"email"`
+	Failures    int        `json:"failures"`
+	LockedUntil time.Time  `json:"locked_until"`
+}
+
+type UserMFAEnabled struct {
+	UserID uuid.UUID `json:"user_id"`
+	Method string    `json:"method"`
+}
+
+// UserPasskeyRegistered refers to the passkey by the ID of its stored
+// credential, not the WebAuthn credential ID.
+type UserPasskeyRegistered struct {
+	UserID    uuid.UUID `json:"user_id"`
+	PasskeyID uuid.UUID `json:"passkey_id"`
+}
+
+type UserLoggedOut struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// UserSessionsRevoked records the revocation of one session, or of all of
+// the user's sessions when SessionID is nil. ActorID is set when an admin
+// revoked them.
+type UserSessionsRevoked struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	SessionID *uuid.UUID `json:"session_id,omitempty"`
+	ActorID   *uuid.UUID `json:"actor_id,omitempty"`
+}
+
+// UserRoleChanged is the data of both UserRoleAssignedV1 and
+// UserRoleRevokedV1.
+type UserRoleChanged struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Role    string    `json:"role"`
+	ActorID uuid.UUID `json:"actor_id"`
+}
+
+// UserUpdated lists only the fields an admin changed.
+type UserUpdated struct {
+	UserID  uuid.UUID `json:"user_id"`
+	Email   *string   `json:"email,omitempty"`
+	IsAdmin *bool     `json:"is_admin,omitempty"`
+	ActorID uuid.UUID `json:"actor_id"`
+}
+
+// UserStatusChanged records every account status transition. ActorID is
+// set when an admin made the change.
+type UserStatusChanged struct {
+	UserID  uuid.UUID  `json:"user_id"`
+	From    string     `json:"from"`
+	To      string     `json:"to"`
+	ActorID *uuid.UUID `json:"actor_id,omitempty"`
+}
+
+// UserVerificationRequested carries the verification token the mailer
+// builds the link from, sealed like the one of UserPasswordResetRequested.
+// The token is single-use and expires at ExpiresAt.
+type UserVerificationRequested struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	EncryptedToken []byte    `json:"encrypted_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// UserPasswordResetRequested carries the reset token sealed with the token
+// encryption key (secretbox, with the user ID as additional data), so that
+// it is never stored or published in clear.
+type UserPasswordResetRequested struct {
+	UserID         uuid.UUID `json:"user_id"`
+	Email          string    `json:"email"`
+	EncryptedToken []byte    `json:"encrypted_token"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+type UserPasswordReset struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+type UserPasswordChanged struct {
+	UserID          uuid.UUID `json:"user_id"`
+	SessionsRevoked bool      `json:"sessions_revoked"`
+}
@@ -0,0 +1,35 @@
+package mfaModel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTP is the authenticator app enrollment of a user. It takes effect
+// once confirmed with a first valid code. LastUsedStep is the time step
+// of the last accepted code, which may not be used again.
+type TOTP struct {
+	UserID          uuid.UUID
+	EncryptedSecret []byte
+	LastUsedStep    int64
+	CreatedAt       time.Time
+	ConfirmedAt     *time.Time
+}
+
+// Enrollment is what a user needs to add a TOTP secret to an
+// authenticator app.
+type Enrollment struct {
+	Secret string
+	URI    string
+}
+
+// Challenge is a login that passed the password check and awaits a
+// second factor. EnrollmentRequired is set for accounts that must enroll
+// MFA before they may log in.
+type Challenge struct {
+	ID                 string    `json:"-"`
+	UserID             uuid.UUID `json:"user_id"`
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ExpiresAt          time.Time `json:"expires_at"`
+}
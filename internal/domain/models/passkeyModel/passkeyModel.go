@@ -0,0 +1,23 @@
+package passkeyModel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Credential is a WebAuthn public key credential registered by a user.
+// SignCount is the last signature counter the authenticator reported;
+// authenticators that keep no counter always report zero.
+type Credential struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	CredentialID    []byte
+	PublicKey       []byte
+	AttestationType string
+	AAGUID          []byte
+	SignCount       uint32
+	Transports      []string
+	CreatedAt       time.Time
+	LastUsedAt      *time.Time
+}
@@ -0,0 +1,79 @@
+package relationModel
+
+import "strings"
+
+const (
+	RelationOwner  = "owner"
+	RelationEditor = "editor"
+	RelationViewer = "viewer"
+)
+
+// Tuple states that Subject has Relation to Object. Objects and subjects
+// are "type:id" strings, e.g. "organization:42" and "user:<uuid>".
+type Tuple struct {
+	Object   string
+	Relation string
+	Subject  string
+}
+
+// implies lists the relations each relation directly grants.
+var implies = map[string][]string{
+	RelationOwner:  {RelationEditor},
+	RelationEditor: {RelationViewer},
+}
+
+// Implied returns relation followed by every relation it grants through
+// the rewrite rules, e.g. owner -> owner, editor, viewer.
+func Implied(relation string) []string {
+	result := []string{relation}
+	for i := 0; i < len(result); i++ {
+		result = append(result, implies[result[i]]...)
+	}
+
+	return result
+}
+
+// Granting returns relation followed by every relation that grants it,
+// e.g. viewer -> viewer, editor, owner. A Check for relation succeeds if
+// the subject holds any of them.
+func Granting(relation string) []string {
+	result := []string{relation}
+	for i := 0; i < len(result); i++ {
+		for parent, children := range implies {
+			for _, child := range children {
+				if child == result[i] {
+					result = append(result, parent)
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// Valid reports whether t has a known relation and object and subject
+// are "type:id" strings. References may not contain '#' or '@', which
+// separate the parts of a tuple written as object#relation@subject.
+func (t Tuple) Valid() bool {
+	return knownRelation(t.Relation) && validRef(t.Object) && validRef(t.Subject)
+}
+
+// knownRelation reports whether relation is one of the relations the
+// rewrite rules know about.
+func knownRelation(relation string) bool {
+	switch relation {
+	case RelationOwner, RelationEditor, RelationViewer:
+		return true
+	}
+
+	return false
+}
+
+func validRef(ref string) bool {
+	if strings.ContainsAny(ref, "#@") {
+		return false
+	}
+
+	kind, id, found := strings.Cut(ref, ":")
+	return found && kind != "" && id != ""
+}
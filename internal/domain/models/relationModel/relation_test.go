@@ -0,0 +1,31 @@
+package relationModel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImplied(t *testing.T) {
+	assert.Equal(t, []string{RelationOwner, RelationEditor, RelationViewer}, Implied(RelationOwner))
+	assert.Equal(t, []string{RelationEditor, RelationViewer}, Implied(RelationEditor))
+	assert.Equal(t, []string{RelationViewer}, Implied(RelationViewer))
+	assert.Equal(t, []string{"member"}, Implied("member"))
+}
+
+func TestGranting(t *testing.T) {
+	assert.Equal(t, []string{RelationViewer, RelationEditor, RelationOwner}, Granting(RelationViewer))
+	assert.Equal(t, []string{RelationEditor, RelationOwner}, Granting(RelationEditor))
+	assert.Equal(t, []string{RelationOwner}, Granting(RelationOwner))
+}
+
+func TestTuple_Valid(t *testing.T) {
+	assert.True(t, Tuple{Object: "organization:42", Relation: RelationEditor, Subject: "user:1"}.Valid())
+	assert.False(t, Tuple{Object: "organization", Relation: RelationEditor, Subject: "user:1"}.Valid())
+	assert.False(t, Tuple{Object: "organization:42", Relation: "", Subject: "user:1"}.Valid())
+	assert.False(t, Tuple{Object: "organization:42", Relation: RelationEditor, Subject: ":1"}.Valid())
+	assert.False(t, Tuple{Object: "organization:42", Relation: "member", Subject: "user:1"}.Valid())
+	assert.False(t, Tuple{Object: "organization:42", Relation: "viewer@user", Subject: "user:1"}.Valid())
+	assert.False(t, Tuple{Object: "organization:42#viewer", Relation: RelationEditor, Subject: "user:1"}.Valid())
+	assert.False(t, Tuple{Object: "organization:42", Relation: RelationEditor, Subject: "user:1@x"}.Valid())
+}
@@ -0,0 +1,20 @@
+package roleModel
+
+// Roles and permissions seeded by the RBAC migration. New ones are added
+// with a migration; these constants only name the ones the code relies on.
+const (
+	RoleAdmin   = "admin"
+	RoleSupport = "support"
+	RoleBilling = "billing"
+)
+
+const (
+	PermUsersRead    = "users:read"
+	PermUsersWrite   = "users:write"
+	PermUsersDelete  = "users:delete"
+	PermRolesAssign  = "roles:assign"
+	PermBillingRead  = "billing:read"
+	PermBillingWrite = "billing:write"
+
+	PermRelationsWrite = "relations:write"
+)
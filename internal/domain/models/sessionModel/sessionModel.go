@@ -0,0 +1,23 @@
+package sessionModel
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a login on one device. It lives as long as its refresh token
+// family: each refresh moves LastSeenAt and ExpiresAt forward, and
+// revoking the session revokes the family and the access tokens issued
+// for it.
+type Session struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	FamilyID   uuid.UUID
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastSeenAt time.Time
+	ExpiresAt  time.Time
+	RevokedAt  *time.Time
+}
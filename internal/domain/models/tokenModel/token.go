@@ -0,0 +1,59 @@
+package tokenModel
+
+import (
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/mfaModel"
+
+	"github.com/google/uuid"
+)
+
+type RefreshToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	FamilyID  uuid.UUID
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+	RevokedAt *time.Time
+}
+
+type TokenPair struct {
+	AccessToken  string
+	RefreshToken string
+}
+
+// LoginResult holds the tokens of a completed login or, when the account
+// needs a second factor, the challenge to complete the login with.
+type LoginResult struct {
+	Tokens       TokenPair
+	MFAChallenge *mfaModel.Challenge
+}
+
+type Introspection struct {
+	Active    bool
+	UserID    uuid.UUID
+	Email     string
+	IsAdmin   bool
+	ExpiresAt time.Time
+	IssuedAt  time.Time
+}
+
+// Purposes of one-time user tokens.
+const (
+	PurposeEmailVerification = "email_verification"
+	PurposePasswordReset     = "password_reset"
+)
+
+// UserToken is a single-use token mailed to a user, such as an email
+// verification or password reset link. Only its hash is stored.
+type UserToken struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Purpose   string
+	TokenHash string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+	UsedAt    *time.Time
+}
@@ -1,11 +1,90 @@
 package userModel
 
-import "github.com/google/uuid"
+import (
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
+)
+
+// Status is the account lifecycle state. Only active accounts may log in
+// or use their tokens.
+type Status string
+
+const (
+	StatusPendingVerification Status = "pending_verification"
+	StatusActive              Status = "active"
+	StatusDisabled            Status = "disabled"
+	StatusDeleted             Status = "deleted"
+)
+
+// transitions lists the statuses each status may move to. Deleted is
+// terminal.
+var transitions = map[Status][]Status{
+	StatusPendingVerification: {StatusActive, StatusDisabled, StatusDeleted},
+	StatusActive:              {StatusDisabled, StatusDeleted},
+	StatusDisabled:            {StatusDeleted},
+	StatusDeleted:             {},
+}
+
+func (s Status) Valid() bool {
+	_, ok := transitions[s]
+	return ok
+}
+
+func (s Status) CanTransitionTo(to Status) bool {
+	for _, next := range transitions[s] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Sources returns the statuses that may move to to.
+func Sources(to Status) []Status {
+	var sources []Status
+	for _, from := range []Status{
+		StatusPendingVerification,
+		StatusActive,
+		StatusDisabled,
+		StatusDeleted,
+	} {
+		if from.CanTransitionTo(to) {
+			sources = append(sources, from)
+		}
+	}
+	return sources
+}
+
+// NormalizeEmail returns the canonical form of email, trimmed and
+// lowercased. Accounts are stored under it and looked up by it.
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
 
 type User struct {
 	ID uuid.UUID
 	Email string 
 	HashedPassword string 
 	IsAdmin bool 
-}
\ No newline at end of file
+	MFAEnabled bool
+	Status Status
+	CreatedAt time.Time
+}
+
+// ListFilter narrows ListUsers. Zero values disable a filter. After is the
+// (CreatedAt, ID) of the last user on the previous page.
+type ListFilter struct {
+	EmailPrefix   string
+	Status        Status
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	After         *Cursor
+	Limit         int
+}
+
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
@@ -0,0 +1,32 @@
+package userModel
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, StatusPendingVerification.CanTransitionTo(StatusActive))
+	assert.True(t, StatusActive.CanTransitionTo(StatusDisabled))
+	assert.True(t, StatusDisabled.CanTransitionTo(StatusDeleted))
+	assert.False(t, StatusActive.CanTransitionTo(StatusPendingVerification))
+	assert.False(t, StatusDisabled.CanTransitionTo(StatusActive))
+	assert.False(t, StatusDeleted.CanTransitionTo(StatusActive))
+	assert.False(t, Status("unknown").CanTransitionTo(StatusActive))
+}
+
+func TestSources(t *testing.T) {
+	assert.Equal(t, []Status{StatusPendingVerification, StatusActive}, Sources(StatusDisabled))
+	assert.Equal(t, []Status{StatusPendingVerification}, Sources(StatusActive))
+	assert.Empty(t, Sources(StatusPendingVerification))
+}
+
+func TestStatus_Valid(t *testing.T) {
+	assert.True(t, StatusDeleted.Valid())
+	assert.False(t, Status("").Valid())
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	assert.Equal(t, "user@example.com", NormalizeEmail(" User@Example.COM\n"))
+}
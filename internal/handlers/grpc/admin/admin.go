@@ -0,0 +1,324 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	authHandler "github.com/Tbits007/auth/internal/handlers/grpc/auth"
+	"github.com/Tbits007/auth/internal/lib/bearer"
+	"github.com/Tbits007/auth/internal/services/admin"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/storage"
+	au "github.com/Tbits007/contract/gen/go/auth"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type AdminService interface {
+	ListUsers(
+		ctx         context.Context,
+		accessToken string,
+		filter      userModel.ListFilter,
+		cursor      string,
+	) ([]userModel.User, string, error)
+
+	GetUser(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+	) (*userModel.User, error)
+
+	UpdateUser(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+		update      admin.UserUpdate,
+	) (*userModel.User, error)
+
+	DisableUser(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+	) error
+
+	DeleteUser(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+	) error
+
+	ListSessions(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+	) ([]sessionModel.Session, error)
+
+	RevokeSession(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+		sessionID   uuid.UUID,
+	) error
+
+	RevokeAllSessions(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+	) error
+}
+
+type AdminServer struct {
+	au.UnimplementedAdminServer
+	adminService AdminService
+}
+
+// NewAdminServer registers the AdminService on gRPCServer. Every RPC
+// requires a bearer token with admin rights in the "authorization" metadata.
+func NewAdminServer(
+	gRPCServer   *grpc.Server,
+	adminService AdminService,
+) {
+	au.RegisterAdminServer(
+		gRPCServer,
+		&AdminServer{adminService: adminService},
+	)
+}
+
+func (as *AdminServer) ListUsers(
+	ctx     context.Context,
+	request *au.ListUsersRequest,
+) (*au.ListUsersResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	if request.PageSize < 0 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must not be negative")
+	}
+
+	filter := userModel.ListFilter{
+		EmailPrefix: request.GetEmailPrefix(),
+		Status:      userModel.Status(request.GetStatus()),
+		Limit:       int(request.GetPageSize()),
+	}
+	if request.CreatedAfter != 0 {
+		filter.CreatedAfter = time.Unix(request.GetCreatedAfter(), 0)
+	}
+	if request.CreatedBefore != 0 {
+		filter.CreatedBefore = time.Unix(request.GetCreatedBefore(), 0)
+	}
+
+	users, next, err := as.adminService.ListUsers(ctx, accessToken, filter, request.GetPageToken())
+	if err != nil {
+		return nil, adminError(err, "failed to list users")
+	}
+
+	response := &au.ListUsersResponse{
+		Users:         make([]*au.AdminUser, 0, len(users)),
+		NextPageToken: next,
+	}
+	for i := range users {
+		response.Users = append(response.Users, toAdminUser(&users[i]))
+	}
+
+	return response, nil
+}
+
+func (as *AdminServer) GetUser(
+	ctx     context.Context,
+	request *au.GetUserRequest,
+) (*au.GetUserResponse, error) {
+	accessToken, userID, err := userRequest(ctx, request.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := as.adminService.GetUser(ctx, accessToken, userID)
+	if err != nil {
+		return nil, adminError(err, "failed to get user")
+	}
+
+	return &au.GetUserResponse{User: toAdminUser(user)}, nil
+}
+
+func (as *AdminServer) UpdateUser(
+	ctx     context.Context,
+	request *au.UpdateUserRequest,
+) (*au.UpdateUserResponse, error) {
+	accessToken, userID, err := userRequest(ctx, request.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Email != nil && *request.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email must not be empty")
+	}
+
+	user, err := as.adminService.UpdateUser(ctx, accessToken, userID, admin.UserUpdate{
+		Email:   request.Email,
+		IsAdmin: request.IsAdmin,
+	})
+	if err != nil {
+		return nil, adminError(err, "failed to update user")
+	}
+
+	return &au.UpdateUserResponse{User: toAdminUser(user)}, nil
+}
+
+func (as *AdminServer) DisableUser(
+	ctx     context.Context,
+	request *au.DisableUserRequest,
+) (*au.DisableUserResponse, error) {
+	accessToken, userID, err := userRequest(ctx, request.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.adminService.DisableUser(ctx, accessToken, userID); err != nil {
+		return nil, adminError(err, "failed to disable user")
+	}
+
+	return &au.DisableUserResponse{}, nil
+}
+
+func (as *AdminServer) DeleteUser(
+	ctx     context.Context,
+	request *au.DeleteUserRequest,
+) (*au.DeleteUserResponse, error) {
+	accessToken, userID, err := userRequest(ctx, request.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.adminService.DeleteUser(ctx, accessToken, userID); err != nil {
+		return nil, adminError(err, "failed to delete user")
+	}
+
+	return &au.DeleteUserResponse{}, nil
+}
+
+func (as *AdminServer) ListUserSessions(
+	ctx     context.Context,
+	request *au.ListUserSessionsRequest,
+) (*au.ListUserSessionsResponse, error) {
+	accessToken, userID, err := userRequest(ctx, request.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := as.adminService.ListSessions(ctx, accessToken, userID)
+	if err != nil {
+		return nil, adminError(err, "failed to list sessions")
+	}
+
+	return &au.ListUserSessionsResponse{Sessions: authHandler.ToSessions(sessions, uuid.Nil)}, nil
+}
+
+func (as *AdminServer) RevokeUserSession(
+	ctx     context.Context,
+	request *au.RevokeUserSessionRequest,
+) (*au.RevokeUserSessionResponse, error) {
+	accessToken, userID, err := userRequest(ctx, request.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if request.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	sessionID, err := uuid.Parse(request.GetSessionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid session ID format")
+	}
+
+	if err := as.adminService.RevokeSession(ctx, accessToken, userID, sessionID); err != nil {
+		return nil, adminError(err, "failed to revoke session")
+	}
+
+	return &au.RevokeUserSessionResponse{}, nil
+}
+
+func (as *AdminServer) RevokeUserSessions(
+	ctx     context.Context,
+	request *au.RevokeUserSessionsRequest,
+) (*au.RevokeUserSessionsResponse, error) {
+	accessToken, userID, err := userRequest(ctx, request.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.adminService.RevokeAllSessions(ctx, accessToken, userID); err != nil {
+		return nil, adminError(err, "failed to revoke sessions")
+	}
+
+	return &au.RevokeUserSessionsResponse{}, nil
+}
+
+// userRequest extracts the bearer token and parses the target user ID
+// shared by the single-user RPCs.
+func userRequest(
+	ctx   context.Context,
+	rawID string,
+) (string, uuid.UUID, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return "", uuid.Nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	if rawID == "" {
+		return "", uuid.Nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	userID, err := uuid.Parse(rawID)
+	if err != nil {
+		return "", uuid.Nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+
+	return accessToken, userID, nil
+}
+
+func adminError(err error, msg string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	case authHandler.AccountStatusError(err) != nil:
+		return authHandler.AccountStatusError(err)
+	case errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, auth.ErrSessionNotFound):
+		return status.Error(codes.NotFound, "session not found")
+	case errors.Is(err, storage.ErrUserExists):
+		return status.Error(codes.AlreadyExists, "email already in use")
+	case errors.Is(err, admin.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, "invalid page_token")
+	case errors.Is(err, admin.ErrInvalidStatus):
+		return status.Error(codes.InvalidArgument, "invalid status")
+	case errors.Is(err, admin.ErrInvalidTransition):
+		return status.Error(codes.FailedPrecondition, "invalid status transition")
+	case errors.Is(err, admin.ErrInvalidEmail):
+		return status.Error(codes.InvalidArgument, "invalid email")
+	case errors.Is(err, admin.ErrNothingToUpdate):
+		return status.Error(codes.InvalidArgument, "email or is_admin is required")
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func toAdminUser(user *userModel.User) *au.AdminUser {
+	return &au.AdminUser{
+		UserId:    user.ID.String(),
+		Email:     user.Email,
+		IsAdmin:   user.IsAdmin,
+		Status:    string(user.Status),
+		CreatedAt: user.CreatedAt.Unix(),
+	}
+}
@@ -3,10 +3,22 @@ package auth
 import (
 	"context"
 	"errors"
+
+	"github.com/Tbits007/auth/internal/domain/models/mfaModel"
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/lib/bearer"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/lib/password"
+	"github.com/Tbits007/auth/internal/lib/ratelimiter"
 	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/authz"
 	"github.com/Tbits007/auth/internal/storage"
 	au "github.com/Tbits007/contract/gen/go/auth"
 	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -23,28 +35,173 @@ type AuthService interface {
 		ctx context.Context,
 		email string,
 		password string,  
-	) (string, error)
+	) (tokenModel.LoginResult, error)
+
+	Refresh(
+		ctx context.Context,
+		refreshToken string,
+	) (tokenModel.TokenPair, error)
+
+	Logout(
+		ctx context.Context,
+		accessToken string,
+		refreshToken string,
+	) error
+
+	VerifyEmail(
+		ctx   context.Context,
+		token string,
+	) error
+
+	ResendVerification(
+		ctx   context.Context,
+		email string,
+	) error
+
+	RequestPasswordReset(
+		ctx   context.Context,
+		email string,
+	) error
+
+	ResetPassword(
+		ctx         context.Context,
+		token       string,
+		newPassword string,
+	) error
+
+	ChangePassword(
+		ctx                 context.Context,
+		accessToken         string,
+		oldPassword         string,
+		newPassword         string,
+		revokeOtherSessions bool,
+	) (tokenModel.TokenPair, error)
+
+	EnrollTOTP(
+		ctx         context.Context,
+		accessToken string,
+		challengeID string,
+	) (mfaModel.Enrollment, error)
+
+	ConfirmTOTP(
+		ctx         context.Context,
+		accessToken string,
+		challengeID string,
+		code        string,
+	) ([]string, *tokenModel.TokenPair, error)
+
+	VerifyMFA(
+		ctx         context.Context,
+		challengeID string,
+		code        string,
+	) (tokenModel.TokenPair, error)
+
+	BeginPasskeyRegistration(
+		ctx         context.Context,
+		accessToken string,
+	) ([]byte, error)
+
+	FinishPasskeyRegistration(
+		ctx         context.Context,
+		accessToken string,
+		response    []byte,
+	) (passkeyModel.Credential, error)
+
+	BeginPasskeyLogin(
+		ctx context.Context,
+	) (string, []byte, error)
+
+	FinishPasskeyLogin(
+		ctx         context.Context,
+		challengeID string,
+		response    []byte,
+	) (tokenModel.TokenPair, error)
+
+	ListSessions(
+		ctx         context.Context,
+		accessToken string,
+	) ([]sessionModel.Session, uuid.UUID, error)
+
+	RevokeSession(
+		ctx         context.Context,
+		accessToken string,
+		sessionID   uuid.UUID,
+	) error
+
+	RevokeAllSessions(
+		ctx         context.Context,
+		accessToken string,
+	) error
 
 	IsAdmin(
 	ctx   context.Context,
 	userID uuid.UUID,
 	) (bool, error)
+
+	HasPermission(
+		ctx        context.Context,
+		userID     uuid.UUID,
+		permission string,
+	) (bool, error)
+
+	AssignRole(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+		role        string,
+	) error
+
+	RevokeRole(
+		ctx         context.Context,
+		accessToken string,
+		userID      uuid.UUID,
+		role        string,
+	) error
+
+	Introspect(
+		ctx context.Context,
+		token string,
+	) (tokenModel.Introspection, error)
+
+	JWKS() jwt.JWKS
+}
+
+type AuthzService interface {
+	Check(
+		ctx   context.Context,
+		tuple relationModel.Tuple,
+	) (bool, error)
+
+	WriteRelation(
+		ctx         context.Context,
+		accessToken string,
+		tuple       relationModel.Tuple,
+	) error
+
+	DeleteRelation(
+		ctx         context.Context,
+		accessToken string,
+		tuple       relationModel.Tuple,
+	) error
 }
 
 
 type AuthServer struct {
 	au.UnimplementedAuthServer 
-	authService AuthService
+	authService  AuthService
+	authzService AuthzService
 }
 
 func NewAuthServer(
 	gRPCServer *grpc.Server,
 	authService AuthService,
+	authzService AuthzService,
 ) {
 	au.RegisterAuthServer(
 		gRPCServer,
 		&AuthServer{
-			authService: authService,
+			authService:  authService,
+			authzService: authzService,
 		},
 	)  
 }
@@ -67,6 +224,10 @@ func (as *AuthServer) Register(
 			return nil, status.Error(codes.AlreadyExists, "user already exists")
 		}
 
+		if errors.Is(err, auth.ErrInvalidPassword) {
+			return nil, passwordPolicyError(err, "password")
+		}
+
 		return nil, status.Error(codes.Internal, "failed to register user")
 	}
 
@@ -85,16 +246,534 @@ func (as *AuthServer) Login(
         return nil, status.Error(codes.InvalidArgument, "password is required")
     }
 
-    token, err := as.authService.Login(ctx, request.GetEmail(), request.GetPassword())
+    result, err := as.authService.Login(ctx, request.GetEmail(), request.GetPassword())
     if err != nil {
         if errors.Is(err, auth.ErrInvalidCredentials) {
             return nil, status.Error(codes.InvalidArgument, "invalid email or password")
         }
 
+        var lockoutErr *auth.LockoutError
+        if errors.As(err, &lockoutErr) {
+            return nil, ratelimiter.ExhaustedError(ctx, "too many failed login attempts", lockoutErr.RetryAfter)
+        }
+
+        if err := AccountStatusError(err); err != nil {
+            return nil, err
+        }
+
         return nil, status.Error(codes.Internal, "failed to login")
     }
 
-    return &au.LoginResponse{Token: token}, nil	
+    if result.MFAChallenge != nil {
+		return &au.LoginResponse{
+			MfaRequired:           true,
+			MfaChallengeId:        result.MFAChallenge.ID,
+			MfaEnrollmentRequired: result.MFAChallenge.EnrollmentRequired,
+		}, nil
+	}
+
+    return &au.LoginResponse{
+		Token:        result.Tokens.AccessToken,
+		RefreshToken: result.Tokens.RefreshToken,
+	}, nil	
+}
+
+func (as *AuthServer) Refresh(
+	ctx     context.Context,
+	request *au.RefreshRequest,
+) (*au.RefreshResponse, error) {
+	if request.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	tokens, err := as.authService.Refresh(ctx, request.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrRefreshTokenReused) {
+			return nil, status.Error(codes.Unauthenticated, "invalid refresh token")
+		}
+
+		if errors.Is(err, auth.ErrMFAEnrollmentRequired) {
+			return nil, status.Error(codes.FailedPrecondition, "mfa enrollment required")
+		}
+
+		if err := AccountStatusError(err); err != nil {
+			return nil, err
+		}
+
+		return nil, status.Error(codes.Internal, "failed to refresh token")
+	}
+
+	return &au.RefreshResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func (as *AuthServer) Logout(
+	ctx     context.Context,
+	request *au.LogoutRequest,
+) (*au.LogoutResponse, error) {
+	if request.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	err := as.authService.Logout(ctx, request.GetToken(), request.GetRefreshToken())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidToken) || errors.Is(err, auth.ErrTokenRevoked) {
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to logout")
+	}
+
+	return &au.LogoutResponse{}, nil
+}
+
+func (as *AuthServer) VerifyEmail(
+	ctx     context.Context,
+	request *au.VerifyEmailRequest,
+) (*au.VerifyEmailResponse, error) {
+	if request.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	err := as.authService.VerifyEmail(ctx, request.GetToken())
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidVerificationToken):
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired verification token")
+		case errors.Is(err, auth.ErrNotPendingVerification):
+			return nil, status.Error(codes.FailedPrecondition, "account is not pending verification")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to verify email")
+	}
+
+	return &au.VerifyEmailResponse{}, nil
+}
+
+func (as *AuthServer) ResendVerification(
+	ctx     context.Context,
+	request *au.ResendVerificationRequest,
+) (*au.ResendVerificationResponse, error) {
+	if request.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	err := as.authService.ResendVerification(ctx, request.GetEmail())
+	if err != nil {
+		if errors.Is(err, auth.ErrRateLimited) {
+			return nil, status.Error(codes.ResourceExhausted, "too many verification requests")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to resend verification")
+	}
+
+	return &au.ResendVerificationResponse{}, nil
+}
+
+func (as *AuthServer) RequestPasswordReset(
+	ctx     context.Context,
+	request *au.RequestPasswordResetRequest,
+) (*au.RequestPasswordResetResponse, error) {
+	if request.Email == "" {
+		return nil, status.Error(codes.InvalidArgument, "email is required")
+	}
+
+	if err := as.authService.RequestPasswordReset(ctx, request.GetEmail()); err != nil {
+		return nil, status.Error(codes.Internal, "failed to request password reset")
+	}
+
+	return &au.RequestPasswordResetResponse{}, nil
+}
+
+func (as *AuthServer) ResetPassword(
+	ctx     context.Context,
+	request *au.ResetPasswordRequest,
+) (*au.ResetPasswordResponse, error) {
+	if request.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if request.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	err := as.authService.ResetPassword(ctx, request.GetToken(), request.GetNewPassword())
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidResetToken) {
+			return nil, status.Error(codes.InvalidArgument, "invalid or expired password reset token")
+		}
+
+		if errors.Is(err, auth.ErrInvalidPassword) {
+			return nil, passwordPolicyError(err, "new_password")
+		}
+
+		if err := AccountStatusError(err); err != nil {
+			return nil, err
+		}
+
+		return nil, status.Error(codes.Internal, "failed to reset password")
+	}
+
+	return &au.ResetPasswordResponse{}, nil
+}
+
+func (as *AuthServer) ChangePassword(
+	ctx     context.Context,
+	request *au.ChangePasswordRequest,
+) (*au.ChangePasswordResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	if request.OldPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "old_password is required")
+	}
+
+	if request.NewPassword == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	tokens, err := as.authService.ChangePassword(
+		ctx,
+		accessToken,
+		request.GetOldPassword(),
+		request.GetNewPassword(),
+		request.GetRevokeOtherSessions(),
+	)
+	if err != nil {
+		var lockoutErr *auth.LockoutError
+		switch {
+		case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenRevoked):
+			return nil, status.Error(codes.Unauthenticated, "invalid token")
+		case errors.Is(err, auth.ErrInvalidCredentials):
+			return nil, status.Error(codes.InvalidArgument, "invalid old password")
+		case errors.As(err, &lockoutErr):
+			return nil, ratelimiter.ExhaustedError(ctx, "too many failed password attempts", lockoutErr.RetryAfter)
+		case errors.Is(err, auth.ErrInvalidPassword):
+			return nil, passwordPolicyError(err, "new_password")
+		case AccountStatusError(err) != nil:
+			return nil, AccountStatusError(err)
+		}
+
+		return nil, status.Error(codes.Internal, "failed to change password")
+	}
+
+	return &au.ChangePasswordResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+// EnrollTOTP authenticates with a bearer token, or with the challenge_id of
+// a login that requires enrollment first.
+func (as *AuthServer) EnrollTOTP(
+	ctx     context.Context,
+	request *au.EnrollTOTPRequest,
+) (*au.EnrollTOTPResponse, error) {
+	accessToken, err := mfaCaller(ctx, request.GetChallengeId())
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := as.authService.EnrollTOTP(ctx, accessToken, request.GetChallengeId())
+	if err != nil {
+		return nil, mfaError(err, "failed to enroll totp")
+	}
+
+	return &au.EnrollTOTPResponse{
+		Secret: enrollment.Secret,
+		Uri:    enrollment.URI,
+	}, nil
+}
+
+func (as *AuthServer) ConfirmTOTP(
+	ctx     context.Context,
+	request *au.ConfirmTOTPRequest,
+) (*au.ConfirmTOTPResponse, error) {
+	accessToken, err := mfaCaller(ctx, request.GetChallengeId())
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	recoveryCodes, tokens, err := as.authService.ConfirmTOTP(
+		ctx,
+		accessToken,
+		request.GetChallengeId(),
+		request.GetCode(),
+	)
+	if err != nil {
+		return nil, mfaError(err, "failed to confirm totp")
+	}
+
+	response := &au.ConfirmTOTPResponse{RecoveryCodes: recoveryCodes}
+	if tokens != nil {
+		response.Token = tokens.AccessToken
+		response.RefreshToken = tokens.RefreshToken
+	}
+
+	return response, nil
+}
+
+func (as *AuthServer) VerifyMFA(
+	ctx     context.Context,
+	request *au.VerifyMFARequest,
+) (*au.VerifyMFAResponse, error) {
+	if request.ChallengeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge_id is required")
+	}
+
+	if request.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+
+	tokens, err := as.authService.VerifyMFA(ctx, request.GetChallengeId(), request.GetCode())
+	if err != nil {
+		return nil, mfaError(err, "failed to verify mfa")
+	}
+
+	return &au.VerifyMFAResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+// mfaCaller returns the bearer token of the call. It may be omitted when
+// a challenge_id is given instead.
+func mfaCaller(ctx context.Context, challengeID string) (string, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil && challengeID == "" {
+		return "", status.Error(codes.Unauthenticated, "bearer token or challenge_id is required")
+	}
+
+	return accessToken, nil
+}
+
+func mfaError(err error, msg string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	case errors.Is(err, auth.ErrInvalidMFAChallenge):
+		return status.Error(codes.Unauthenticated, "invalid or expired mfa challenge")
+	case errors.Is(err, auth.ErrInvalidMFACode):
+		return status.Error(codes.InvalidArgument, "invalid mfa code")
+	case errors.Is(err, auth.ErrMFAAlreadyEnabled):
+		return status.Error(codes.AlreadyExists, "mfa already enabled")
+	case errors.Is(err, auth.ErrMFANotEnrolled):
+		return status.Error(codes.FailedPrecondition, "mfa not enrolled")
+	case errors.Is(err, auth.ErrMFAEnrollmentRequired):
+		return status.Error(codes.FailedPrecondition, "mfa enrollment required")
+	case errors.Is(err, auth.ErrTooManyAttempts):
+		return status.Error(codes.ResourceExhausted, "too many mfa attempts")
+	case AccountStatusError(err) != nil:
+		return AccountStatusError(err)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+// BeginPasskeyRegistration returns the PublicKeyCredentialCreationOptions
+// for navigator.credentials.create as JSON.
+func (as *AuthServer) BeginPasskeyRegistration(
+	ctx     context.Context,
+	request *au.BeginPasskeyRegistrationRequest,
+) (*au.BeginPasskeyRegistrationResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	options, err := as.authService.BeginPasskeyRegistration(ctx, accessToken)
+	if err != nil {
+		return nil, passkeyError(err, "failed to begin passkey registration")
+	}
+
+	return &au.BeginPasskeyRegistrationResponse{Options: string(options)}, nil
+}
+
+// FinishPasskeyRegistration takes the JSON encoded PublicKeyCredential
+// returned by navigator.credentials.create.
+func (as *AuthServer) FinishPasskeyRegistration(
+	ctx     context.Context,
+	request *au.FinishPasskeyRegistrationRequest,
+) (*au.FinishPasskeyRegistrationResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	if request.Credential == "" {
+		return nil, status.Error(codes.InvalidArgument, "credential is required")
+	}
+
+	credential, err := as.authService.FinishPasskeyRegistration(ctx, accessToken, []byte(request.GetCredential()))
+	if err != nil {
+		return nil, passkeyError(err, "failed to finish passkey registration")
+	}
+
+	return &au.FinishPasskeyRegistrationResponse{PasskeyId: credential.ID.String()}, nil
+}
+
+// BeginPasskeyLogin returns the PublicKeyCredentialRequestOptions for
+// navigator.credentials.get as JSON.
+func (as *AuthServer) BeginPasskeyLogin(
+	ctx     context.Context,
+	request *au.BeginPasskeyLoginRequest,
+) (*au.BeginPasskeyLoginResponse, error) {
+	challengeID, options, err := as.authService.BeginPasskeyLogin(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to begin passkey login")
+	}
+
+	return &au.BeginPasskeyLoginResponse{
+		ChallengeId: challengeID,
+		Options:     string(options),
+	}, nil
+}
+
+// FinishPasskeyLogin takes the JSON encoded PublicKeyCredential returned
+// by navigator.credentials.get.
+func (as *AuthServer) FinishPasskeyLogin(
+	ctx     context.Context,
+	request *au.FinishPasskeyLoginRequest,
+) (*au.FinishPasskeyLoginResponse, error) {
+	if request.ChallengeId == "" {
+		return nil, status.Error(codes.InvalidArgument, "challenge_id is required")
+	}
+
+	if request.Credential == "" {
+		return nil, status.Error(codes.InvalidArgument, "credential is required")
+	}
+
+	tokens, err := as.authService.FinishPasskeyLogin(ctx, request.GetChallengeId(), []byte(request.GetCredential()))
+	if err != nil {
+		return nil, passkeyError(err, "failed to finish passkey login")
+	}
+
+	return &au.FinishPasskeyLoginResponse{
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+	}, nil
+}
+
+func passkeyError(err error, msg string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	case errors.Is(err, auth.ErrInvalidPasskeyChallenge):
+		return status.Error(codes.Unauthenticated, "invalid or expired passkey challenge")
+	case errors.Is(err, auth.ErrInvalidPasskey):
+		return status.Error(codes.Unauthenticated, "invalid passkey")
+	case errors.Is(err, auth.ErrPasskeyExists):
+		return status.Error(codes.AlreadyExists, "passkey already registered")
+	case AccountStatusError(err) != nil:
+		return AccountStatusError(err)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func (as *AuthServer) ListSessions(
+	ctx     context.Context,
+	request *au.ListSessionsRequest,
+) (*au.ListSessionsResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	sessions, current, err := as.authService.ListSessions(ctx, accessToken)
+	if err != nil {
+		return nil, SessionError(err, "failed to list sessions")
+	}
+
+	return &au.ListSessionsResponse{Sessions: ToSessions(sessions, current)}, nil
+}
+
+// RevokeSession ends one of the caller's sessions. Revoking the current
+// session is the same as Logout.
+func (as *AuthServer) RevokeSession(
+	ctx     context.Context,
+	request *au.RevokeSessionRequest,
+) (*au.RevokeSessionResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	if request.SessionId == "" {
+		return nil, status.Error(codes.InvalidArgument, "session_id is required")
+	}
+
+	sessionID, err := uuid.Parse(request.GetSessionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid session ID format")
+	}
+
+	if err := as.authService.RevokeSession(ctx, accessToken, sessionID); err != nil {
+		return nil, SessionError(err, "failed to revoke session")
+	}
+
+	return &au.RevokeSessionResponse{}, nil
+}
+
+func (as *AuthServer) RevokeAllSessions(
+	ctx     context.Context,
+	request *au.RevokeAllSessionsRequest,
+) (*au.RevokeAllSessionsResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	if err := as.authService.RevokeAllSessions(ctx, accessToken); err != nil {
+		return nil, SessionError(err, "failed to revoke sessions")
+	}
+
+	return &au.RevokeAllSessionsResponse{}, nil
+}
+
+// SessionError maps session management errors to gRPC statuses. It is
+// shared with the admin handlers.
+func SessionError(err error, msg string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	case errors.Is(err, auth.ErrSessionNotFound):
+		return status.Error(codes.NotFound, "session not found")
+	case AccountStatusError(err) != nil:
+		return AccountStatusError(err)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+// ToSessions converts sessions for a response, flagging the one with the
+// ID current.
+func ToSessions(
+	sessions []sessionModel.Session,
+	current  uuid.UUID,
+) []*au.Session {
+	out := make([]*au.Session, 0, len(sessions))
+	for _, session := range sessions {
+		out = append(out, &au.Session{
+			SessionId:  session.ID.String(),
+			UserAgent:  session.UserAgent,
+			Ip:         session.IP,
+			CreatedAt:  session.CreatedAt.Unix(),
+			LastSeenAt: session.LastSeenAt.Unix(),
+			ExpiresAt:  session.ExpiresAt.Unix(),
+			Current:    current != uuid.Nil && session.ID == current,
+		})
+	}
+
+	return out
 }
 
 func (as *AuthServer) IsAdmin(
@@ -120,4 +799,288 @@ func (as *AuthServer) IsAdmin(
 	}
 
 	return &au.IsAdminResponse{IsAdmin: isAdmin}, nil	
+}
+
+func (as *AuthServer) HasPermission(
+	ctx     context.Context,
+	request *au.HasPermissionRequest,
+) (*au.HasPermissionResponse, error) {
+	if request.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if request.Permission == "" {
+		return nil, status.Error(codes.InvalidArgument, "permission is required")
+	}
+
+	userID, err := uuid.Parse(request.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+
+	allowed, err := as.authService.HasPermission(ctx, userID, request.GetPermission())
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			return nil, status.Error(codes.NotFound, "user not found")
+		}
+
+		return nil, status.Error(codes.Internal, "failed to check permission")
+	}
+
+	return &au.HasPermissionResponse{HasPermission: allowed}, nil
+}
+
+func (as *AuthServer) AssignRole(
+	ctx     context.Context,
+	request *au.AssignRoleRequest,
+) (*au.AssignRoleResponse, error) {
+	accessToken, userID, err := roleRequest(ctx, request.GetUserId(), request.GetRole())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.authService.AssignRole(ctx, accessToken, userID, request.GetRole()); err != nil {
+		return nil, roleError(err, "failed to assign role")
+	}
+
+	return &au.AssignRoleResponse{}, nil
+}
+
+func (as *AuthServer) RevokeRole(
+	ctx     context.Context,
+	request *au.RevokeRoleRequest,
+) (*au.RevokeRoleResponse, error) {
+	accessToken, userID, err := roleRequest(ctx, request.GetUserId(), request.GetRole())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := as.authService.RevokeRole(ctx, accessToken, userID, request.GetRole()); err != nil {
+		return nil, roleError(err, "failed to revoke role")
+	}
+
+	return &au.RevokeRoleResponse{}, nil
+}
+
+// roleRequest validates the common AssignRole/RevokeRole input. The caller
+// authenticates with a bearer token in the "authorization" metadata.
+func roleRequest(
+	ctx    context.Context,
+	rawID  string,
+	role   string,
+) (string, uuid.UUID, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return "", uuid.Nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	if rawID == "" {
+		return "", uuid.Nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	if role == "" {
+		return "", uuid.Nil, status.Error(codes.InvalidArgument, "role is required")
+	}
+
+	userID, err := uuid.Parse(rawID)
+	if err != nil {
+		return "", uuid.Nil, status.Error(codes.InvalidArgument, "invalid user ID format")
+	}
+
+	return accessToken, userID, nil
+}
+
+func roleError(err error, msg string) error {
+	switch {
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	case errors.Is(err, auth.ErrUnknownRole):
+		return status.Error(codes.InvalidArgument, "unknown role")
+	case errors.Is(err, auth.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case AccountStatusError(err) != nil:
+		return AccountStatusError(err)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+func (as *AuthServer) Check(
+	ctx     context.Context,
+	request *au.CheckRequest,
+) (*au.CheckResponse, error) {
+	allowed, err := as.authzService.Check(ctx, relationModel.Tuple{
+		Object:   request.GetObject(),
+		Relation: request.GetRelation(),
+		Subject:  request.GetSubject(),
+	})
+	if err != nil {
+		if errors.Is(err, authz.ErrInvalidTuple) {
+			return nil, status.Error(codes.InvalidArgument, invalidTupleMessage)
+		}
+
+		return nil, status.Error(codes.Internal, "failed to check relation")
+	}
+
+	return &au.CheckResponse{Allowed: allowed}, nil
+}
+
+func (as *AuthServer) WriteRelation(
+	ctx     context.Context,
+	request *au.WriteRelationRequest,
+) (*au.WriteRelationResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	err = as.authzService.WriteRelation(ctx, accessToken, relationModel.Tuple{
+		Object:   request.GetObject(),
+		Relation: request.GetRelation(),
+		Subject:  request.GetSubject(),
+	})
+	if err != nil {
+		return nil, relationError(err, "failed to write relation")
+	}
+
+	return &au.WriteRelationResponse{}, nil
+}
+
+func (as *AuthServer) DeleteRelation(
+	ctx     context.Context,
+	request *au.DeleteRelationRequest,
+) (*au.DeleteRelationResponse, error) {
+	accessToken, err := bearer.FromIncomingContext(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, "bearer token is required")
+	}
+
+	err = as.authzService.DeleteRelation(ctx, accessToken, relationModel.Tuple{
+		Object:   request.GetObject(),
+		Relation: request.GetRelation(),
+		Subject:  request.GetSubject(),
+	})
+	if err != nil {
+		return nil, relationError(err, "failed to delete relation")
+	}
+
+	return &au.DeleteRelationResponse{}, nil
+}
+
+const invalidTupleMessage = "subject, relation and object are required; subject and object must be type:id"
+
+func relationError(err error, msg string) error {
+	switch {
+	case errors.Is(err, authz.ErrInvalidTuple):
+		return status.Error(codes.InvalidArgument, invalidTupleMessage)
+	case errors.Is(err, auth.ErrInvalidToken), errors.Is(err, auth.ErrTokenRevoked):
+		return status.Error(codes.Unauthenticated, "invalid token")
+	case errors.Is(err, auth.ErrPermissionDenied):
+		return status.Error(codes.PermissionDenied, "permission denied")
+	case AccountStatusError(err) != nil:
+		return AccountStatusError(err)
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
+
+// passwordPolicyError reports every violated password rule as a
+// BadRequest field violation on field, with the rule as its reason.
+func passwordPolicyError(err error, field string) error {
+	st := status.New(codes.InvalidArgument, "password does not meet policy")
+
+	var policyErr *password.PolicyError
+	if !errors.As(err, &policyErr) {
+		return st.Err()
+	}
+
+	violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(policyErr.Violations))
+	for _, v := range policyErr.Violations {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: v.Description,
+			Reason:      v.Rule,
+		})
+	}
+
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+// AccountStatusError maps the errors returned for accounts that are not
+// active. A client can act on pending verification, so it is
+// FailedPrecondition; disabled and deleted accounts are PermissionDenied.
+// It returns nil for any other error.
+func AccountStatusError(err error) error {
+	switch {
+	case errors.Is(err, auth.ErrAccountNotVerified):
+		return status.Error(codes.FailedPrecondition, "account not verified")
+	case errors.Is(err, auth.ErrAccountDisabled):
+		return status.Error(codes.PermissionDenied, "account disabled")
+	case errors.Is(err, auth.ErrAccountDeleted):
+		return status.Error(codes.PermissionDenied, "account deleted")
+	default:
+		return nil
+	}
+}
+
+func (as *AuthServer) Introspect(
+	ctx     context.Context,
+	request *au.IntrospectRequest,
+) (*au.IntrospectResponse, error) {
+	if request.Token == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	introspection, err := as.authService.Introspect(ctx, request.GetToken())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to introspect token")
+	}
+
+	if !introspection.Active {
+		return &au.IntrospectResponse{Active: false}, nil
+	}
+
+	response := &au.IntrospectResponse{
+		Active:    true,
+		UserId:    introspection.UserID.String(),
+		Email:     introspection.Email,
+		IsAdmin:   introspection.IsAdmin,
+		ExpiresAt: introspection.ExpiresAt.Unix(),
+	}
+	if !introspection.IssuedAt.IsZero() {
+		response.IssuedAt = introspection.IssuedAt.Unix()
+	}
+
+	return response, nil
+}
+
+func (as *AuthServer) GetJWKS(
+	ctx     context.Context,
+	request *au.GetJWKSRequest,
+) (*au.GetJWKSResponse, error) {
+	jwks := as.authService.JWKS()
+
+	keys := make([]*au.JWK, 0, len(jwks.Keys))
+	for _, key := range jwks.Keys {
+		keys = append(keys, &au.JWK{
+			Kty: key.Kty,
+			Use: key.Use,
+			Alg: key.Alg,
+			Kid: key.Kid,
+			N:   key.N,
+			E:   key.E,
+			Crv: key.Crv,
+			X:   key.X,
+			Y:   key.Y,
+		})
+	}
+
+	return &au.GetJWKSResponse{Keys: keys}, nil
 }
\ No newline at end of file
@@ -0,0 +1,28 @@
+package jwks
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Tbits007/auth/internal/lib/jwt"
+)
+
+const Path = "/.well-known/jwks.json"
+
+type Provider interface {
+	JWKS() jwt.JWKS
+}
+
+func NewHandler(provider Provider) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "public, max-age=300")
+		_ = json.NewEncoder(w).Encode(provider.JWKS())
+	})
+}
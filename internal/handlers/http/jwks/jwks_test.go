@@ -0,0 +1,47 @@
+package jwks
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+
+func TestHandler_ServesPublicKeys(t *testing.T) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	key, err := jwt.NewAsymmetricKey("test", jwt.AlgEdDSA, privateKey)
+	require.NoError(t, err)
+	keyring, err := jwt.NewKeyring(key)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	NewHandler(keyring).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	var jwks jwt.JWKS
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &jwks))
+	require.Len(t, jwks.Keys, 1)
+	assert.Equal(t, "OKP", jwks.Keys[0].Kty)
+	assert.Equal(t, "test", jwks.Keys[0].Kid)
+	assert.NotContains(t, rec.Body.String(), `"d"`)
+}
+
+func TestHandler_RejectsPost(t *testing.T) {
+	keyring, err := jwt.NewKeyring(jwt.NewHMACKey("test", "secret"))
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	NewHandler(keyring).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, Path, nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
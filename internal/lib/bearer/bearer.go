@@ -0,0 +1,31 @@
+package bearer
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const MetadataKey = "authorization"
+
+var ErrMissingToken = errors.New("missing bearer token")
+
+// FromIncomingContext returns the token from an "authorization: Bearer <token>"
+// gRPC metadata entry.
+func FromIncomingContext(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", ErrMissingToken
+	}
+
+	for _, value := range md.Get(MetadataKey) {
+		scheme, token, found := strings.Cut(value, " ")
+		if found && strings.EqualFold(scheme, "Bearer") && token != "" {
+			return strings.TrimSpace(token), nil
+		}
+	}
+
+	return "", ErrMissingToken
+}
@@ -0,0 +1,40 @@
+package bearer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestFromIncomingContext(t *testing.T) {
+	tests := []struct {
+		name    string
+		md      metadata.MD
+		want    string
+		wantErr bool
+	}{
+		{name: "bearer", md: metadata.Pairs(MetadataKey, "Bearer abc"), want: "abc"},
+		{name: "case insensitive scheme", md: metadata.Pairs(MetadataKey, "bearer abc"), want: "abc"},
+		{name: "other scheme", md: metadata.Pairs(MetadataKey, "Basic abc"), wantErr: true},
+		{name: "empty token", md: metadata.Pairs(MetadataKey, "Bearer "), wantErr: true},
+		{name: "missing", md: metadata.MD{}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			token, err := FromIncomingContext(ctx)
+
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrMissingToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, token)
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package clientip
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// ForwardedForKey is the metadata key proxies use for the original client
+// address. Only its first entry is used.
+const ForwardedForKey = "x-forwarded-for"
+
+type ctxKey struct{}
+
+func WithIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, ip)
+}
+
+// FromContext returns the client IP stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ctxKey{}).(string)
+	return ip
+}
+
+// UnaryServerInterceptor stores the client IP in the context. It is the
+// peer address unless trustForwarded is set, in which case a valid
+// x-forwarded-for value sent by a fronting proxy takes precedence. Only
+// enable it behind a proxy that overwrites the header, since clients can
+// set it to anything.
+func UnaryServerInterceptor(trustForwarded bool) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		ip := ""
+		if trustForwarded {
+			ip = forwardedIP(ctx)
+		}
+		if ip == "" {
+			ip = peerIP(ctx)
+		}
+
+		return handler(WithIP(ctx, ip), req)
+	}
+}
+
+func forwardedIP(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(ForwardedForKey)
+	if len(values) == 0 {
+		return ""
+	}
+
+	first, _, _ := strings.Cut(values[0], ",")
+	ip := net.ParseIP(strings.TrimSpace(first))
+	if ip == nil {
+		return ""
+	}
+
+	return ip.String()
+}
+
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	addr := p.Addr.String()
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+
+	return addr
+}
@@ -0,0 +1,51 @@
+package clientip
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwarded      string
+		trustForwarded bool
+		want           string
+	}{
+		{name: "peer address", want: "192.0.2.10"},
+		{name: "forwarded ignored when untrusted", forwarded: "198.51.100.7", want: "192.0.2.10"},
+		{name: "forwarded trusted", forwarded: "198.51.100.7, 10.0.0.1", trustForwarded: true, want: "198.51.100.7"},
+		{name: "invalid forwarded falls back to peer", forwarded: "not-an-ip", trustForwarded: true, want: "192.0.2.10"},
+		{name: "ipv6 forwarded", forwarded: "2001:db8::1", trustForwarded: true, want: "2001:db8::1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := peer.NewContext(context.Background(), &peer.Peer{
+				Addr: &net.TCPAddr{IP: net.ParseIP("192.0.2.10"), Port: 51234},
+			})
+			if tt.forwarded != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(ForwardedForKey, tt.forwarded))
+			}
+
+			var got string
+			_, err := UnaryServerInterceptor(tt.trustForwarded)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				got = FromContext(ctx)
+				return nil, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFromContext_Empty(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+}
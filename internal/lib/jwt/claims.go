@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"encoding/json"
+	"slices"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// reservedClaims cannot be overwritten through Claims.Extra.
+var reservedClaims = []string{
+	"iss", "sub", "aud", "exp", "nbf", "iat", "jti",
+	"uuid", "email", "admin", "sid",
+}
+
+// Claims is the payload of an access token. The uuid claim duplicates sub
+// for consumers that predate the registered claims. SessionID is zero for
+// tokens not bound to a login session.
+type Claims struct {
+	UserID    uuid.UUID      `json:"uuid"`
+	Email     string         `json:"email"`
+	IsAdmin   bool           `json:"admin"`
+	SessionID uuid.UUID      `json:"sid,omitzero"`
+	Extra     map[string]any `json:"-"`
+	jwt.RegisteredClaims
+}
+
+// MarshalJSON flattens Extra into the top level of the payload, next to
+// the registered claims.
+func (c Claims) MarshalJSON() ([]byte, error) {
+	type plain Claims
+
+	data, err := json.Marshal(plain(c))
+	if err != nil || len(c.Extra) == 0 {
+		return data, err
+	}
+
+	var merged map[string]any
+	if err := json.Unmarshal(data, &merged); err != nil {
+		return nil, err
+	}
+
+	for name, value := range c.Extra {
+		if slices.Contains(reservedClaims, name) {
+			continue
+		}
+		merged[name] = value
+	}
+
+	return json.Marshal(merged)
+}
+
+func (c *Claims) UnmarshalJSON(data []byte) error {
+	type plain Claims
+
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+
+	var all map[string]any
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	for _, name := range reservedClaims {
+		delete(all, name)
+	}
+
+	c.Extra = nil
+	if len(all) > 0 {
+		c.Extra = all
+	}
+
+	return nil
+}
@@ -1,29 +1,169 @@
 package jwt
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/Tbits007/auth/internal/domain/models/userModel"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+var (
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token expired")
+)
+
+// Enricher adds deployment specific claims (roles, permissions, tenant...)
+// to a token before it is signed. Custom claims go into Claims.Extra.
+type Enricher func(ctx context.Context, user userModel.User, claims *Claims) error
+
+type Issuer struct {
+	keyring   *Keyring
+	issuer    string
+	audience  []string
+	enrichers []Enricher
+}
+
+func NewIssuer(
+	keyring *Keyring,
+	issuer string,
+	audience []string,
+	enrichers ...Enricher,
+) *Issuer {
+	return &Issuer{
+		keyring:   keyring,
+		issuer:    issuer,
+		audience:  audience,
+		enrichers: enrichers,
+	}
+}
+
+// Use registers an additional enricher. It must be called before the
+// issuer is shared between goroutines.
+func (i *Issuer) Use(enricher Enricher) {
+	i.enrichers = append(i.enrichers, enricher)
+}
+
+func (i *Issuer) Keyring() *Keyring {
+	return i.keyring
+}
 
-func NewToken(
+func (i *Issuer) NewToken(
+	ctx context.Context,
 	user userModel.User,
-	duration time.Duration, 
-	secretKey string,
-) (string, error) {  
-    token := jwt.New(jwt.SigningMethodHS256)  
-
-    claims := token.Claims.(jwt.MapClaims)  
-    claims["uuid"] = user.ID  
-    claims["email"] = user.Email  
-    claims["exp"] = time.Now().Add(duration).Unix()   
-
-    tokenString, err := token.SignedString([]byte(secretKey))  
-    if err != nil {  
-       return "", err  
-    }  
-
-    return tokenString, nil  
-}
\ No newline at end of file
+	duration time.Duration,
+) (string, error) {
+	return i.NewSessionToken(ctx, user, uuid.Nil, duration)
+}
+
+// NewSessionToken issues a token bound to the login session sessionID, so
+// that revoking the session revokes the token.
+func (i *Issuer) NewSessionToken(
+	ctx       context.Context,
+	user      userModel.User,
+	sessionID uuid.UUID,
+	duration  time.Duration,
+) (string, error) {
+	key := i.keyring.Active()
+
+	now := time.Now()
+
+	claims := Claims{
+		UserID:    user.ID,
+		Email:     user.Email,
+		IsAdmin:   user.IsAdmin,
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Subject:   user.ID.String(),
+			Issuer:    i.issuer,
+			Audience:  i.audience,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
+		},
+	}
+
+	for _, enrich := range i.enrichers {
+		if err := enrich(ctx, user, &claims); err != nil {
+			return "", fmt.Errorf("enrich claims: %w", err)
+		}
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+
+	tokenString, err := token.SignedString(key.signKey)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenString, nil
+}
+
+func (i *Issuer) ParseToken(tokenString string) (*Claims, error) {
+	var claims Claims
+
+	opts := []jwt.ParserOption{jwt.WithExpirationRequired()}
+	if i.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(i.issuer))
+	}
+
+	_, err := jwt.ParseWithClaims(
+		tokenString,
+		&claims,
+		func(token *jwt.Token) (any, error) {
+			key, err := verificationKey(token, i.keyring)
+			if err != nil {
+				return nil, err
+			}
+			if token.Method.Alg() != key.Algorithm() {
+				return nil, fmt.Errorf("unexpected signing method %q for key %q", token.Method.Alg(), key.id)
+			}
+			return key.verifyKey, nil
+		},
+		opts...,
+	)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, fmt.Errorf("%w: %w", ErrTokenExpired, err)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+
+	if claims.ID == "" {
+		return nil, fmt.Errorf("%w: missing jti claim", ErrInvalidToken)
+	}
+
+	if len(i.audience) > 0 && !slices.ContainsFunc(claims.Audience, func(aud string) bool {
+		return slices.Contains(i.audience, aud)
+	}) {
+		return nil, fmt.Errorf("%w: unexpected audience %v", ErrInvalidToken, claims.Audience)
+	}
+
+	return &claims, nil
+}
+
+func (i *Issuer) JWKS() JWKS {
+	return i.keyring.JWKS()
+}
+
+// verificationKey picks the key named by the kid header. Tokens issued
+// before kid headers were introduced are checked against the active key.
+func verificationKey(token *jwt.Token, keyring *Keyring) (*Key, error) {
+	kid, ok := token.Header["kid"]
+	if !ok {
+		return keyring.Active(), nil
+	}
+
+	id, ok := kid.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed kid header", ErrUnknownKey)
+	}
+
+	return keyring.Lookup(id)
+}
@@ -0,0 +1,187 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTokenAndParse_Success(t *testing.T) {
+	ctx := context.Background()
+	issuer := NewIssuer(newHMACKeyring(t, "secret"), "auth", []string{"gateway", "billing"})
+	user := userModel.User{
+		ID:      uuid.New(),
+		Email:   "test@example.com",
+		IsAdmin: true,
+	}
+
+	token, err := issuer.NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	claims, err := issuer.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, user.ID.String(), claims.Subject)
+	assert.Equal(t, user.Email, claims.Email)
+	assert.True(t, claims.IsAdmin)
+	assert.Equal(t, "auth", claims.Issuer)
+	assert.Equal(t, jwt.ClaimStrings{"gateway", "billing"}, claims.Audience)
+	assert.NotEmpty(t, claims.ID)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), claims.ExpiresAt.Time, time.Minute)
+	assert.WithinDuration(t, time.Now(), claims.IssuedAt.Time, time.Minute)
+	assert.WithinDuration(t, time.Now(), claims.NotBefore.Time, time.Minute)
+}
+
+func TestNewToken_UniqueJTI(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t, "secret")
+	user := userModel.User{ID: uuid.New()}
+
+	first, err := issuer.NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+	second, err := issuer.NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	firstClaims, err := issuer.ParseToken(first)
+	require.NoError(t, err)
+	secondClaims, err := issuer.ParseToken(second)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, firstClaims.ID, secondClaims.ID)
+}
+
+func TestNewSessionToken(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t, "secret")
+	user := userModel.User{ID: uuid.New()}
+	sessionID := uuid.New()
+
+	token, err := issuer.NewSessionToken(ctx, user, sessionID, time.Hour)
+	require.NoError(t, err)
+
+	claims, err := issuer.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, claims.SessionID)
+	assert.Nil(t, claims.Extra)
+
+	token, err = issuer.NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	claims, err = issuer.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, claims.SessionID)
+}
+
+func TestNewToken_Enrichers(t *testing.T) {
+	ctx := context.Background()
+	issuer := newTestIssuer(t, "secret")
+	issuer.Use(func(_ context.Context, user userModel.User, claims *Claims) error {
+		claims.Extra = map[string]any{
+			"roles":  []string{"support"},
+			"tenant": "acme",
+			"sub":    "spoofed",
+		}
+		return nil
+	})
+
+	user := userModel.User{ID: uuid.New()}
+	token, err := issuer.NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	claims, err := issuer.ParseToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, user.ID.String(), claims.Subject, "extra claims must not override registered ones")
+	assert.Equal(t, "acme", claims.Extra["tenant"])
+	assert.Equal(t, []any{"support"}, claims.Extra["roles"])
+	assert.NotContains(t, claims.Extra, "sub")
+}
+
+func TestNewToken_EnricherError(t *testing.T) {
+	issuer := newTestIssuer(t, "secret")
+	enrichErr := errors.New("roles unavailable")
+	issuer.Use(func(context.Context, userModel.User, *Claims) error {
+		return enrichErr
+	})
+
+	_, err := issuer.NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+
+	assert.ErrorIs(t, err, enrichErr)
+}
+
+func TestParseToken_WrongSecret(t *testing.T) {
+	token, err := newTestIssuer(t, "secret").NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	_, err = newTestIssuer(t, "other").ParseToken(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseToken_WrongIssuer(t *testing.T) {
+	keyring := newHMACKeyring(t, "secret")
+
+	token, err := NewIssuer(keyring, "someone-else", nil).NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	_, err = NewIssuer(keyring, "auth", nil).ParseToken(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseToken_WrongAudience(t *testing.T) {
+	keyring := newHMACKeyring(t, "secret")
+
+	token, err := NewIssuer(keyring, "auth", []string{"billing"}).NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	_, err = NewIssuer(keyring, "auth", []string{"gateway"}).ParseToken(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseToken_Expired(t *testing.T) {
+	issuer := newTestIssuer(t, "secret")
+
+	token, err := issuer.NewToken(context.Background(), userModel.User{ID: uuid.New()}, -time.Minute)
+	require.NoError(t, err)
+
+	_, err = issuer.ParseToken(token)
+
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestParseToken_Malformed(t *testing.T) {
+	_, err := newTestIssuer(t, "secret").ParseToken("not-a-jwt")
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestParseToken_UnknownKid(t *testing.T) {
+	token, err := newTestIssuer(t, "secret").NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	other, err := NewKeyring(NewHMACKey("other", "secret"))
+	require.NoError(t, err)
+
+	_, err = NewIssuer(other, "", nil).ParseToken(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func newHMACKeyring(t *testing.T, secret string) *Keyring {
+	keyring, err := NewKeyring(NewHMACKey("test", secret))
+	require.NoError(t, err)
+
+	return keyring
+}
+
+func newTestIssuer(t *testing.T, secret string) *Issuer {
+	return NewIssuer(newHMACKeyring(t, secret), "auth", []string{"test"})
+}
@@ -0,0 +1,185 @@
+package jwt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type KeyStatus string
+
+const (
+	KeyStatusActive KeyStatus = "active"
+	KeyStatusVerify KeyStatus = "verify"
+)
+
+// KeyFile is the on-disk description of a keyring. Key material stays in
+// PEM files or secrets; the file only records which key signs and until
+// when the others are still accepted.
+type KeyFile struct {
+	Keys []KeyFileEntry `yaml:"keys"`
+}
+
+type KeyFileEntry struct {
+	ID             string     `yaml:"kid"`
+	Algorithm      string     `yaml:"alg"`
+	Secret         string     `yaml:"secret,omitempty"`
+	PrivateKeyPath string     `yaml:"private_key_path,omitempty"`
+	Status         KeyStatus  `yaml:"status"`
+	RetireAt       *time.Time `yaml:"retire_at,omitempty"`
+}
+
+func ReadKeyFile(path string) (*KeyFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read key file: %w", err)
+	}
+
+	var f KeyFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse key file: %w", err)
+	}
+
+	return &f, nil
+}
+
+// WriteFile replaces path atomically so a reloading server never reads a
+// half-written keyring.
+func (f *KeyFile) WriteFile(path string) error {
+	data, err := yaml.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("marshal key file: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".keyring-*")
+	if err != nil {
+		return fmt.Errorf("create temp key file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp key file: %w", err)
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("chmod temp key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp key file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace key file: %w", err)
+	}
+
+	return nil
+}
+
+// Promote makes kid the signing key. The previously active keys are kept
+// for verification until overlap has passed, which should be at least the
+// longest token TTL.
+func (f *KeyFile) Promote(
+	kid string,
+	now time.Time,
+	overlap time.Duration,
+) error {
+	target := f.entry(kid)
+	if target == nil {
+		return fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if target.Status == KeyStatusActive {
+		return nil
+	}
+
+	retireAt := now.Add(overlap)
+	for i := range f.Keys {
+		if f.Keys[i].Status == KeyStatusActive {
+			f.Keys[i].Status = KeyStatusVerify
+			f.Keys[i].RetireAt = &retireAt
+		}
+	}
+
+	target.Status = KeyStatusActive
+	target.RetireAt = nil
+
+	return nil
+}
+
+// Prune drops verification keys whose retirement time has passed and
+// returns their ids.
+func (f *KeyFile) Prune(now time.Time) []string {
+	var (
+		kept    []KeyFileEntry
+		removed []string
+	)
+
+	for _, entry := range f.Keys {
+		if entry.Status != KeyStatusActive && entry.RetireAt != nil && now.After(*entry.RetireAt) {
+			removed = append(removed, entry.ID)
+			continue
+		}
+		kept = append(kept, entry)
+	}
+
+	f.Keys = kept
+	return removed
+}
+
+func (f *KeyFile) Keyring() (*Keyring, error) {
+	var (
+		active       *Key
+		verification []*Key
+		now          = time.Now()
+	)
+
+	for _, entry := range f.Keys {
+		if entry.RetireAt != nil && now.After(*entry.RetireAt) {
+			continue
+		}
+
+		key, err := LoadKey(entry.ID, entry.Algorithm, entry.Secret, entry.PrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("load key %q: %w", entry.ID, err)
+		}
+
+		switch entry.Status {
+		case KeyStatusActive:
+			if active != nil {
+				return nil, fmt.Errorf("%w: more than one active key", ErrInvalidKey)
+			}
+			active = key
+		case KeyStatusVerify:
+			if entry.RetireAt != nil {
+				key = key.RetireAfter(*entry.RetireAt)
+			}
+			verification = append(verification, key)
+		default:
+			return nil, fmt.Errorf("%w: key %q has unknown status %q", ErrInvalidKey, entry.ID, entry.Status)
+		}
+	}
+
+	return NewKeyring(active, verification...)
+}
+
+func LoadKeyring(path string) (*Keyring, error) {
+	f, err := ReadKeyFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Keyring()
+}
+
+func (f *KeyFile) entry(kid string) *KeyFileEntry {
+	for i := range f.Keys {
+		if f.Keys[i].ID == kid {
+			return &f.Keys[i]
+		}
+	}
+
+	return nil
+}
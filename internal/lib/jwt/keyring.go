@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownKey = errors.New("unknown signing key")
+)
+
+// Keyring holds the key new tokens are signed with and every key whose
+// tokens are still accepted. Verification picks the key named by the
+// token's kid header, so keys can be rotated without invalidating tokens
+// that are still in flight.
+type Keyring struct {
+	mu     sync.RWMutex
+	active *Key
+	keys   map[string]*Key
+}
+
+func NewKeyring(
+	active *Key,
+	verification ...*Key,
+) (*Keyring, error) {
+	kr := &Keyring{}
+	if err := kr.set(active, verification); err != nil {
+		return nil, err
+	}
+
+	return kr, nil
+}
+
+// Replace swaps the keyring contents in place, so every holder of kr sees
+// the new keys without being rebuilt.
+func (kr *Keyring) Replace(other *Keyring) {
+	other.mu.RLock()
+	active, keys := other.active, other.keys
+	other.mu.RUnlock()
+
+	kr.mu.Lock()
+	kr.active, kr.keys = active, keys
+	kr.mu.Unlock()
+}
+
+func (kr *Keyring) Active() *Key {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	return kr.active
+}
+
+func (kr *Keyring) Lookup(kid string) (*Key, error) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	key, ok := kr.keys[kid]
+	if !ok || key.retired(time.Now()) {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+
+	return key, nil
+}
+
+func (kr *Keyring) JWKS() JWKS {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+
+	now := time.Now()
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range kr.keys {
+		if key.retired(now) {
+			continue
+		}
+		if jwk, ok := key.JWK(); ok {
+			jwks.Keys = append(jwks.Keys, jwk)
+		}
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
+
+func (kr *Keyring) set(active *Key, verification []*Key) error {
+	if active == nil {
+		return fmt.Errorf("%w: no active key", ErrInvalidKey)
+	}
+	if !active.retireAt.IsZero() {
+		return fmt.Errorf("%w: active key %q has a retirement time", ErrInvalidKey, active.id)
+	}
+
+	keys := make(map[string]*Key, len(verification)+1)
+	for _, key := range append([]*Key{active}, verification...) {
+		if key.id == "" {
+			return fmt.Errorf("%w: key without kid", ErrInvalidKey)
+		}
+		if _, ok := keys[key.id]; ok {
+			return fmt.Errorf("%w: duplicate kid %q", ErrInvalidKey, key.id)
+		}
+		keys[key.id] = key
+	}
+
+	kr.mu.Lock()
+	kr.active, kr.keys = active, keys
+	kr.mu.Unlock()
+
+	return nil
+}
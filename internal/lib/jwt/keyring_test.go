@@ -0,0 +1,149 @@
+package jwt
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyring_OldTokensVerifyAfterRotation(t *testing.T) {
+	oldKey := NewHMACKey("old", "old_secret")
+	newKey := NewHMACKey("new", "new_secret")
+
+	before, err := NewKeyring(oldKey)
+	require.NoError(t, err)
+
+	token, err := NewIssuer(before, "", nil).NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	after, err := NewKeyring(newKey, oldKey.RetireAfter(time.Now().Add(time.Hour)))
+	require.NoError(t, err)
+
+	_, err = NewIssuer(after, "", nil).ParseToken(token)
+	require.NoError(t, err)
+
+	fresh, err := NewIssuer(after, "", nil).NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(fresh, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", parsed.Header["kid"])
+}
+
+func TestKeyring_RetiredKeyRejected(t *testing.T) {
+	oldKey := NewHMACKey("old", "old_secret")
+
+	before, err := NewKeyring(oldKey)
+	require.NoError(t, err)
+
+	token, err := NewIssuer(before, "", nil).NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	after, err := NewKeyring(
+		NewHMACKey("new", "new_secret"),
+		oldKey.RetireAfter(time.Now().Add(-time.Second)),
+	)
+	require.NoError(t, err)
+
+	_, err = NewIssuer(after, "", nil).ParseToken(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestKeyring_TokenWithoutKidUsesActiveKey(t *testing.T) {
+	claims := Claims{
+		UserID: uuid.New(),
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	parsed, err := NewIssuer(newHMACKeyring(t, "secret"), "", nil).ParseToken(token)
+
+	require.NoError(t, err)
+	assert.Equal(t, claims.UserID, parsed.UserID)
+}
+
+func TestKeyring_Replace(t *testing.T) {
+	keyring := newHMACKeyring(t, "secret")
+
+	replacement, err := NewKeyring(NewHMACKey("next", "next_secret"))
+	require.NoError(t, err)
+
+	keyring.Replace(replacement)
+
+	assert.Equal(t, "next", keyring.Active().ID())
+	_, err = keyring.Lookup("test")
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestNewKeyring_DuplicateKid(t *testing.T) {
+	_, err := NewKeyring(NewHMACKey("same", "a"), NewHMACKey("same", "b"))
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestKeyFile_PromoteAndPrune(t *testing.T) {
+	now := time.Now()
+	path := filepath.Join(t.TempDir(), "keyring.yaml")
+
+	f := &KeyFile{
+		Keys: []KeyFileEntry{
+			{ID: "old", Algorithm: AlgHS256, Secret: "old_secret", Status: KeyStatusActive},
+			{ID: "new", Algorithm: AlgHS256, Secret: "new_secret", Status: KeyStatusVerify},
+		},
+	}
+
+	require.NoError(t, f.Promote("new", now, time.Hour))
+	require.NoError(t, f.WriteFile(path))
+
+	f, err := ReadKeyFile(path)
+	require.NoError(t, err)
+
+	keyring, err := f.Keyring()
+	require.NoError(t, err)
+	assert.Equal(t, "new", keyring.Active().ID())
+
+	_, err = keyring.Lookup("old")
+	require.NoError(t, err, "old key must verify during the overlap")
+
+	assert.Empty(t, f.Prune(now))
+	assert.Equal(t, []string{"old"}, f.Prune(now.Add(2*time.Hour)))
+	require.Len(t, f.Keys, 1)
+	assert.Equal(t, "new", f.Keys[0].ID)
+}
+
+func TestKeyFile_PromoteUnknownKey(t *testing.T) {
+	f := &KeyFile{
+		Keys: []KeyFileEntry{
+			{ID: "old", Algorithm: AlgHS256, Secret: "old_secret", Status: KeyStatusActive},
+		},
+	}
+
+	err := f.Promote("missing", time.Now(), time.Hour)
+
+	assert.ErrorIs(t, err, ErrUnknownKey)
+}
+
+func TestKeyFile_SecondActiveKeyRejected(t *testing.T) {
+	f := &KeyFile{
+		Keys: []KeyFileEntry{
+			{ID: "a", Algorithm: AlgHS256, Secret: "a", Status: KeyStatusActive},
+			{ID: "b", Algorithm: AlgHS256, Secret: "b", Status: KeyStatusActive},
+		},
+	}
+
+	_, err := f.Keyring()
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
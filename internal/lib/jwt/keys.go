@@ -0,0 +1,177 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+var (
+	ErrUnsupportedAlgorithm = errors.New("unsupported signing algorithm")
+	ErrInvalidKey           = errors.New("invalid signing key")
+)
+
+// Key is a signing key together with the material needed to verify its
+// signatures. For HS256 both are the shared secret; for asymmetric
+// algorithms verification only needs the public half.
+type Key struct {
+	id        string
+	method    jwt.SigningMethod
+	signKey   any
+	verifyKey any
+	retireAt  time.Time
+}
+
+func NewHMACKey(
+	id string,
+	secret string,
+) *Key {
+	return &Key{
+		id:        id,
+		method:    jwt.SigningMethodHS256,
+		signKey:   []byte(secret),
+		verifyKey: []byte(secret),
+	}
+}
+
+// LoadKey builds the signing key described by the auth config: the shared
+// secret for HS256, or a PEM encoded private key for the asymmetric ones.
+func LoadKey(
+	id string,
+	alg string,
+	secret string,
+	privateKeyPath string,
+) (*Key, error) {
+	if alg == "" || alg == AlgHS256 {
+		if secret == "" {
+			return nil, fmt.Errorf("%w: empty secret for %s", ErrInvalidKey, AlgHS256)
+		}
+		return NewHMACKey(id, secret), nil
+	}
+
+	data, err := os.ReadFile(privateKeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+
+	return ParsePrivateKeyPEM(id, alg, data)
+}
+
+func ParsePrivateKeyPEM(
+	id string,
+	alg string,
+	data []byte,
+) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", ErrInvalidKey)
+	}
+
+	privateKey, err := parsePrivateKey(block)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidKey, err)
+	}
+
+	return NewAsymmetricKey(id, alg, privateKey)
+}
+
+func NewAsymmetricKey(
+	id string,
+	alg string,
+	privateKey crypto.Signer,
+) (*Key, error) {
+	var method jwt.SigningMethod
+
+	switch alg {
+	case AlgRS256:
+		if _, ok := privateKey.(*rsa.PrivateKey); !ok {
+			return nil, fmt.Errorf("%w: %s requires an RSA key", ErrInvalidKey, alg)
+		}
+		method = jwt.SigningMethodRS256
+	case AlgES256:
+		ecKey, ok := privateKey.(*ecdsa.PrivateKey)
+		if !ok || ecKey.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("%w: %s requires a P-256 key", ErrInvalidKey, alg)
+		}
+		method = jwt.SigningMethodES256
+	case AlgEdDSA:
+		if _, ok := privateKey.(ed25519.PrivateKey); !ok {
+			return nil, fmt.Errorf("%w: %s requires an Ed25519 key", ErrInvalidKey, alg)
+		}
+		method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedAlgorithm, alg)
+	}
+
+	return &Key{
+		id:        id,
+		method:    method,
+		signKey:   privateKey,
+		verifyKey: privateKey.Public(),
+	}, nil
+}
+
+func (k *Key) ID() string {
+	return k.id
+}
+
+func (k *Key) Algorithm() string {
+	return k.method.Alg()
+}
+
+// RetireAfter limits the key to verifying tokens until t. A zero t keeps
+// the key valid indefinitely.
+func (k *Key) RetireAfter(t time.Time) *Key {
+	retired := *k
+	retired.retireAt = t
+	return &retired
+}
+
+func (k *Key) retired(now time.Time) bool {
+	return !k.retireAt.IsZero() && now.After(k.retireAt)
+}
+
+func parsePrivateKey(block *pem.Block) (crypto.Signer, error) {
+	var (
+		key any
+		err error
+	)
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block type %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T", key)
+	}
+
+	return signer, nil
+}
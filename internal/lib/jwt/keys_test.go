@@ -0,0 +1,151 @@
+package jwt
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsymmetricKeys_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		alg    string
+		kty    string
+		newKey func(t *testing.T) crypto.Signer
+	}{
+		{
+			name: "RS256",
+			alg:  AlgRS256,
+			kty:  "RSA",
+			newKey: func(t *testing.T) crypto.Signer {
+				key, err := rsa.GenerateKey(rand.Reader, 2048)
+				require.NoError(t, err)
+				return key
+			},
+		},
+		{
+			name: "ES256",
+			alg:  AlgES256,
+			kty:  "EC",
+			newKey: func(t *testing.T) crypto.Signer {
+				key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+				require.NoError(t, err)
+				return key
+			},
+		},
+		{
+			name: "EdDSA",
+			alg:  AlgEdDSA,
+			kty:  "OKP",
+			newKey: func(t *testing.T) crypto.Signer {
+				_, key, err := ed25519.GenerateKey(rand.Reader)
+				require.NoError(t, err)
+				return key
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writePKCS8(t, tt.newKey(t))
+
+			key, err := LoadKey("test", tt.alg, "", path)
+			require.NoError(t, err)
+			assert.Equal(t, tt.alg, key.Algorithm())
+
+			keyring, err := NewKeyring(key)
+			require.NoError(t, err)
+
+			user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+			token, err := NewIssuer(keyring, "", nil).NewToken(context.Background(), user, time.Hour)
+			require.NoError(t, err)
+
+			claims, err := NewIssuer(keyring, "", nil).ParseToken(token)
+			require.NoError(t, err)
+			assert.Equal(t, user.ID, claims.UserID)
+
+			jwks := keyring.JWKS()
+			require.Len(t, jwks.Keys, 1)
+			assert.Equal(t, "test", jwks.Keys[0].Kid)
+			assert.Equal(t, tt.kty, jwks.Keys[0].Kty)
+			assert.Equal(t, tt.alg, jwks.Keys[0].Alg)
+			assert.Equal(t, "sig", jwks.Keys[0].Use)
+		})
+	}
+}
+
+func TestLoadKey_HMACPublishesNothing(t *testing.T) {
+	key, err := LoadKey("test", AlgHS256, "secret", "")
+	require.NoError(t, err)
+
+	_, ok := key.JWK()
+	assert.False(t, ok)
+}
+
+func TestLoadKey_EmptySecret(t *testing.T) {
+	_, err := LoadKey("test", AlgHS256, "", "")
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestLoadKey_AlgorithmMismatch(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	path := writePKCS8(t, edKey)
+
+	_, err = LoadKey("test", AlgRS256, "", path)
+
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
+
+func TestLoadKey_UnsupportedAlgorithm(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	path := writePKCS8(t, edKey)
+
+	_, err = LoadKey("test", "PS512", "", path)
+
+	assert.ErrorIs(t, err, ErrUnsupportedAlgorithm)
+}
+
+func TestParseToken_RejectsAlgorithmSwitch(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	key, err := NewAsymmetricKey("test", AlgES256, ecKey)
+	require.NoError(t, err)
+	keyring, err := NewKeyring(key)
+	require.NoError(t, err)
+
+	token, err := newTestIssuer(t, "secret").NewToken(context.Background(), userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	_, err = NewIssuer(keyring, "", nil).ParseToken(token)
+
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func writePKCS8(t *testing.T, key crypto.Signer) string {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "key.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
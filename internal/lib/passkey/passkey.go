@@ -0,0 +1,287 @@
+package passkey
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidResponse reports an authenticator response that does not
+	// verify against the ceremony.
+	ErrInvalidResponse = errors.New("invalid webauthn response")
+	// ErrCloned reports an assertion whose sign counter did not increase,
+	// which suggests the credential was copied to another authenticator.
+	ErrCloned = errors.New("authenticator sign counter did not increase")
+	// ErrUserNotVerified reports a response made without user
+	// verification, by PIN or biometrics, which every ceremony requires.
+	ErrUserNotVerified = errors.New("user not verified")
+)
+
+type Config struct {
+	RPID          string
+	RPDisplayName string
+	RPOrigins     []string
+	Timeout       time.Duration
+}
+
+// User is the account a ceremony is for, with its registered passkeys.
+type User struct {
+	ID          uuid.UUID
+	Name        string
+	Credentials []passkeyModel.Credential
+}
+
+// UserLookup returns the owner of the credential an assertion was made
+// with, from the user handle the authenticator sent along.
+type UserLookup func(credentialID []byte, userID uuid.UUID) (User, error)
+
+// RelyingParty runs WebAuthn ceremonies. Options and responses are the
+// JSON of the browser's navigator.credentials API; the session returned
+// by a Begin call has to be kept until the matching Finish call.
+// Ceremonies require user verification, and registrations create
+// discoverable credentials, so that a passkey alone logs a user in.
+type RelyingParty struct {
+	webAuthn *webauthn.WebAuthn
+}
+
+func New(cfg Config) (*RelyingParty, error) {
+	timeout := webauthn.TimeoutConfig{
+		Enforce: true,
+		Timeout: cfg.Timeout,
+	}
+
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          cfg.RPID,
+		RPDisplayName: cfg.RPDisplayName,
+		RPOrigins:     cfg.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        timeout,
+			Registration: timeout,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RelyingParty{
+		webAuthn: webAuthn,
+	}, nil
+}
+
+func (rp *RelyingParty) BeginRegistration(user User) ([]byte, []byte, error) {
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Credentials))
+	for _, credential := range user.Credentials {
+		exclusions = append(exclusions, protocol.CredentialDescriptor{
+			Type:         protocol.PublicKeyCredentialType,
+			CredentialID: credential.CredentialID,
+		})
+	}
+
+	creation, session, err := rp.webAuthn.BeginRegistration(
+		webAuthnUser{user},
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithAuthenticatorSelection(protocol.AuthenticatorSelection{
+			ResidentKey:      protocol.ResidentKeyRequirementRequired,
+			UserVerification: protocol.VerificationRequired,
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encode(creation, session)
+}
+
+// FinishRegistration verifies the attestation response to a registration
+// of user and returns the new credential.
+func (rp *RelyingParty) FinishRegistration(
+	user     User,
+	session  []byte,
+	response []byte,
+) (passkeyModel.Credential, error) {
+	var sessionData webauthn.SessionData
+	if err := json.Unmarshal(session, &sessionData); err != nil {
+		return passkeyModel.Credential{}, fmt.Errorf("decode session: %w", err)
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return passkeyModel.Credential{}, invalidResponse(err)
+	}
+
+	credential, err := rp.webAuthn.CreateCredential(webAuthnUser{user}, sessionData, parsed)
+	if err != nil {
+		return passkeyModel.Credential{}, invalidResponse(err)
+	}
+
+	if !credential.Flags.UserVerified {
+		return passkeyModel.Credential{}, fmt.Errorf("%w: %w", ErrInvalidResponse, ErrUserNotVerified)
+	}
+
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return passkeyModel.Credential{
+		UserID:          user.ID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		Transports:      transports,
+	}, nil
+}
+
+// BeginLogin starts an assertion for any discoverable credential, so the
+// user is only known once the response names it.
+func (rp *RelyingParty) BeginLogin() ([]byte, []byte, error) {
+	assertion, session, err := rp.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return encode(assertion, session)
+}
+
+// FinishLogin verifies the assertion response of a login and returns the
+// credential used, with its sign counter updated. It fails with ErrCloned
+// if the counter went backwards.
+func (rp *RelyingParty) FinishLogin(
+	session  []byte,
+	response []byte,
+	lookup   UserLookup,
+) (passkeyModel.Credential, error) {
+	var sessionData webauthn.SessionData
+	if err := json.Unmarshal(session, &sessionData); err != nil {
+		return passkeyModel.Credential{}, fmt.Errorf("decode session: %w", err)
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return passkeyModel.Credential{}, invalidResponse(err)
+	}
+
+	var owner User
+	handler := func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+
+		owner, err = lookup(rawID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		return webAuthnUser{owner}, nil
+	}
+
+	credential, err := rp.webAuthn.ValidateDiscoverableLogin(handler, sessionData, parsed)
+	if err != nil {
+		return passkeyModel.Credential{}, invalidResponse(err)
+	}
+
+	// The library already checks the flag against the session, but the
+	// auth service skips MFA for passkey logins because of it, so it is
+	// checked here regardless of how the session was built.
+	if !credential.Flags.UserVerified {
+		return passkeyModel.Credential{}, fmt.Errorf("%w: %w", ErrInvalidResponse, ErrUserNotVerified)
+	}
+
+	if credential.Authenticator.CloneWarning {
+		return passkeyModel.Credential{}, ErrCloned
+	}
+
+	for _, stored := range owner.Credentials {
+		if bytes.Equal(stored.CredentialID, credential.ID) {
+			stored.SignCount = credential.Authenticator.SignCount
+			return stored, nil
+		}
+	}
+
+	return passkeyModel.Credential{}, ErrInvalidResponse
+}
+
+func encode(options any, session *webauthn.SessionData) ([]byte, []byte, error) {
+	encodedOptions, err := json.Marshal(options)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode options: %w", err)
+	}
+
+	encodedSession, err := json.Marshal(session)
+	if err != nil {
+		return nil, nil, fmt.Errorf("encode session: %w", err)
+	}
+
+	return encodedOptions, encodedSession, nil
+}
+
+// invalidResponse keeps the reason a response was rejected, which the
+// library reports in its error details.
+func invalidResponse(err error) error {
+	var protocolErr *protocol.Error
+	if errors.As(err, &protocolErr) {
+		return fmt.Errorf("%w: %s: %s", ErrInvalidResponse, protocolErr.Details, protocolErr.DevInfo)
+	}
+
+	return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+}
+
+// webAuthnUser adapts User to the library, which identifies users by
+// their ID bytes. The same bytes come back as the user handle of an
+// assertion.
+type webAuthnUser struct {
+	User
+}
+
+func (u webAuthnUser) WebAuthnID() []byte {
+	return u.ID[:]
+}
+
+func (u webAuthnUser) WebAuthnName() string {
+	return u.Name
+}
+
+func (u webAuthnUser) WebAuthnDisplayName() string {
+	return u.Name
+}
+
+func (u webAuthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u webAuthnUser) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.Credentials))
+	for _, credential := range u.Credentials {
+		transports := make([]protocol.AuthenticatorTransport, 0, len(credential.Transports))
+		for _, transport := range credential.Transports {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+
+		credentials = append(credentials, webauthn.Credential{
+			ID:              credential.CredentialID,
+			PublicKey:       credential.PublicKey,
+			AttestationType: credential.AttestationType,
+			Transport:       transports,
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    credential.AAGUID,
+				SignCount: credential.SignCount,
+			},
+		})
+	}
+
+	return credentials
+}
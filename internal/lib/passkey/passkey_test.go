@@ -0,0 +1,206 @@
+package passkey
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/Tbits007/auth/internal/lib/passkey/passkeytest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testOrigin = "https://auth.example.com"
+
+func newTestRelyingParty(t *testing.T) *RelyingParty {
+	t.Helper()
+
+	rp, err := New(Config{
+		RPID:          "auth.example.com",
+		RPDisplayName: "Auth",
+		RPOrigins:     []string{testOrigin},
+		Timeout:       time.Minute,
+	})
+	require.NoError(t, err)
+
+	return rp
+}
+
+func register(t *testing.T, rp *RelyingParty, user User, authenticator *passkeytest.Authenticator) passkeyModel.Credential {
+	t.Helper()
+
+	options, session, err := rp.BeginRegistration(user)
+	require.NoError(t, err)
+
+	response, err := authenticator.Register(options)
+	require.NoError(t, err)
+
+	credential, err := rp.FinishRegistration(user, session, response)
+	require.NoError(t, err)
+
+	return credential
+}
+
+func TestRegisterAndLogin(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	user := User{ID: uuid.New(), Name: "test@example.com"}
+
+	authenticator, err := passkeytest.New(testOrigin)
+	require.NoError(t, err)
+
+	credential := register(t, rp, user, authenticator)
+	assert.Equal(t, user.ID, credential.UserID)
+	assert.Equal(t, authenticator.CredentialID, credential.CredentialID)
+	assert.NotEmpty(t, credential.PublicKey)
+	assert.Equal(t, "none", credential.AttestationType)
+	assert.Equal(t, []string{"internal"}, credential.Transports)
+	assert.Equal(t, user.ID[:], authenticator.UserHandle)
+
+	user.Credentials = []passkeyModel.Credential{credential}
+
+	options, session, err := rp.BeginLogin()
+	require.NoError(t, err)
+
+	response, err := authenticator.Assert(options)
+	require.NoError(t, err)
+
+	used, err := rp.FinishLogin(session, response, func(credentialID []byte, userID uuid.UUID) (User, error) {
+		assert.Equal(t, authenticator.CredentialID, credentialID)
+		assert.Equal(t, user.ID, userID)
+		return user, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, credential.CredentialID, used.CredentialID)
+	assert.Equal(t, uint32(1), used.SignCount)
+}
+
+func TestFinishRegistration_Rejects(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	user := User{ID: uuid.New(), Name: "test@example.com"}
+
+	tests := []struct {
+		name   string
+		origin string
+		user   User
+	}{
+		{name: "foreign origin", origin: "https://evil.example.com", user: user},
+		{name: "other user", origin: testOrigin, user: User{ID: uuid.New(), Name: "other@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, err := passkeytest.New(tt.origin)
+			require.NoError(t, err)
+
+			options, session, err := rp.BeginRegistration(user)
+			require.NoError(t, err)
+
+			response, err := authenticator.Register(options)
+			require.NoError(t, err)
+
+			_, err = rp.FinishRegistration(tt.user, session, response)
+			assert.ErrorIs(t, err, ErrInvalidResponse)
+		})
+	}
+}
+
+func TestFinishLogin_Rejects(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	user := User{ID: uuid.New(), Name: "test@example.com"}
+
+	authenticator, err := passkeytest.New(testOrigin)
+	require.NoError(t, err)
+
+	credential := register(t, rp, user, authenticator)
+	user.Credentials = []passkeyModel.Credential{credential}
+	lookup := func([]byte, uuid.UUID) (User, error) { return user, nil }
+
+	t.Run("other challenge", func(t *testing.T) {
+		options, _, err := rp.BeginLogin()
+		require.NoError(t, err)
+		_, session, err := rp.BeginLogin()
+		require.NoError(t, err)
+
+		response, err := authenticator.Assert(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishLogin(session, response, lookup)
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+	})
+
+	t.Run("unknown credential", func(t *testing.T) {
+		options, session, err := rp.BeginLogin()
+		require.NoError(t, err)
+
+		response, err := authenticator.Assert(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishLogin(session, response, func([]byte, uuid.UUID) (User, error) {
+			return User{}, errors.New("not found")
+		})
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+	})
+
+	t.Run("sign counter went back", func(t *testing.T) {
+		cloned := user
+		cloned.Credentials = []passkeyModel.Credential{credential}
+		cloned.Credentials[0].SignCount = 10
+
+		options, session, err := rp.BeginLogin()
+		require.NoError(t, err)
+
+		authenticator.SignCount = 4
+		response, err := authenticator.Assert(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishLogin(session, response, func([]byte, uuid.UUID) (User, error) {
+			return cloned, nil
+		})
+		assert.ErrorIs(t, err, ErrCloned)
+	})
+}
+
+func TestCeremonies_RequireUserVerification(t *testing.T) {
+	rp := newTestRelyingParty(t)
+	user := User{ID: uuid.New(), Name: "test@example.com"}
+
+	t.Run("registration", func(t *testing.T) {
+		authenticator, err := passkeytest.New(testOrigin)
+		require.NoError(t, err)
+		authenticator.SkipUserVerification = true
+
+		options, session, err := rp.BeginRegistration(user)
+		require.NoError(t, err)
+		assert.Contains(t, string(options), `"userVerification":"required"`)
+
+		response, err := authenticator.Register(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishRegistration(user, session, response)
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+	})
+
+	t.Run("login", func(t *testing.T) {
+		authenticator, err := passkeytest.New(testOrigin)
+		require.NoError(t, err)
+
+		credential := register(t, rp, user, authenticator)
+		owner := user
+		owner.Credentials = []passkeyModel.Credential{credential}
+
+		options, session, err := rp.BeginLogin()
+		require.NoError(t, err)
+		assert.Contains(t, string(options), `"userVerification":"required"`)
+
+		authenticator.SkipUserVerification = true
+		response, err := authenticator.Assert(options)
+		require.NoError(t, err)
+
+		_, err = rp.FinishLogin(session, response, func([]byte, uuid.UUID) (User, error) {
+			return owner, nil
+		})
+		assert.ErrorIs(t, err, ErrInvalidResponse)
+	})
+}
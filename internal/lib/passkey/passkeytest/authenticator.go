@@ -0,0 +1,193 @@
+// Package passkeytest provides a software WebAuthn authenticator, so that
+// ceremonies can be tested without a browser or security key.
+package passkeytest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+// Authenticator data flags.
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttested     = 0x40
+)
+
+// Authenticator holds a single ES256 credential with "none" attestation.
+// SignCount is the counter reported by the next assertion; each assertion
+// increments it. With SkipUserVerification its responses only report user
+// presence, like a security key used without its PIN.
+type Authenticator struct {
+	Origin               string
+	CredentialID         []byte
+	UserHandle           []byte
+	SignCount            uint32
+	SkipUserVerification bool
+
+	key *ecdsa.PrivateKey
+}
+
+func New(origin string) (*Authenticator, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		return nil, err
+	}
+
+	return &Authenticator{
+		Origin:       origin,
+		CredentialID: credentialID,
+		key:          key,
+	}, nil
+}
+
+type creationOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RP        struct {
+			ID string `json:"id"`
+		} `json:"rp"`
+		User struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	} `json:"publicKey"`
+}
+
+type requestOptions struct {
+	PublicKey struct {
+		Challenge string `json:"challenge"`
+		RPID      string `json:"rpId"`
+	} `json:"publicKey"`
+}
+
+// Register answers registration options with an attestation response.
+func (a *Authenticator) Register(options []byte) ([]byte, error) {
+	var opts creationOptions
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, fmt.Errorf("decode options: %w", err)
+	}
+
+	userHandle, err := base64.RawURLEncoding.DecodeString(opts.PublicKey.User.ID)
+	if err != nil {
+		return nil, fmt.Errorf("decode user id: %w", err)
+	}
+	a.UserHandle = userHandle
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authData := a.authData(opts.PublicKey.RP.ID, a.flags()|flagAttested)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.CredentialID)))
+	authData = append(authData, a.CredentialID...)
+	authData = append(authData, publicKey...)
+
+	attestationObject, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":    a.encodedID(),
+		"rawId": a.encodedID(),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encode(a.clientData("webauthn.create", opts.PublicKey.Challenge)),
+			"attestationObject": encode(attestationObject),
+			"transports":        []string{"internal"},
+		},
+	})
+}
+
+// Assert answers login options with an assertion response.
+func (a *Authenticator) Assert(options []byte) ([]byte, error) {
+	var opts requestOptions
+	if err := json.Unmarshal(options, &opts); err != nil {
+		return nil, fmt.Errorf("decode options: %w", err)
+	}
+
+	a.SignCount++
+
+	authData := a.authData(opts.PublicKey.RPID, a.flags())
+	clientData := a.clientData("webauthn.get", opts.PublicKey.Challenge)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(map[string]any{
+		"id":    a.encodedID(),
+		"rawId": a.encodedID(),
+		"type":  "public-key",
+		"response": map[string]any{
+			"clientDataJSON":    encode(clientData),
+			"authenticatorData": encode(authData),
+			"signature":         encode(signature),
+			"userHandle":        encode(a.UserHandle),
+		},
+	})
+}
+
+func (a *Authenticator) flags() byte {
+	if a.SkipUserVerification {
+		return flagUserPresent
+	}
+	return flagUserPresent | flagUserVerified
+}
+
+func (a *Authenticator) authData(rpID string, flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	authData := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(authData, a.SignCount)
+}
+
+func (a *Authenticator) clientData(ceremony, challenge string) []byte {
+	clientData, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": challenge,
+		"origin":    a.Origin,
+	})
+
+	return clientData
+}
+
+func (a *Authenticator) encodedID() string {
+	return encode(a.CredentialID)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
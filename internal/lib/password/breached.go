@@ -0,0 +1,88 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// prefixLen is the length of the hash prefix used to bucket the list, as
+// in the k-anonymity range API of Have I Been Pwned.
+const prefixLen = 5
+
+// BreachedList is an offline set of SHA-1 digests of common or breached
+// passwords, bucketed by their 5 character hex prefix.
+type BreachedList struct {
+	buckets map[string]map[string]struct{}
+}
+
+// LoadBreachedList reads a file of uppercase or lowercase hex SHA-1
+// digests, one per line. Anything after a colon, such as the occurrence
+// count of the HIBP dumps, is ignored, as are blank lines and lines
+// starting with '#'.
+func LoadBreachedList(path string) (*BreachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	list := &BreachedList{buckets: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		digest, _, _ := strings.Cut(line, ":")
+		digest = strings.ToUpper(digest)
+		if len(digest) != sha1.Size*2 {
+			return nil, fmt.Errorf("%s:%d: not a SHA-1 digest", path, n)
+		}
+		if _, err := hex.DecodeString(digest); err != nil {
+			return nil, fmt.Errorf("%s:%d: not a SHA-1 digest: %w", path, n, err)
+		}
+
+		list.add(digest)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// Contains reports whether password is on the list.
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, ok := l.buckets[digest[:prefixLen]][digest[prefixLen:]]
+	return ok
+}
+
+// Len returns the number of digests on the list.
+func (l *BreachedList) Len() int {
+	n := 0
+	for _, bucket := range l.buckets {
+		n += len(bucket)
+	}
+
+	return n
+}
+
+func (l *BreachedList) add(digest string) {
+	prefix, suffix := digest[:prefixLen], digest[prefixLen:]
+
+	bucket, ok := l.buckets[prefix]
+	if !ok {
+		bucket = make(map[string]struct{})
+		l.buckets[prefix] = bucket
+	}
+	bucket[suffix] = struct{}{}
+}
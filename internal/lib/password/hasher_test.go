@@ -0,0 +1,100 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testArgon2id = Argon2id{
+	Memory:      8 * 1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestAlgorithms_HashAndVerify(t *testing.T) {
+	tests := []struct {
+		name   string
+		alg    Algorithm
+		prefix string
+	}{
+		{name: "bcrypt", alg: Bcrypt{Cost: bcrypt.MinCost}, prefix: "$2a$04$"},
+		{name: "argon2id", alg: testArgon2id, prefix: "$argon2id$v=19$m=8192,t=1,p=1$"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := tt.alg.Hash("correct-horse")
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(encoded, tt.prefix), encoded)
+			assert.True(t, tt.alg.Identifies(encoded))
+			assert.False(t, tt.alg.Weaker(encoded))
+
+			ok, err := tt.alg.Verify(encoded, "correct-horse")
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = tt.alg.Verify(encoded, "wrong-horse")
+			require.NoError(t, err)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestHasher_MigratesLegacyHashes(t *testing.T) {
+	legacy := Bcrypt{Cost: bcrypt.MinCost}
+	hasher := NewHasher(testArgon2id, legacy)
+
+	old, err := legacy.Hash("correct-horse")
+	require.NoError(t, err)
+
+	ok, err := hasher.Verify(old, "correct-horse")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(old))
+
+	fresh, err := hasher.Hash("correct-horse")
+	require.NoError(t, err)
+	assert.True(t, testArgon2id.Identifies(fresh))
+	assert.False(t, hasher.NeedsRehash(fresh))
+
+	_, err = hasher.Verify("plaintext", "correct-horse")
+	assert.ErrorIs(t, err, ErrUnknownHash)
+}
+
+func TestHasher_RehashesWeakerParameters(t *testing.T) {
+	weak := testArgon2id
+	weak.Iterations = 1
+	strong := testArgon2id
+	strong.Iterations = 2
+
+	encoded, err := weak.Hash("correct-horse")
+	require.NoError(t, err)
+
+	assert.True(t, NewHasher(strong).NeedsRehash(encoded))
+	assert.False(t, NewHasher(weak).NeedsRehash(encoded))
+
+	cheap, err := Bcrypt{Cost: bcrypt.MinCost}.Hash("correct-horse")
+	require.NoError(t, err)
+
+	assert.True(t, NewHasher(Bcrypt{Cost: bcrypt.MinCost + 1}).NeedsRehash(cheap))
+	assert.False(t, NewHasher(Bcrypt{Cost: bcrypt.MinCost}).NeedsRehash(cheap))
+}
+
+func TestArgon2id_Malformed(t *testing.T) {
+	for _, encoded := range []string{
+		"$argon2id$v=19$m=8192,t=1,p=1$salt",
+		"$argon2id$v=18$m=8192,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdA$a2V5",
+		"$argon2id$v=19$m=8192,t=1,p=1$!!$a2V5",
+	} {
+		_, err := testArgon2id.Verify(encoded, "correct-horse")
+		assert.Error(t, err, encoded)
+		assert.True(t, testArgon2id.Weaker(encoded), encoded)
+	}
+}
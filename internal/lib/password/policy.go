@@ -0,0 +1,137 @@
+package password
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxBcryptBytes is the longest input bcrypt hashes; it rejects anything
+// longer.
+const MaxBcryptBytes = 72
+
+// Rules a password can violate. They double as the reason reported to
+// clients.
+const (
+	RuleMinLength = "MIN_LENGTH"
+	RuleMaxLength = "MAX_LENGTH"
+	RuleLowercase = "MISSING_LOWERCASE"
+	RuleUppercase = "MISSING_UPPERCASE"
+	RuleDigit     = "MISSING_DIGIT"
+	RuleSymbol    = "MISSING_SYMBOL"
+	RuleBreached  = "BREACHED"
+)
+
+type Config struct {
+	MinLength int
+	// MaxLength is counted in characters; passwords longer than
+	// MaxBcryptBytes bytes are rejected regardless.
+	MaxLength        int
+	RequireLowercase bool
+	RequireUppercase bool
+	RequireDigit     bool
+	RequireSymbol    bool
+}
+
+type Violation struct {
+	Rule        string
+	Description string
+}
+
+// PolicyError lists every rule a password violated.
+type PolicyError struct {
+	Violations []Violation
+}
+
+func (e *PolicyError) Error() string {
+	rules := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		rules = append(rules, v.Rule)
+	}
+
+	return "password violates policy: " + strings.Join(rules, ", ")
+}
+
+type Policy struct {
+	cfg      Config
+	breached *BreachedList
+}
+
+// NewPolicy validates cfg and returns a policy enforcing it. breached may
+// be nil to skip the breached password check.
+func NewPolicy(cfg Config, breached *BreachedList) (*Policy, error) {
+	if cfg.MinLength < 1 {
+		return nil, fmt.Errorf("min length must be positive, got %d", cfg.MinLength)
+	}
+
+	if cfg.MaxLength < cfg.MinLength {
+		return nil, fmt.Errorf("max length %d is below min length %d", cfg.MaxLength, cfg.MinLength)
+	}
+
+	if cfg.MaxLength > MaxBcryptBytes {
+		return nil, fmt.Errorf("max length %d exceeds the bcrypt limit of %d bytes", cfg.MaxLength, MaxBcryptBytes)
+	}
+
+	return &Policy{cfg: cfg, breached: breached}, nil
+}
+
+// Validate returns a *PolicyError if password violates any rule.
+func (p *Policy) Validate(password string) error {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(password)
+	if length < p.cfg.MinLength {
+		violations = append(violations, Violation{
+			Rule:        RuleMinLength,
+			Description: fmt.Sprintf("must be at least %d characters long", p.cfg.MinLength),
+		})
+	}
+
+	if length > p.cfg.MaxLength || len(password) > MaxBcryptBytes {
+		violations = append(violations, Violation{
+			Rule:        RuleMaxLength,
+			Description: fmt.Sprintf("must be at most %d characters and %d bytes long", p.cfg.MaxLength, MaxBcryptBytes),
+		})
+	}
+
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			symbol = true
+		}
+	}
+
+	if p.cfg.RequireLowercase && !lower {
+		violations = append(violations, Violation{Rule: RuleLowercase, Description: "must contain a lowercase letter"})
+	}
+	if p.cfg.RequireUppercase && !upper {
+		violations = append(violations, Violation{Rule: RuleUppercase, Description: "must contain an uppercase letter"})
+	}
+	if p.cfg.RequireDigit && !digit {
+		violations = append(violations, Violation{Rule: RuleDigit, Description: "must contain a digit"})
+	}
+	if p.cfg.RequireSymbol && !symbol {
+		violations = append(violations, Violation{Rule: RuleSymbol, Description: "must contain a symbol"})
+	}
+
+	if p.breached != nil && p.breached.Contains(password) {
+		violations = append(violations, Violation{
+			Rule:        RuleBreached,
+			Description: "is too common or appeared in a data breach",
+		})
+	}
+
+	if len(violations) > 0 {
+		return &PolicyError{Violations: violations}
+	}
+
+	return nil
+}
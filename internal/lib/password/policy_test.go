@@ -0,0 +1,122 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	policy, err := NewPolicy(Config{
+		MinLength:        8,
+		MaxLength:        64,
+		RequireLowercase: true,
+		RequireUppercase: true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+	}, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		password string
+		want     []string
+	}{
+		{name: "valid", password: "Correct-Horse-1"},
+		{name: "unicode letters", password: "Пароль-надёжный-1"},
+		{name: "too short", password: "Ab1!", want: []string{RuleMinLength}},
+		{name: "too many characters", password: "Aa1!" + strings.Repeat("a", 61), want: []string{RuleMaxLength}},
+		{name: "beyond bcrypt limit", password: "Aa1!" + strings.Repeat("ж", 35), want: []string{RuleMaxLength}},
+		{
+			name:     "every class missing",
+			password: "        ",
+			want:     []string{RuleLowercase, RuleUppercase, RuleDigit, RuleSymbol},
+		},
+		{
+			name:     "single character",
+			password: "a",
+			want:     []string{RuleMinLength, RuleUppercase, RuleDigit, RuleSymbol},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Validate(tt.password)
+
+			if tt.want == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var policyErr *PolicyError
+			require.ErrorAs(t, err, &policyErr)
+
+			rules := make([]string, 0, len(policyErr.Violations))
+			for _, v := range policyErr.Violations {
+				assert.NotEmpty(t, v.Description)
+				rules = append(rules, v.Rule)
+			}
+			assert.Equal(t, tt.want, rules)
+		})
+	}
+}
+
+func TestNewPolicy_InvalidConfig(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{name: "zero min length", cfg: Config{MinLength: 0, MaxLength: 64}},
+		{name: "max below min", cfg: Config{MinLength: 10, MaxLength: 8}},
+		{name: "max beyond bcrypt", cfg: Config{MinLength: 8, MaxLength: 100}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPolicy(tt.cfg, nil)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBreachedList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := "# common passwords\n" +
+		sha1Hex("password123") + ":24230577\n" +
+		"\n" +
+		strings.ToLower(sha1Hex("qwertyuiop")) + "\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	list, err := LoadBreachedList(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, list.Len())
+	assert.True(t, list.Contains("password123"))
+	assert.True(t, list.Contains("qwertyuiop"))
+	assert.False(t, list.Contains("Correct-Horse-1"))
+
+	policy, err := NewPolicy(Config{MinLength: 8, MaxLength: 64}, list)
+	require.NoError(t, err)
+
+	var policyErr *PolicyError
+	require.ErrorAs(t, policy.Validate("password123"), &policyErr)
+	assert.Equal(t, RuleBreached, policyErr.Violations[0].Rule)
+}
+
+func TestLoadBreachedList_Malformed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte("not-a-digest\n"), 0o600))
+
+	_, err := LoadBreachedList(path)
+	assert.ErrorContains(t, err, ":1:")
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
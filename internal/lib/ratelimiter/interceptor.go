@@ -0,0 +1,210 @@
+package ratelimiter
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/Tbits007/auth/internal/lib/bearer"
+	"github.com/Tbits007/auth/internal/lib/clientip"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Response metadata describing the limit applied to a call.
+const (
+	LimitKey      = "x-ratelimit-limit"
+	RemainingKey  = "x-ratelimit-remaining"
+	ResetKey      = "x-ratelimit-reset"
+	RetryAfterKey = "retry-after"
+)
+
+// APIKeyMetadataKey is the metadata key clients send their API key in.
+const APIKeyMetadataKey = "x-api-key"
+
+// FailurePolicy decides what happens to calls while the Redis backend is
+// unavailable.
+type FailurePolicy string
+
+const (
+	// FailOpen lets every call through.
+	FailOpen FailurePolicy = "open"
+	// FailClosed rejects every call with Unavailable.
+	FailClosed FailurePolicy = "closed"
+	// FailLocal enforces the limits per instance with a LocalLimiter.
+	FailLocal FailurePolicy = "local"
+)
+
+// Policy holds the limit of every gRPC full method, such as
+// "/auth.Auth/Login". Methods without an entry get Default.
+type Policy struct {
+	Default   Limit
+	Methods   map[string]Limit
+	OnFailure FailurePolicy
+}
+
+func (p Policy) limit(method string) Limit {
+	if limit, ok := p.Methods[method]; ok {
+		return limit
+	}
+
+	return p.Default
+}
+
+// Validate reports limits and failure policies that cannot be enforced.
+func (p Policy) Validate() error {
+	switch p.OnFailure {
+	case FailOpen, FailClosed, FailLocal:
+	default:
+		return fmt.Errorf("unknown failure policy %q", p.OnFailure)
+	}
+
+	if err := p.Default.validate(); err != nil {
+		return fmt.Errorf("default limit: %w", err)
+	}
+
+	for method, limit := range p.Methods {
+		if err := limit.validate(); err != nil {
+			return fmt.Errorf("limit of %s: %w", method, err)
+		}
+	}
+
+	return nil
+}
+
+func (l Limit) validate() error {
+	if l.Rate < 0 || l.Burst < 0 {
+		return fmt.Errorf("rate and burst must not be negative")
+	}
+	if l.Rate > 0 && l.Period <= 0 {
+		return fmt.Errorf("period must be positive")
+	}
+
+	return nil
+}
+
+// Identify returns the identity of the client making a call. Calls are
+// limited per method and identity.
+type Identify func(ctx context.Context) string
+
+// SubjectFunc returns the subject of a valid access token.
+type SubjectFunc func(token string) (string, error)
+
+// ClientIdentity identifies clients by a known API key, else by the
+// subject of a valid bearer token, else by client IP. Unknown API keys
+// and invalid tokens are ignored, so clients cannot escape their limit
+// by making up new identities. apiKeys maps client names to their keys.
+func ClientIdentity(apiKeys map[string]string, subject SubjectFunc) Identify {
+	names := make(map[[sha256.Size]byte]string, len(apiKeys))
+	for name, key := range apiKeys {
+		names[sha256.Sum256([]byte(key))] = name
+	}
+
+	return func(ctx context.Context) string {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			for _, key := range md.Get(APIKeyMetadataKey) {
+				if name, ok := names[sha256.Sum256([]byte(key))]; ok {
+					return "key:" + name
+				}
+			}
+		}
+
+		if token, err := bearer.FromIncomingContext(ctx); err == nil {
+			if sub, err := subject(token); err == nil {
+				return "sub:" + sub
+			}
+		}
+
+		return "ip:" + clientip.FromContext(ctx)
+	}
+}
+
+// UnaryServerInterceptor limits calls per method and client identity.
+// Limits are counted in store; when it fails, policy.OnFailure decides
+// how calls are handled, with fallback counting them under FailLocal.
+// The limit applied is reported in the x-ratelimit-* response headers.
+func UnaryServerInterceptor(
+	log      *slog.Logger,
+	policy   Policy,
+	store    Backend,
+	fallback Backend,
+	identify Identify,
+) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		limit := policy.limit(info.FullMethod)
+		if limit.Rate == 0 {
+			return handler(ctx, req)
+		}
+
+		key := info.FullMethod + ":" + identify(ctx)
+
+		res, err := store.Take(ctx, key, limit)
+		if err != nil {
+			log.Warn("rate limit store unavailable",
+				slog.String("method", info.FullMethod),
+				slog.String("on_failure", string(policy.OnFailure)),
+				sl.Err(err),
+			)
+
+			switch policy.OnFailure {
+			case FailClosed:
+				return nil, status.Error(codes.Unavailable, "rate limiter unavailable")
+			case FailLocal:
+				res, err = fallback.Take(ctx, key, limit)
+				if err != nil {
+					return handler(ctx, req)
+				}
+			default:
+				return handler(ctx, req)
+			}
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(
+			LimitKey, strconv.Itoa(limit.Rate),
+			RemainingKey, strconv.Itoa(res.Remaining),
+			ResetKey, strconv.FormatInt(ceilSeconds(res.ResetAfter), 10),
+		))
+
+		if !res.Allowed {
+			return nil, ExhaustedError(ctx, "rate limit exceeded", res.RetryAfter)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// ExhaustedError reports a rejected call as ResourceExhausted with msg.
+// The wait, rounded up to whole seconds, is sent both in a retry-after
+// header and as RetryInfo details.
+func ExhaustedError(ctx context.Context, msg string, retryAfter time.Duration) error {
+	seconds := ceilSeconds(retryAfter)
+	_ = grpc.SetHeader(ctx, metadata.Pairs(RetryAfterKey, strconv.FormatInt(seconds, 10)))
+
+	st := status.New(codes.ResourceExhausted, msg)
+	detailed, err := st.WithDetails(&errdetails.RetryInfo{
+		RetryDelay: durationpb.New(time.Duration(seconds) * time.Second),
+	})
+	if err != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64(math.Ceil(d.Seconds()))
+}
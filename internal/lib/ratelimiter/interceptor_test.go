@@ -0,0 +1,201 @@
+package ratelimiter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/lib/clientip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testLog = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type backendFunc func(ctx context.Context, key string, limit Limit) (Result, error)
+
+func (f backendFunc) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	return f(ctx, key, limit)
+}
+
+var errStoreDown = errors.New("redis down")
+
+func downBackend(ctx context.Context, key string, limit Limit) (Result, error) {
+	return Result{}, errStoreDown
+}
+
+// headerStream captures the headers an interceptor sets with
+// grpc.SetHeader.
+type headerStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "/auth.Auth/Login" }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func call(t *testing.T, interceptor grpc.UnaryServerInterceptor, ctx context.Context) (metadata.MD, bool, error) {
+	t.Helper()
+
+	stream := &headerStream{}
+	ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+
+	called := false
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/auth.Auth/Login"}, func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	return stream.header, called, err
+}
+
+func TestUnaryServerInterceptor_PerMethodLimit(t *testing.T) {
+	policy := Policy{
+		Default:   Limit{Rate: 100, Period: time.Second},
+		Methods:   map[string]Limit{"/auth.Auth/Login": {Rate: 5, Period: time.Minute}},
+		OnFailure: FailOpen,
+	}
+
+	var gotKey string
+	var gotLimit Limit
+	store := backendFunc(func(ctx context.Context, key string, limit Limit) (Result, error) {
+		gotKey, gotLimit = key, limit
+		return Result{Limit: limit, Allowed: true, Remaining: 4, ResetAfter: 11500 * time.Millisecond}, nil
+	})
+
+	interceptor := UnaryServerInterceptor(testLog, policy, store, nil, func(ctx context.Context) string { return "ip:192.0.2.10" })
+
+	header, called, err := call(t, interceptor, context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Equal(t, "/auth.Auth/Login:ip:192.0.2.10", gotKey)
+	assert.Equal(t, 5, gotLimit.Rate)
+	assert.Equal(t, []string{"5"}, header.Get(LimitKey))
+	assert.Equal(t, []string{"4"}, header.Get(RemainingKey))
+	assert.Equal(t, []string{"12"}, header.Get(ResetKey))
+}
+
+func TestUnaryServerInterceptor_Exhausted(t *testing.T) {
+	policy := Policy{Default: Limit{Rate: 1, Period: time.Second}, OnFailure: FailOpen}
+	store := backendFunc(func(ctx context.Context, key string, limit Limit) (Result, error) {
+		return Result{Limit: limit, RetryAfter: 1500 * time.Millisecond, ResetAfter: 2 * time.Second}, nil
+	})
+
+	interceptor := UnaryServerInterceptor(testLog, policy, store, nil, func(ctx context.Context) string { return "ip:" })
+
+	header, called, err := call(t, interceptor, context.Background())
+
+	assert.False(t, called)
+	st := status.Convert(err)
+	assert.Equal(t, codes.ResourceExhausted, st.Code())
+	assert.Equal(t, []string{"2"}, header.Get(RetryAfterKey))
+	require.Len(t, st.Details(), 1)
+	assert.Equal(t, 2*time.Second, st.Details()[0].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+}
+
+func TestUnaryServerInterceptor_Unlimited(t *testing.T) {
+	policy := Policy{Default: Limit{}, OnFailure: FailClosed}
+
+	interceptor := UnaryServerInterceptor(testLog, policy, backendFunc(downBackend), nil, func(ctx context.Context) string { return "" })
+
+	header, called, err := call(t, interceptor, context.Background())
+
+	require.NoError(t, err)
+	assert.True(t, called)
+	assert.Empty(t, header)
+}
+
+func TestUnaryServerInterceptor_StoreFailure(t *testing.T) {
+	limit := Limit{Rate: 1, Period: time.Minute}
+
+	tests := []struct {
+		name      string
+		onFailure FailurePolicy
+		calls     int
+		wantCode  codes.Code
+	}{
+		{name: "fail open", onFailure: FailOpen, calls: 3, wantCode: codes.OK},
+		{name: "fail closed", onFailure: FailClosed, calls: 1, wantCode: codes.Unavailable},
+		{name: "local fallback", onFailure: FailLocal, calls: 2, wantCode: codes.ResourceExhausted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := Policy{Default: limit, OnFailure: tt.onFailure}
+			interceptor := UnaryServerInterceptor(
+				testLog,
+				policy,
+				backendFunc(downBackend),
+				NewLocalLimiter(DefaultMaxKeys),
+				func(ctx context.Context) string { return "ip:192.0.2.10" },
+			)
+
+			var err error
+			for range tt.calls {
+				_, _, err = call(t, interceptor, context.Background())
+			}
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestClientIdentity(t *testing.T) {
+	identify := ClientIdentity(map[string]string{"billing": "s3cret"}, func(token string) (string, error) {
+		if token != "valid" {
+			return "", errors.New("invalid token")
+		}
+		return "user-1", nil
+	})
+
+	base := clientip.WithIP(context.Background(), "192.0.2.10")
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want string
+	}{
+		{name: "known api key", md: metadata.Pairs(APIKeyMetadataKey, "s3cret", "authorization", "Bearer valid"), want: "key:billing"},
+		{name: "unknown api key", md: metadata.Pairs(APIKeyMetadataKey, "guess"), want: "ip:192.0.2.10"},
+		{name: "valid token", md: metadata.Pairs("authorization", "Bearer valid"), want: "sub:user-1"},
+		{name: "invalid token", md: metadata.Pairs("authorization", "Bearer forged"), want: "ip:192.0.2.10"},
+		{name: "anonymous", want: "ip:192.0.2.10"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := base
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			assert.Equal(t, tt.want, identify(ctx))
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	valid := Policy{Default: Limit{Rate: 10, Period: time.Second}, OnFailure: FailLocal}
+	require.NoError(t, valid.Validate())
+
+	unknown := valid
+	unknown.OnFailure = "maybe"
+	assert.Error(t, unknown.Validate())
+
+	noPeriod := valid
+	noPeriod.Methods = map[string]Limit{"/auth.Auth/Login": {Rate: 5}}
+	assert.Error(t, noPeriod.Validate())
+}
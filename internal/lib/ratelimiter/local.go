@@ -0,0 +1,92 @@
+package ratelimiter
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// DefaultMaxKeys bounds the number of buckets a LocalLimiter keeps.
+const DefaultMaxKeys = 100_000
+
+// LocalLimiter is an in-process token bucket Backend. Its counts are per
+// instance, so it only stands in for Limiter while Redis is unavailable.
+type LocalLimiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	maxKeys int
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+func NewLocalLimiter(maxKeys int) *LocalLimiter {
+	return &LocalLimiter{
+		buckets: make(map[string]*bucket),
+		maxKeys: maxKeys,
+		now:     time.Now,
+	}
+}
+
+func (lo *LocalLimiter) Take(
+	ctx   context.Context,
+	key   string,
+	limit Limit,
+) (Result, error) {
+	capacity := float64(limit.burst())
+	perSecond := float64(limit.Rate) / limit.Period.Seconds()
+
+	lo.mu.Lock()
+	defer lo.mu.Unlock()
+
+	now := lo.now()
+
+	b, ok := lo.buckets[key]
+	if !ok {
+		if len(lo.buckets) >= lo.maxKeys {
+			lo.prune(now, perSecond, capacity)
+		}
+		b = &bucket{tokens: capacity, updated: now}
+		lo.buckets[key] = b
+	}
+
+	b.tokens = min(capacity, b.tokens+now.Sub(b.updated).Seconds()*perSecond)
+	b.updated = now
+
+	res := Result{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.tokens) / perSecond)
+	}
+
+	res.Remaining = int(math.Floor(b.tokens))
+	res.ResetAfter = seconds((capacity - b.tokens) / perSecond)
+
+	return res, nil
+}
+
+// prune drops the buckets that have refilled, as a fresh bucket would be
+// identical. If every bucket is still in use it drops them all rather
+// than grow without bound. Buckets of other limits are estimated with the
+// limit at hand, which only affects how early they are dropped.
+func (lo *LocalLimiter) prune(now time.Time, perSecond, capacity float64) {
+	for key, b := range lo.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*perSecond >= capacity {
+			delete(lo.buckets, key)
+		}
+	}
+
+	if len(lo.buckets) >= lo.maxKeys {
+		clear(lo.buckets)
+	}
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
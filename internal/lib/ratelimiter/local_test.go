@@ -0,0 +1,61 @@
+package ratelimiter
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalLimiter_TokenBucket(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewLocalLimiter(DefaultMaxKeys)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	limit := Limit{Rate: 2, Burst: 3, Period: time.Second}
+
+	for want := 2; want >= 0; want-- {
+		res, err := limiter.Take(ctx, "client", limit)
+		require.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, want, res.Remaining)
+	}
+
+	res, err := limiter.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+	assert.Equal(t, 1500*time.Millisecond, res.ResetAfter)
+
+	res, err = limiter.Take(ctx, "other", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed, "keys have their own buckets")
+
+	now = now.Add(500 * time.Millisecond)
+
+	res, err = limiter.Take(ctx, "client", limit)
+	require.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+}
+
+func TestLocalLimiter_PrunesRefilledBuckets(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	limiter := NewLocalLimiter(2)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Period: time.Second}
+
+	_, _ = limiter.Take(ctx, "a", limit)
+	_, _ = limiter.Take(ctx, "b", limit)
+
+	now = now.Add(time.Second)
+	_, _ = limiter.Take(ctx, "c", limit)
+
+	assert.Len(t, limiter.buckets, 1)
+	assert.Contains(t, limiter.buckets, "c")
+}
@@ -3,6 +3,7 @@ package ratelimiter
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/go-redis/redis_rate/v10"
 )
@@ -11,6 +12,44 @@ var (
 	ErrRateLimited = errors.New("rate limited")
 )
 
+// Limit allows Rate requests per Period, with bursts of up to Burst
+// requests. A zero Burst means Rate; a zero Rate means no limit.
+type Limit struct {
+	Rate   int
+	Burst  int
+	Period time.Duration
+}
+
+func (l Limit) burst() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+
+	return l.Rate
+}
+
+// Result is the state of a key after a request was counted against it.
+type Result struct {
+	Limit     Limit
+	Allowed   bool
+	Remaining int
+	// RetryAfter is how long to wait until the next request is allowed;
+	// zero when this one was.
+	RetryAfter time.Duration
+	// ResetAfter is how long until the key is back to its full burst.
+	ResetAfter time.Duration
+}
+
+// Backend counts requests against keys.
+type Backend interface {
+	Take(
+		ctx   context.Context,
+		key   string,
+		limit Limit,
+	) (Result, error)
+}
+
+// Limiter is a Backend shared by every instance through Redis.
 type Limiter struct {
 	rateLimit *redis_rate.Limiter
 }
@@ -23,12 +62,42 @@ func NewLimiter(
 	}
 }
 
-func (li *Limiter) Limit(ctx context.Context) error {
-	res, err := li.rateLimit.Allow(ctx, "auth", redis_rate.PerSecond(15))
+func (li *Limiter) Take(
+	ctx   context.Context,
+	key   string,
+	limit Limit,
+) (Result, error) {
+	res, err := li.rateLimit.Allow(ctx, key, redis_rate.Limit{
+		Rate:   limit.Rate,
+		Burst:  limit.burst(),
+		Period: limit.Period,
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Limit:      limit,
+		Allowed:    res.Allowed > 0,
+		Remaining:  res.Remaining,
+		RetryAfter: max(res.RetryAfter, 0),
+		ResetAfter: res.ResetAfter,
+	}, nil
+}
+
+// Allow counts one event against key and fails with ErrRateLimited once
+// more than limit events happened within period.
+func (li *Limiter) Allow(
+	ctx    context.Context,
+	key    string,
+	limit  int,
+	period time.Duration,
+) error {
+	res, err := li.Take(ctx, key, Limit{Rate: limit, Period: period})
 	if err != nil {
 		return err
 	}
-	if res.Allowed == 0 {
+	if !res.Allowed {
 		return ErrRateLimited
 	}
 
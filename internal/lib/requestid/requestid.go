@@ -0,0 +1,54 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the gRPC metadata key carrying the request ID in both
+// directions.
+const MetadataKey = "x-request-id"
+
+// maxLength bounds client-supplied IDs, which end up in logs and events.
+const maxLength = 128
+
+type ctxKey struct{}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the request ID stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKey{}).(string)
+	return id
+}
+
+// UnaryServerInterceptor takes the request ID from incoming metadata,
+// generating one when the client did not send a usable one, stores it in
+// the context and echoes it back in the response header.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		var id string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if values := md.Get(MetadataKey); len(values) > 0 {
+				id = values[0]
+			}
+		}
+		if id == "" || len(id) > maxLength {
+			id = uuid.NewString()
+		}
+
+		_ = grpc.SetHeader(ctx, metadata.Pairs(MetadataKey, id))
+
+		return handler(WithID(ctx, id), req)
+	}
+}
@@ -0,0 +1,49 @@
+package secretbox
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSealOpen(t *testing.T) {
+	box, err := New(bytes.Repeat([]byte{7}, KeySize))
+	require.NoError(t, err)
+
+	sealed, err := box.Seal([]byte("JBSWY3DPEHPK3PXP"), []byte("user-1"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(sealed), "JBSWY3DPEHPK3PXP")
+
+	again, err := box.Seal([]byte("JBSWY3DPEHPK3PXP"), []byte("user-1"))
+	require.NoError(t, err)
+	assert.NotEqual(t, sealed, again, "nonces are random")
+
+	plaintext, err := box.Open(sealed, []byte("user-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "JBSWY3DPEHPK3PXP", string(plaintext))
+
+	_, err = box.Open(sealed, []byte("user-2"))
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	_, err = box.Open(sealed[:5], []byte("user-1"))
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+
+	other, err := New(bytes.Repeat([]byte{8}, KeySize))
+	require.NoError(t, err)
+	_, err = other.Open(sealed, []byte("user-1"))
+	assert.ErrorIs(t, err, ErrInvalidCiphertext)
+}
+
+func TestNewFromBase64(t *testing.T) {
+	_, err := NewFromBase64(base64.StdEncoding.EncodeToString(make([]byte, KeySize)))
+	assert.NoError(t, err)
+
+	_, err = NewFromBase64(base64.StdEncoding.EncodeToString(make([]byte, 16)))
+	assert.Error(t, err)
+
+	_, err = NewFromBase64("not base64!")
+	assert.Error(t, err)
+}
@@ -0,0 +1,69 @@
+package useragent
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// GatewayKey is the metadata key grpc-gateway forwards the HTTP client's
+// User-Agent under. It takes precedence over the gRPC client's own.
+const GatewayKey = "grpcgateway-user-agent"
+
+// MaxLength caps stored user agents; longer values are truncated.
+const MaxLength = 512
+
+type ctxKey struct{}
+
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, userAgent)
+}
+
+// FromContext returns the user agent stored in ctx, or "" if there is none.
+func FromContext(ctx context.Context) string {
+	userAgent, _ := ctx.Value(ctxKey{}).(string)
+	return userAgent
+}
+
+// UnaryServerInterceptor stores the caller's user agent in the context.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		return handler(WithUserAgent(ctx, fromMetadata(ctx)), req)
+	}
+}
+
+func fromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	for _, key := range []string{GatewayKey, "user-agent"} {
+		if values := md.Get(key); len(values) > 0 {
+			return truncate(strings.TrimSpace(values[0]))
+		}
+	}
+
+	return ""
+}
+
+func truncate(s string) string {
+	if len(s) <= MaxLength {
+		return s
+	}
+
+	s = s[:MaxLength]
+	for !utf8.ValidString(s) {
+		s = s[:len(s)-1]
+	}
+
+	return s
+}
@@ -0,0 +1,47 @@
+package useragent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want string
+	}{
+		{name: "no metadata"},
+		{name: "grpc client", md: metadata.Pairs("user-agent", "grpc-go/1.71.0"), want: "grpc-go/1.71.0"},
+		{
+			name: "gateway",
+			md:   metadata.Pairs("user-agent", "grpc-go/1.71.0", GatewayKey, "Mozilla/5.0"),
+			want: "Mozilla/5.0",
+		},
+		{name: "truncated", md: metadata.Pairs("user-agent", strings.Repeat("a", MaxLength+1)), want: strings.Repeat("a", MaxLength)},
+		{name: "truncated on rune boundary", md: metadata.Pairs("user-agent", strings.Repeat("a", MaxLength-1)+"é"), want: strings.Repeat("a", MaxLength-1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+
+			var got string
+			_, err := UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, req any) (any, error) {
+				got = FromContext(ctx)
+				return nil, nil
+			})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package logPublisher
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+)
+
+// LogPublisher writes events to the application log. It is meant for
+// local development, where no broker is available. Payloads are not
+// logged since some carry user data.
+type LogPublisher struct {
+	log *slog.Logger
+}
+
+func NewLogPublisher(log *slog.Logger) *LogPublisher {
+	return &LogPublisher{
+		log: log,
+	}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, event eventModel.Event) error {
+	p.log.InfoContext(ctx, "outbox event published",
+		slog.String("event_id", event.ID.String()),
+		slog.String("event_type", event.EventType),
+	)
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package redisStreamPublisher
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisStreamPublisher appends events to a Redis stream. An event may be
+// appended more than once if the relay crashes before marking it
+// processed, so consumers should deduplicate on the "id" field.
+type RedisStreamPublisher struct {
+	db     *redis.Client
+	stream string
+	maxLen int64
+}
+
+func NewRedisStreamPublisher(db *redis.Client, stream string, maxLen int64) *RedisStreamPublisher {
+	return &RedisStreamPublisher{
+		db:     db,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+func (p *RedisStreamPublisher) Publish(ctx context.Context, event eventModel.Event) error {
+	const op = "redisStreamPublisher.Publish"
+
+	args := &redis.XAddArgs{
+		Stream: p.stream,
+		Values: map[string]any{
+			"id":      event.ID.String(),
+			"type":    event.EventType,
+			"payload": string(event.Payload),
+		},
+	}
+	if p.maxLen > 0 {
+		args.MaxLen = p.maxLen
+		args.Approx = true
+	}
+
+	if err := p.db.XAdd(ctx, args).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package webhookPublisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+)
+
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderEventType      = "X-Event-Type"
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected webhook response status")
+
+// WebhookPublisher POSTs the event payload to a fixed URL. The outbox row ID
+// is sent as the Idempotency-Key header so that receivers can drop
+// redeliveries.
+type WebhookPublisher struct {
+	client *http.Client
+	url    string
+}
+
+func NewWebhookPublisher(url string, timeout time.Duration) *WebhookPublisher {
+	return &WebhookPublisher{
+		client: &http.Client{Timeout: timeout},
+		url:    url,
+	}
+}
+
+func (p *WebhookPublisher) Publish(ctx context.Context, event eventModel.Event) error {
+	const op = "webhookPublisher.Publish"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(event.Payload))
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+	req.Header.Set(HeaderIdempotencyKey, event.ID.String())
+	req.Header.Set(HeaderEventType, event.EventType)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %w: %d", op, ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package webhookPublisher
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPublish_Success(t *testing.T) {
+	event := eventModel.Event{
+		ID:        uuid.New(),
+		EventType: "UserCreated",
+		Payload:   []byte(`{"user_email":"test@test"}`),
+	}
+
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookPublisher(srv.URL, time.Second).Publish(context.Background(), event)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, event.ID.String(), got.Header.Get(HeaderIdempotencyKey))
+	assert.Equal(t, event.EventType, got.Header.Get(HeaderEventType))
+	assert.Equal(t, event.Payload, body)
+}
+
+func TestPublish_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	err := NewWebhookPublisher(srv.URL, time.Second).Publish(context.Background(), eventModel.Event{ID: uuid.New()})
+
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+}
@@ -0,0 +1,586 @@
+package admin
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/requestid"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidCursor     = errors.New("invalid cursor")
+	ErrNothingToUpdate   = errors.New("nothing to update")
+	ErrInvalidEmail      = errors.New("invalid email")
+	ErrInvalidStatus     = errors.New("invalid status")
+	ErrInvalidTransition = errors.New("invalid status transition")
+)
+
+const (
+	DefaultPageSize = 50
+	MaxPageSize     = 500
+)
+
+type UserRepo interface {
+	List(
+		ctx    context.Context,
+		filter userModel.ListFilter,
+	) ([]userModel.User, error)
+
+	GetByID(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) (*userModel.User, error)
+
+	UpdateEmail(
+		ctx    context.Context,
+		userID uuid.UUID,
+		email  string,
+	) error
+
+	SetStatus(
+		ctx    context.Context,
+		userID uuid.UUID,
+		from   []userModel.Status,
+		to     userModel.Status,
+	) (userModel.Status, error)
+
+	AssignRole(
+		ctx    context.Context,
+		userID uuid.UUID,
+		role   string,
+	) error
+
+	RevokeRole(
+		ctx    context.Context,
+		userID uuid.UUID,
+		role   string,
+	) error
+}
+
+type EventRepo interface {
+	Save(
+		ctx   context.Context,
+		event eventModel.Event,
+	) (uuid.UUID, error)
+}
+
+type TxManager interface {
+	WithTransaction(
+		ctx context.Context,
+		fn  func(ctx context.Context) error,
+	) error
+}
+
+type CacheRepo interface {
+	InvalidateUser(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) error
+}
+
+// Authorizer checks that an access token carries a global permission.
+// It is implemented by auth.AuthService.
+type Authorizer interface {
+	Authorize(
+		ctx         context.Context,
+		accessToken string,
+		permission  string,
+	) (*jwt.Claims, error)
+}
+
+// Sessions lists and ends the login sessions of any user. It is
+// implemented by auth.AuthService.
+type Sessions interface {
+	ListUserSessions(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) ([]sessionModel.Session, error)
+
+	RevokeUserSession(
+		ctx       context.Context,
+		actorID   uuid.UUID,
+		userID    uuid.UUID,
+		sessionID uuid.UUID,
+	) error
+
+	RevokeUserSessions(
+		ctx     context.Context,
+		actorID uuid.UUID,
+		userID  uuid.UUID,
+	) error
+}
+
+// UserUpdate holds the fields UpdateUser changes. Nil fields are left as
+// they are.
+//
+// An email set by an admin takes effect without verification and leaves
+// the account status alone: admins change emails on behalf of users who
+// lost access to the old address, so a verification mail would lock them
+// out instead. The UserUpdated event carries the new address, so that
+// consumers can tell the user about the change.
+type UserUpdate struct {
+	Email   *string
+	IsAdmin *bool
+}
+
+type AdminService struct {
+	log        *slog.Logger
+	txManager  TxManager
+	userRepo   UserRepo
+	eventRepo  EventRepo
+	cacheRepo  CacheRepo
+	authorizer Authorizer
+	sessions   Sessions
+}
+
+func NewAdminService(
+	log        *slog.Logger,
+	txManager  TxManager,
+	userRepo   UserRepo,
+	eventRepo  EventRepo,
+	cacheRepo  CacheRepo,
+	authorizer Authorizer,
+	sessions   Sessions,
+) *AdminService {
+	return &AdminService{
+		log:        log,
+		txManager:  txManager,
+		userRepo:   userRepo,
+		eventRepo:  eventRepo,
+		cacheRepo:  cacheRepo,
+		authorizer: authorizer,
+		sessions:   sessions,
+	}
+}
+
+// ListUsers returns one page of users matching filter, ordered by creation
+// time. cursor is empty for the first page; the returned cursor is empty
+// after the last one.
+func (ad *AdminService) ListUsers(
+	ctx         context.Context,
+	accessToken string,
+	filter      userModel.ListFilter,
+	cursor      string,
+) ([]userModel.User, string, error) {
+	const op = "AdminService.ListUsers"
+
+	log := ad.log.With(
+		slog.String("op", op),
+	)
+
+	if _, err := ad.authorize(ctx, accessToken, roleModel.PermUsersRead); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if filter.Status != "" && !filter.Status.Valid() {
+		return nil, "", fmt.Errorf("%s: %w", op, ErrInvalidStatus)
+	}
+
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", fmt.Errorf("%s: %w", op, err)
+		}
+		filter.After = &after
+	}
+
+	switch {
+	case filter.Limit <= 0:
+		filter.Limit = DefaultPageSize
+	case filter.Limit > MaxPageSize:
+		filter.Limit = MaxPageSize
+	}
+	pageSize := filter.Limit
+
+	// One extra row tells us whether there is a next page.
+	filter.Limit++
+
+	users, err := ad.userRepo.List(ctx, filter)
+	if err != nil {
+		log.Error("failed to list users", sl.Err(err))
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	if len(users) <= pageSize {
+		return users, "", nil
+	}
+
+	users = users[:pageSize]
+	last := users[len(users)-1]
+
+	return users, encodeCursor(userModel.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}), nil
+}
+
+func (ad *AdminService) GetUser(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+) (*userModel.User, error) {
+	const op = "AdminService.GetUser"
+
+	if _, err := ad.authorize(ctx, accessToken, roleModel.PermUsersRead); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := ad.getUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return user, nil
+}
+
+// UpdateUser changes a user's email and/or admin role and returns the
+// updated user. Toggling admin additionally requires roles:assign.
+func (ad *AdminService) UpdateUser(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+	update      UserUpdate,
+) (*userModel.User, error) {
+	const op = "AdminService.UpdateUser"
+
+	log := ad.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	if update.Email == nil && update.IsAdmin == nil {
+		return nil, fmt.Errorf("%s: %w", op, ErrNothingToUpdate)
+	}
+
+	if update.Email != nil {
+		email, err := normalizeEmail(*update.Email)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+		update.Email = &email
+	}
+
+	actor, err := ad.authorize(ctx, accessToken, roleModel.PermUsersWrite)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if update.IsAdmin != nil {
+		if _, err := ad.authorize(ctx, accessToken, roleModel.PermRolesAssign); err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	user, err := ad.getUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = ad.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if update.Email != nil {
+			if err := ad.userRepo.UpdateEmail(ctx, userID, *update.Email); err != nil {
+				return err
+			}
+			user.Email = *update.Email
+		}
+
+		if update.IsAdmin != nil {
+			apply := ad.userRepo.RevokeRole
+			if *update.IsAdmin {
+				apply = ad.userRepo.AssignRole
+			}
+			if err := apply(ctx, userID, roleModel.RoleAdmin); err != nil {
+				return err
+			}
+			user.IsAdmin = *update.IsAdmin
+		}
+
+		return ad.saveEvent(ctx, eventModel.UserUpdatedV1, userID, eventModel.UserUpdated{
+			UserID:  userID,
+			Email:   update.Email,
+			IsAdmin: update.IsAdmin,
+			ActorID: actor.UserID,
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, ad.txError(log, err))
+	}
+
+	ad.evict(ctx, log, userID)
+
+	log.Info("user updated", slog.String("actor_id", actor.UserID.String()))
+
+	return user, nil
+}
+
+// normalizeEmail brings email into the form accounts are stored under and
+// rejects anything but a bare address.
+func normalizeEmail(email string) (string, error) {
+	email = userModel.NormalizeEmail(email)
+
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Name != "" || addr.Address != email {
+		return "", ErrInvalidEmail
+	}
+
+	return email, nil
+}
+
+func (ad *AdminService) DisableUser(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+) error {
+	const op = "AdminService.DisableUser"
+
+	return ad.changeStatus(ctx, op, accessToken, userID, roleModel.PermUsersWrite, userModel.StatusDisabled)
+}
+
+// DeleteUser soft-deletes the user: the row is kept with status deleted,
+// which is terminal.
+func (ad *AdminService) DeleteUser(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+) error {
+	const op = "AdminService.DeleteUser"
+
+	return ad.changeStatus(ctx, op, accessToken, userID, roleModel.PermUsersDelete, userModel.StatusDeleted)
+}
+
+// ListSessions returns the active sessions of a user, most recently seen
+// first.
+func (ad *AdminService) ListSessions(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+) ([]sessionModel.Session, error) {
+	const op = "AdminService.ListSessions"
+
+	if _, err := ad.authorize(ctx, accessToken, roleModel.PermUsersRead); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := ad.getUser(ctx, userID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sessions, err := ad.sessions.ListUserSessions(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession ends one session of a user.
+func (ad *AdminService) RevokeSession(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+	sessionID   uuid.UUID,
+) error {
+	const op = "AdminService.RevokeSession"
+
+	actor, err := ad.authorize(ctx, accessToken, roleModel.PermUsersWrite)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := ad.sessions.RevokeUserSession(ctx, actor.UserID, userID, sessionID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeAllSessions logs a user out on every device. Unlike DisableUser
+// the user may log in again right away.
+func (ad *AdminService) RevokeAllSessions(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+) error {
+	const op = "AdminService.RevokeAllSessions"
+
+	actor, err := ad.authorize(ctx, accessToken, roleModel.PermUsersWrite)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := ad.getUser(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := ad.sessions.RevokeUserSessions(ctx, actor.UserID, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (ad *AdminService) changeStatus(
+	ctx         context.Context,
+	op          string,
+	accessToken string,
+	userID      uuid.UUID,
+	permission  string,
+	to          userModel.Status,
+) error {
+	log := ad.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	actor, err := ad.authorize(ctx, accessToken, permission)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := ad.getUser(ctx, userID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = ad.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		from, err := ad.userRepo.SetStatus(ctx, userID, userModel.Sources(to), to)
+		if err != nil {
+			return err
+		}
+
+		return ad.saveEvent(ctx, eventModel.UserStatusChangedV1, userID, eventModel.UserStatusChanged{
+			UserID:  userID,
+			From:    string(from),
+			To:      string(to),
+			ActorID: &actor.UserID,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, ad.txError(log, err))
+	}
+
+	ad.evict(ctx, log, userID)
+
+	log.Info("user status changed",
+		slog.String("status", string(to)),
+		slog.String("actor_id", actor.UserID.String()),
+	)
+
+	return nil
+}
+
+// authorize requires both the admin claim in the token and a live
+// permission check, so a demoted admin's unexpired token stops working.
+func (ad *AdminService) authorize(
+	ctx         context.Context,
+	accessToken string,
+	permission  string,
+) (*jwt.Claims, error) {
+	claims, err := ad.authorizer.Authorize(ctx, accessToken, permission)
+	if err != nil {
+		return nil, err
+	}
+
+	if !claims.IsAdmin {
+		return nil, auth.ErrPermissionDenied
+	}
+
+	return claims, nil
+}
+
+func (ad *AdminService) getUser(
+	ctx    context.Context,
+	userID uuid.UUID,
+) (*userModel.User, error) {
+	user, err := ad.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, auth.ErrUserNotFound
+		}
+		ad.log.Error("failed to get user", slog.String("user_id", userID.String()), sl.Err(err))
+		return nil, err
+	}
+
+	return user, nil
+}
+
+func (ad *AdminService) saveEvent(
+	ctx       context.Context,
+	eventType string,
+	subject   uuid.UUID,
+	data      any,
+) error {
+	event, err := eventModel.NewEvent(eventType, subject, requestid.FromContext(ctx), time.Now(), data)
+	if err != nil {
+		return fmt.Errorf("build %s event: %w", eventType, err)
+	}
+
+	_, err = ad.eventRepo.Save(ctx, event)
+	return err
+}
+
+func (ad *AdminService) txError(log *slog.Logger, err error) error {
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		log.Info("user not found")
+		return auth.ErrUserNotFound
+	case errors.Is(err, storage.ErrUserExists):
+		log.Info("email already taken")
+		return err
+	case errors.Is(err, storage.ErrStatusConflict):
+		log.Info("invalid status transition", sl.Err(err))
+		return ErrInvalidTransition
+	}
+	log.Error("transaction failed", sl.Err(err))
+	return err
+}
+
+// evict drops the cached status and admin flag of the user once a change
+// to it is committed.
+func (ad *AdminService) evict(
+	ctx    context.Context,
+	log    *slog.Logger,
+	userID uuid.UUID,
+) {
+	if err := ad.cacheRepo.InvalidateUser(ctx, userID); err != nil {
+		log.Warn("failed to evict cached user", sl.Err(err))
+	}
+}
+
+func encodeCursor(cursor userModel.Cursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(s string) (userModel.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return userModel.Cursor{}, ErrInvalidCursor
+	}
+
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return userModel.Cursor{}, ErrInvalidCursor
+	}
+
+	var cursor userModel.Cursor
+	if cursor.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return userModel.Cursor{}, ErrInvalidCursor
+	}
+	if cursor.ID, err = uuid.Parse(id); err != nil {
+		return userModel.Cursor{}, ErrInvalidCursor
+	}
+
+	return cursor, nil
+}
@@ -0,0 +1,524 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/services/admin"
+	"github.com/Tbits007/auth/internal/services/admin/tests/mocks"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var adminClaims = &jwt.Claims{UserID: uuid.New(), IsAdmin: true}
+
+func TestListUsers_Paginates(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	users := []userModel.User{
+		{ID: uuid.New(), Email: "a@example.com", CreatedAt: base},
+		{ID: uuid.New(), Email: "b@example.com", CreatedAt: base.Add(time.Second)},
+		{ID: uuid.New(), Email: "c@example.com", CreatedAt: base.Add(2 * time.Second)},
+	}
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersRead).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		List(ctx, userModel.ListFilter{EmailPrefix: "a", Limit: 3}).
+		Return(users, nil).
+		Once()
+
+	mockUserRepo.EXPECT().
+		List(ctx, userModel.ListFilter{
+			EmailPrefix: "a",
+			Limit:       3,
+			After:       &userModel.Cursor{CreatedAt: users[1].CreatedAt, ID: users[1].ID},
+		}).
+		Return(users[2:], nil).
+		Once()
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	page, next, err := service.ListUsers(ctx, "token", userModel.ListFilter{EmailPrefix: "a", Limit: 2}, "")
+	require.NoError(t, err)
+	assert.Equal(t, users[:2], page)
+	require.NotEmpty(t, next)
+
+	page, next, err = service.ListUsers(ctx, "token", userModel.ListFilter{EmailPrefix: "a", Limit: 2}, next)
+	require.NoError(t, err)
+	assert.Equal(t, users[2:], page)
+	assert.Empty(t, next)
+}
+
+func TestListUsers_InvalidCursor(t *testing.T) {
+	ctx := context.Background()
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersRead).
+		Return(adminClaims, nil)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	_, _, err := service.ListUsers(ctx, "token", userModel.ListFilter{}, "not a cursor")
+
+	assert.ErrorIs(t, err, admin.ErrInvalidCursor)
+	mockUserRepo.AssertNotCalled(t, "List")
+}
+
+func TestGetUser_RequiresAdminClaim(t *testing.T) {
+	ctx := context.Background()
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	// A support user holds users:read but is not an admin.
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersRead).
+		Return(&jwt.Claims{UserID: uuid.New()}, nil)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	_, err := service.GetUser(ctx, "token", uuid.New())
+
+	assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+	mockUserRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestGetUser_NotFound(t *testing.T) {
+	ctx := context.Background()
+	testUserID := uuid.New()
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersRead).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, testUserID).
+		Return(nil, storage.ErrUserNotFound)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	_, err := service.GetUser(ctx, "token", testUserID)
+
+	assert.ErrorIs(t, err, auth.ErrUserNotFound)
+}
+
+func TestUpdateUser_EmailAndAdmin(t *testing.T) {
+	ctx := context.Background()
+	testUser := &userModel.User{ID: uuid.New(), Email: "old@example.com"}
+	newEmail := "new@example.com"
+	isAdmin := true
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersWrite).
+		Return(adminClaims, nil)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermRolesAssign).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, testUser.ID).
+		Return(testUser, nil)
+
+	mockTxManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	mockUserRepo.EXPECT().
+		UpdateEmail(ctx, testUser.ID, newEmail).
+		Return(nil)
+
+	mockUserRepo.EXPECT().
+		AssignRole(ctx, testUser.ID, roleModel.RoleAdmin).
+		Return(nil)
+
+	mockEventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserUpdatedV1
+		})).
+		Return(uuid.New(), nil)
+
+	mockCacheRepo.EXPECT().
+		InvalidateUser(ctx, testUser.ID).
+		Return(nil).
+		Once()
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	user, err := service.UpdateUser(ctx, "token", testUser.ID, admin.UserUpdate{
+		Email:   &newEmail,
+		IsAdmin: &isAdmin,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, user.Email)
+	assert.True(t, user.IsAdmin)
+}
+
+func TestUpdateUser_EmailTaken(t *testing.T) {
+	ctx := context.Background()
+	testUser := &userModel.User{ID: uuid.New(), Email: "old@example.com"}
+	newEmail := "taken@example.com"
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersWrite).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, testUser.ID).
+		Return(testUser, nil)
+
+	mockTxManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	mockUserRepo.EXPECT().
+		UpdateEmail(ctx, testUser.ID, newEmail).
+		Return(storage.ErrUserExists)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	_, err := service.UpdateUser(ctx, "token", testUser.ID, admin.UserUpdate{Email: &newEmail})
+
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+	mockEventRepo.AssertNotCalled(t, "Save")
+	mockCacheRepo.AssertNotCalled(t, "InvalidateUser")
+}
+
+func TestUpdateUser_NormalizesEmail(t *testing.T) {
+	ctx := context.Background()
+	testUser := &userModel.User{ID: uuid.New(), Email: "old@example.com"}
+	newEmail := " New@Example.COM "
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersWrite).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, testUser.ID).
+		Return(testUser, nil)
+
+	mockTxManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	mockUserRepo.EXPECT().
+		UpdateEmail(ctx, testUser.ID, "new@example.com").
+		Return(nil)
+
+	mockEventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserUpdatedV1
+		})).
+		Return(uuid.New(), nil)
+
+	mockCacheRepo.EXPECT().
+		InvalidateUser(ctx, testUser.ID).
+		Return(nil)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	user, err := service.UpdateUser(ctx, "token", testUser.ID, admin.UserUpdate{Email: &newEmail})
+
+	require.NoError(t, err)
+	assert.Equal(t, "new@example.com", user.Email)
+}
+
+func TestUpdateUser_InvalidEmail(t *testing.T) {
+	ctx := context.Background()
+
+	for _, email := range []string{"", "not-an-email", "Name <user@example.com>", "user@example.com, other@example.com"} {
+		t.Run(email, func(t *testing.T) {
+			mockTxManager := mocks.NewMockTxManager(t)
+			mockUserRepo := mocks.NewMockUserRepo(t)
+			mockEventRepo := mocks.NewMockEventRepo(t)
+			mockCacheRepo := mocks.NewMockCacheRepo(t)
+			mockAuthorizer := mocks.NewMockAuthorizer(t)
+			mockSessions := mocks.NewMockSessions(t)
+
+			service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+			_, err := service.UpdateUser(ctx, "token", uuid.New(), admin.UserUpdate{Email: &email})
+
+			assert.ErrorIs(t, err, admin.ErrInvalidEmail)
+			mockUserRepo.AssertNotCalled(t, "UpdateEmail")
+		})
+	}
+}
+
+func TestUpdateUser_NothingToUpdate(t *testing.T) {
+	ctx := context.Background()
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	_, err := service.UpdateUser(ctx, "token", uuid.New(), admin.UserUpdate{})
+
+	assert.ErrorIs(t, err, admin.ErrNothingToUpdate)
+}
+
+func TestDisableUser_Success(t *testing.T) {
+	ctx := context.Background()
+	testUser := &userModel.User{ID: uuid.New(), Email: "user@example.com"}
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersWrite).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, testUser.ID).
+		Return(testUser, nil)
+
+	mockTxManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	mockUserRepo.EXPECT().
+		SetStatus(ctx, testUser.ID, userModel.Sources(userModel.StatusDisabled), userModel.StatusDisabled).
+		Return(userModel.StatusActive, nil)
+
+	mockEventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserStatusChangedV1
+		})).
+		Return(uuid.New(), nil)
+
+	mockCacheRepo.EXPECT().
+		InvalidateUser(ctx, testUser.ID).
+		Return(nil).
+		Once()
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	err := service.DisableUser(ctx, "token", testUser.ID)
+
+	require.NoError(t, err)
+}
+
+func TestDeleteUser_PermissionDenied(t *testing.T) {
+	ctx := context.Background()
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersDelete).
+		Return(nil, auth.ErrPermissionDenied)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	err := service.DeleteUser(ctx, "token", uuid.New())
+
+	assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+	mockUserRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestDeleteUser_AlreadyDeleted(t *testing.T) {
+	ctx := context.Background()
+	testUser := &userModel.User{ID: uuid.New(), Email: "user@example.com", Status: userModel.StatusDeleted}
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersDelete).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, testUser.ID).
+		Return(testUser, nil)
+
+	mockTxManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	mockUserRepo.EXPECT().
+		SetStatus(ctx, testUser.ID, userModel.Sources(userModel.StatusDeleted), userModel.StatusDeleted).
+		Return(userModel.StatusDeleted, storage.ErrStatusConflict)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	err := service.DeleteUser(ctx, "token", testUser.ID)
+
+	assert.ErrorIs(t, err, admin.ErrInvalidTransition)
+	mockEventRepo.AssertNotCalled(t, "Save")
+}
+
+func TestListSessions_Success(t *testing.T) {
+	ctx := context.Background()
+	testUser := &userModel.User{ID: uuid.New(), Email: "user@example.com"}
+	sessions := []sessionModel.Session{
+		{ID: uuid.New(), UserID: testUser.ID, UserAgent: "curl/8.0"},
+	}
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersRead).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, testUser.ID).
+		Return(testUser, nil)
+
+	mockSessions.EXPECT().
+		ListUserSessions(ctx, testUser.ID).
+		Return(sessions, nil)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	got, err := service.ListSessions(ctx, "token", testUser.ID)
+
+	require.NoError(t, err)
+	assert.Equal(t, sessions, got)
+}
+
+func TestRevokeSession_PassesActor(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersWrite).
+		Return(adminClaims, nil)
+
+	mockSessions.EXPECT().
+		RevokeUserSession(ctx, adminClaims.UserID, userID, sessionID).
+		Return(auth.ErrSessionNotFound)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	err := service.RevokeSession(ctx, "token", userID, sessionID)
+
+	assert.ErrorIs(t, err, auth.ErrSessionNotFound)
+}
+
+func TestRevokeAllSessions_UserNotFound(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	mockTxManager := mocks.NewMockTxManager(t)
+	mockUserRepo := mocks.NewMockUserRepo(t)
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+	mockSessions := mocks.NewMockSessions(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermUsersWrite).
+		Return(adminClaims, nil)
+
+	mockUserRepo.EXPECT().
+		GetByID(ctx, userID).
+		Return(nil, storage.ErrUserNotFound)
+
+	service := admin.NewAdminService(testutils.Log, mockTxManager, mockUserRepo, mockEventRepo, mockCacheRepo, mockAuthorizer, mockSessions)
+
+	err := service.RevokeAllSessions(ctx, "token", userID)
+
+	assert.ErrorIs(t, err, auth.ErrUserNotFound)
+	mockSessions.AssertNotCalled(t, "RevokeUserSessions")
+}
@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockCacheRepo is an autogenerated mock type for the CacheRepo type
+type MockCacheRepo struct {
+	mock.Mock
+}
+
+type MockCacheRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCacheRepo) EXPECT() *MockCacheRepo_Expecter {
+	return &MockCacheRepo_Expecter{mock: &_m.Mock}
+}
+
+// InvalidateUser provides a mock function with given fields: ctx, userID
+func (_m *MockCacheRepo) InvalidateUser(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_InvalidateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateUser'
+type MockCacheRepo_InvalidateUser_Call struct {
+	*mock.Call
+}
+
+// InvalidateUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockCacheRepo_Expecter) InvalidateUser(ctx interface{}, userID interface{}) *MockCacheRepo_InvalidateUser_Call {
+	return &MockCacheRepo_InvalidateUser_Call{Call: _e.mock.On("InvalidateUser", ctx, userID)}
+}
+
+func (_c *MockCacheRepo_InvalidateUser_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockCacheRepo_InvalidateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_InvalidateUser_Call) Return(_a0 error) *MockCacheRepo_InvalidateUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_InvalidateUser_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockCacheRepo_InvalidateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCacheRepo creates a new instance of MockCacheRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCacheRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCacheRepo {
+	mock := &MockCacheRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,195 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	sessionModel "github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockSessions is an autogenerated mock type for the Sessions type
+type MockSessions struct {
+	mock.Mock
+}
+
+type MockSessions_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSessions) EXPECT() *MockSessions_Expecter {
+	return &MockSessions_Expecter{mock: &_m.Mock}
+}
+
+// ListUserSessions provides a mock function with given fields: ctx, userID
+func (_m *MockSessions) ListUserSessions(ctx context.Context, userID uuid.UUID) ([]sessionModel.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUserSessions")
+	}
+
+	var r0 []sessionModel.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]sessionModel.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []sessionModel.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sessionModel.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessions_ListUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListUserSessions'
+type MockSessions_ListUserSessions_Call struct {
+	*mock.Call
+}
+
+// ListUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockSessions_Expecter) ListUserSessions(ctx interface{}, userID interface{}) *MockSessions_ListUserSessions_Call {
+	return &MockSessions_ListUserSessions_Call{Call: _e.mock.On("ListUserSessions", ctx, userID)}
+}
+
+func (_c *MockSessions_ListUserSessions_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockSessions_ListUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessions_ListUserSessions_Call) Return(_a0 []sessionModel.Session, _a1 error) *MockSessions_ListUserSessions_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessions_ListUserSessions_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]sessionModel.Session, error)) *MockSessions_ListUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserSession provides a mock function with given fields: ctx, actorID, userID, sessionID
+func (_m *MockSessions) RevokeUserSession(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, sessionID uuid.UUID) error {
+	ret := _m.Called(ctx, actorID, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, actorID, userID, sessionID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessions_RevokeUserSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSession'
+type MockSessions_RevokeUserSession_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - userID uuid.UUID
+//   - sessionID uuid.UUID
+func (_e *MockSessions_Expecter) RevokeUserSession(ctx interface{}, actorID interface{}, userID interface{}, sessionID interface{}) *MockSessions_RevokeUserSession_Call {
+	return &MockSessions_RevokeUserSession_Call{Call: _e.mock.On("RevokeUserSession", ctx, actorID, userID, sessionID)}
+}
+
+func (_c *MockSessions_RevokeUserSession_Call) Run(run func(ctx context.Context, actorID uuid.UUID, userID uuid.UUID, sessionID uuid.UUID)) *MockSessions_RevokeUserSession_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID), args[3].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessions_RevokeUserSession_Call) Return(_a0 error) *MockSessions_RevokeUserSession_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessions_RevokeUserSession_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID, uuid.UUID) error) *MockSessions_RevokeUserSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserSessions provides a mock function with given fields: ctx, actorID, userID
+func (_m *MockSessions) RevokeUserSessions(ctx context.Context, actorID uuid.UUID, userID uuid.UUID) error {
+	ret := _m.Called(ctx, actorID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserSessions")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, actorID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessions_RevokeUserSessions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserSessions'
+type MockSessions_RevokeUserSessions_Call struct {
+	*mock.Call
+}
+
+// RevokeUserSessions is a helper method to define mock.On call
+//   - ctx context.Context
+//   - actorID uuid.UUID
+//   - userID uuid.UUID
+func (_e *MockSessions_Expecter) RevokeUserSessions(ctx interface{}, actorID interface{}, userID interface{}) *MockSessions_RevokeUserSessions_Call {
+	return &MockSessions_RevokeUserSessions_Call{Call: _e.mock.On("RevokeUserSessions", ctx, actorID, userID)}
+}
+
+func (_c *MockSessions_RevokeUserSessions_Call) Run(run func(ctx context.Context, actorID uuid.UUID, userID uuid.UUID)) *MockSessions_RevokeUserSessions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessions_RevokeUserSessions_Call) Return(_a0 error) *MockSessions_RevokeUserSessions_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessions_RevokeUserSessions_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) error) *MockSessions_RevokeUserSessions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSessions creates a new instance of MockSessions. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSessions(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSessions {
+	mock := &MockSessions{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,360 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	userModel "github.com/Tbits007/auth/internal/domain/models/userModel"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockUserRepo is an autogenerated mock type for the UserRepo type
+type MockUserRepo struct {
+	mock.Mock
+}
+
+type MockUserRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserRepo) EXPECT() *MockUserRepo_Expecter {
+	return &MockUserRepo_Expecter{mock: &_m.Mock}
+}
+
+// AssignRole provides a mock function with given fields: ctx, userID, role
+func (_m *MockUserRepo) AssignRole(ctx context.Context, userID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepo_AssignRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AssignRole'
+type MockUserRepo_AssignRole_Call struct {
+	*mock.Call
+}
+
+// AssignRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - role string
+func (_e *MockUserRepo_Expecter) AssignRole(ctx interface{}, userID interface{}, role interface{}) *MockUserRepo_AssignRole_Call {
+	return &MockUserRepo_AssignRole_Call{Call: _e.mock.On("AssignRole", ctx, userID, role)}
+}
+
+func (_c *MockUserRepo_AssignRole_Call) Run(run func(ctx context.Context, userID uuid.UUID, role string)) *MockUserRepo_AssignRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_AssignRole_Call) Return(_a0 error) *MockUserRepo_AssignRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepo_AssignRole_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockUserRepo_AssignRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function with given fields: ctx, userID
+func (_m *MockUserRepo) GetByID(ctx context.Context, userID uuid.UUID) (*userModel.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *userModel.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*userModel.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *userModel.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*userModel.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepo_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockUserRepo_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockUserRepo_Expecter) GetByID(ctx interface{}, userID interface{}) *MockUserRepo_GetByID_Call {
+	return &MockUserRepo_GetByID_Call{Call: _e.mock.On("GetByID", ctx, userID)}
+}
+
+func (_c *MockUserRepo_GetByID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockUserRepo_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_GetByID_Call) Return(_a0 *userModel.User, _a1 error) *MockUserRepo_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepo_GetByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*userModel.User, error)) *MockUserRepo_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function with given fields: ctx, filter
+func (_m *MockUserRepo) List(ctx context.Context, filter userModel.ListFilter) ([]userModel.User, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []userModel.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, userModel.ListFilter) ([]userModel.User, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, userModel.ListFilter) []userModel.User); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]userModel.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, userModel.ListFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepo_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockUserRepo_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter userModel.ListFilter
+func (_e *MockUserRepo_Expecter) List(ctx interface{}, filter interface{}) *MockUserRepo_List_Call {
+	return &MockUserRepo_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockUserRepo_List_Call) Run(run func(ctx context.Context, filter userModel.ListFilter)) *MockUserRepo_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(userModel.ListFilter))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_List_Call) Return(_a0 []userModel.User, _a1 error) *MockUserRepo_List_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepo_List_Call) RunAndReturn(run func(context.Context, userModel.ListFilter) ([]userModel.User, error)) *MockUserRepo_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeRole provides a mock function with given fields: ctx, userID, role
+func (_m *MockUserRepo) RevokeRole(ctx context.Context, userID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepo_RevokeRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRole'
+type MockUserRepo_RevokeRole_Call struct {
+	*mock.Call
+}
+
+// RevokeRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - role string
+func (_e *MockUserRepo_Expecter) RevokeRole(ctx interface{}, userID interface{}, role interface{}) *MockUserRepo_RevokeRole_Call {
+	return &MockUserRepo_RevokeRole_Call{Call: _e.mock.On("RevokeRole", ctx, userID, role)}
+}
+
+func (_c *MockUserRepo_RevokeRole_Call) Run(run func(ctx context.Context, userID uuid.UUID, role string)) *MockUserRepo_RevokeRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_RevokeRole_Call) Return(_a0 error) *MockUserRepo_RevokeRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepo_RevokeRole_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockUserRepo_RevokeRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetStatus provides a mock function with given fields: ctx, userID, from, to
+func (_m *MockUserRepo) SetStatus(ctx context.Context, userID uuid.UUID, from []userModel.Status, to userModel.Status) (userModel.Status, error) {
+	ret := _m.Called(ctx, userID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStatus")
+	}
+
+	var r0 userModel.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) (userModel.Status, error)); ok {
+		return rf(ctx, userID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) userModel.Status); ok {
+		r0 = rf(ctx, userID, from, to)
+	} else {
+		r0 = ret.Get(0).(userModel.Status)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) error); ok {
+		r1 = rf(ctx, userID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepo_SetStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetStatus'
+type MockUserRepo_SetStatus_Call struct {
+	*mock.Call
+}
+
+// SetStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - from []userModel.Status
+//   - to userModel.Status
+func (_e *MockUserRepo_Expecter) SetStatus(ctx interface{}, userID interface{}, from interface{}, to interface{}) *MockUserRepo_SetStatus_Call {
+	return &MockUserRepo_SetStatus_Call{Call: _e.mock.On("SetStatus", ctx, userID, from, to)}
+}
+
+func (_c *MockUserRepo_SetStatus_Call) Run(run func(ctx context.Context, userID uuid.UUID, from []userModel.Status, to userModel.Status)) *MockUserRepo_SetStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].([]userModel.Status), args[3].(userModel.Status))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_SetStatus_Call) Return(_a0 userModel.Status, _a1 error) *MockUserRepo_SetStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepo_SetStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) (userModel.Status, error)) *MockUserRepo_SetStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateEmail provides a mock function with given fields: ctx, userID, email
+func (_m *MockUserRepo) UpdateEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	ret := _m.Called(ctx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepo_UpdateEmail_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateEmail'
+type MockUserRepo_UpdateEmail_Call struct {
+	*mock.Call
+}
+
+// UpdateEmail is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - email string
+func (_e *MockUserRepo_Expecter) UpdateEmail(ctx interface{}, userID interface{}, email interface{}) *MockUserRepo_UpdateEmail_Call {
+	return &MockUserRepo_UpdateEmail_Call{Call: _e.mock.On("UpdateEmail", ctx, userID, email)}
+}
+
+func (_c *MockUserRepo_UpdateEmail_Call) Run(run func(ctx context.Context, userID uuid.UUID, email string)) *MockUserRepo_UpdateEmail_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_UpdateEmail_Call) Return(_a0 error) *MockUserRepo_UpdateEmail_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepo_UpdateEmail_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockUserRepo_UpdateEmail_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserRepo creates a new instance of MockUserRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserRepo {
+	mock := &MockUserRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
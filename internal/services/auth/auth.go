@@ -2,24 +2,53 @@ package auth
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"time"
 
 	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/mfaModel"
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
 	"github.com/Tbits007/auth/internal/domain/models/userModel"
 	"github.com/Tbits007/auth/internal/lib/jwt"
 	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/lib/passkey"
+	"github.com/Tbits007/auth/internal/lib/requestid"
 	"github.com/Tbits007/auth/internal/storage"
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
-	"golang.org/x/crypto/bcrypt"
 )
 
 var (
-    ErrInvalidCredentials = errors.New("invalid credentials")
+    ErrInvalidCredentials       = errors.New("invalid credentials")
+    ErrInvalidRefreshToken      = errors.New("invalid refresh token")
+    ErrRefreshTokenReused       = errors.New("refresh token reused")
+    ErrInvalidToken             = errors.New("invalid token")
+    ErrTokenRevoked             = errors.New("token revoked")
+    ErrUserNotFound             = errors.New("user not found")
+    ErrPermissionDenied         = errors.New("permission denied")
+    ErrUnknownRole              = errors.New("unknown role")
+    ErrAccountNotVerified       = errors.New("account not verified")
+    ErrAccountDisabled          = errors.New("account disabled")
+    ErrAccountDeleted           = errors.New("account deleted")
+    ErrInvalidVerificationToken = errors.New("invalid verification token")
+    ErrNotPendingVerification   = errors.New("account is not pending verification")
+    ErrRateLimited              = errors.New("rate limited")
+    ErrInvalidResetToken        = errors.New("invalid password reset token")
+    ErrInvalidPassword          = errors.New("password does not meet policy")
+    ErrTooManyAttempts          = errors.New("too many failed login attempts")
+    ErrMFAAlreadyEnabled        = errors.New("mfa already enabled")
+    ErrMFANotEnrolled           = errors.New("mfa not enrolled")
+    ErrMFAEnrollmentRequired    = errors.New("mfa enrollment required")
+    ErrInvalidMFAChallenge      = errors.New("invalid mfa challenge")
+    ErrInvalidMFACode           = errors.New("invalid mfa code")
+    ErrInvalidPasskey           = errors.New("invalid passkey")
+    ErrInvalidPasskeyChallenge  = errors.New("invalid passkey challenge")
+    ErrPasskeyExists            = errors.New("passkey already registered")
+    ErrSessionNotFound          = errors.New("session not found")
 )
 
 
@@ -34,10 +63,46 @@ type UserRepo interface {
 		email string,
 	) (*userModel.User, error)
 
+	GetByID(
+		ctx context.Context,
+		userID uuid.UUID,
+	) (*userModel.User, error)
+
 	IsAdmin(
 		ctx context.Context,
 		userID uuid.UUID,
 	) (bool, error)
+
+	SetStatus(
+		ctx context.Context,
+		userID uuid.UUID,
+		from []userModel.Status,
+		to userModel.Status,
+	) (userModel.Status, error)
+
+	UpdatePassword(
+		ctx context.Context,
+		userID uuid.UUID,
+		hashedPassword string,
+	) error
+
+	HasPermission(
+		ctx context.Context,
+		userID uuid.UUID,
+		permission string,
+	) (bool, error)
+
+	AssignRole(
+		ctx context.Context,
+		userID uuid.UUID,
+		role string,
+	) error
+
+	RevokeRole(
+		ctx context.Context,
+		userID uuid.UUID,
+		role string,
+	) error
 }
 
 type EventRepo interface {
@@ -47,6 +112,33 @@ type EventRepo interface {
 	) (uuid.UUID, error)
 }
 
+type RefreshTokenRepo interface {
+	Save(
+		ctx context.Context,
+		token tokenModel.RefreshToken,
+	) (uuid.UUID, error)
+
+	GetByHash(
+		ctx context.Context,
+		tokenHash string,
+	) (*tokenModel.RefreshToken, error)
+
+	MarkUsed(
+		ctx context.Context,
+		tokenID uuid.UUID,
+	) error
+
+	RevokeFamily(
+		ctx context.Context,
+		familyID uuid.UUID,
+	) error
+
+	RevokeAllForUser(
+		ctx context.Context,
+		userID uuid.UUID,
+	) error
+}
+
 type TxManager interface {
 	WithTransaction(
 		ctx context.Context,
@@ -55,46 +147,406 @@ type TxManager interface {
 }
 
 type CacheRepo interface {
-	Set(
-		ctx 	   context.Context,
-		key        string,
-		value 	   any, 
+	SetMFAChallenge(
+		ctx        context.Context,
+		id         string,
+		challenge  []byte,
+		expiration time.Duration,
+	) error
+
+	GetMFAChallenge(
+		ctx context.Context,
+		id  string,
+	) ([]byte, error)
+
+	TakeMFAChallenge(
+		ctx context.Context,
+		id  string,
+	) ([]byte, error)
+
+	DeleteMFAChallenge(
+		ctx context.Context,
+		id  string,
+	) error
+
+	SetPasskeyRegistration(
+		ctx        context.Context,
+		userID     uuid.UUID,
+		session    []byte,
+		expiration time.Duration,
+	) error
+
+	TakePasskeyRegistration(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) ([]byte, error)
+
+	SetPasskeyLogin(
+		ctx         context.Context,
+		challengeID string,
+		session     []byte,
+		expiration  time.Duration,
+	) error
+
+	TakePasskeyLogin(
+		ctx         context.Context,
+		challengeID string,
+	) ([]byte, error)
+
+	GetUserStatus(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) (userModel.Status, int64, error)
+
+	SetUserStatus(
+		ctx        context.Context,
+		userID     uuid.UUID,
+		status     userModel.Status,
+		version    int64,
+		expiration time.Duration,
+	) error
+
+	GetUserAdmin(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) (bool, int64, error)
+
+	SetUserAdmin(
+		ctx        context.Context,
+		userID     uuid.UUID,
+		isAdmin    bool,
+		version    int64,
 		expiration time.Duration,
-	) error 
+	) error
+
+	InvalidateUser(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) error
+
+	RevokeToken(
+		ctx        context.Context,
+		jti        string,
+		expiration time.Duration,
+	) error
+
+	RevokeUserTokens(
+		ctx        context.Context,
+		userID     uuid.UUID,
+		revokedAt  time.Time,
+		keep       uuid.UUID,
+		expiration time.Duration,
+	) error
+
+	RevokeSession(
+		ctx        context.Context,
+		sessionID  uuid.UUID,
+		expiration time.Duration,
+	) error
+
+	IsTokenRevoked(
+		ctx       context.Context,
+		jti       string,
+		userID    uuid.UUID,
+		sessionID uuid.UUID,
+		issuedAt  time.Time,
+	) (bool, error)
+}
+
+type UserTokenRepo interface {
+	Save(
+		ctx   context.Context,
+		token tokenModel.UserToken,
+	) (uuid.UUID, error)
+
+	Consume(
+		ctx       context.Context,
+		purpose   string,
+		tokenHash string,
+	) (*tokenModel.UserToken, error)
+
+	InvalidateAll(
+		ctx     context.Context,
+		userID  uuid.UUID,
+		purpose string,
+	) error
+}
+
+type RateLimiter interface {
+	AllowMFAAttempt(
+		ctx         context.Context,
+		challengeID string,
+		limit       int,
+		period      time.Duration,
+	) error
+
+	AllowVerificationResend(
+		ctx    context.Context,
+		email  string,
+		limit  int,
+		period time.Duration,
+	) error
+}
+
+type LoginAttemptRepo interface {
+	RegisterFailure(
+		ctx    context.Context,
+		key    string,
+		window time.Duration,
+	) (int, error)
+
+	Lock(
+		ctx      context.Context,
+		key      string,
+		duration time.Duration,
+	) error
+
+	LockedFor(
+		ctx  context.Context,
+		keys ...string,
+	) (time.Duration, error)
 
-	Get(
+	Reset(
 		ctx context.Context,
 		key string,
-	) (string, error)		
+	) error
+}
+
+type MFARepo interface {
+	SaveTOTP(
+		ctx             context.Context,
+		userID          uuid.UUID,
+		encryptedSecret []byte,
+	) error
+
+	GetTOTP(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) (*mfaModel.TOTP, error)
+
+	ConfirmTOTP(
+		ctx    context.Context,
+		userID uuid.UUID,
+		step   int64,
+	) error
+
+	UseTOTPStep(
+		ctx    context.Context,
+		userID uuid.UUID,
+		step   int64,
+	) error
+
+	ReplaceRecoveryCodes(
+		ctx        context.Context,
+		userID     uuid.UUID,
+		codeHashes []string,
+	) error
+
+	UseRecoveryCode(
+		ctx      context.Context,
+		userID   uuid.UUID,
+		codeHash string,
+	) error
+}
+
+type PasskeyRepo interface {
+	Save(
+		ctx        context.Context,
+		credential passkeyModel.Credential,
+	) (uuid.UUID, error)
+
+	GetByCredentialID(
+		ctx          context.Context,
+		credentialID []byte,
+	) (*passkeyModel.Credential, error)
+
+	ListByUser(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) ([]passkeyModel.Credential, error)
+
+	UpdateSignCount(
+		ctx          context.Context,
+		credentialID []byte,
+		signCount    uint32,
+	) error
+}
+
+type SessionRepo interface {
+	Save(
+		ctx     context.Context,
+		session sessionModel.Session,
+	) (uuid.UUID, error)
+
+	Touch(
+		ctx       context.Context,
+		familyID  uuid.UUID,
+		expiresAt time.Time,
+	) (uuid.UUID, error)
+
+	ListActive(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) ([]sessionModel.Session, error)
+
+	Revoke(
+		ctx       context.Context,
+		userID    uuid.UUID,
+		sessionID uuid.UUID,
+	) (*sessionModel.Session, error)
+
+	RevokeByFamily(
+		ctx      context.Context,
+		familyID uuid.UUID,
+	) (uuid.UUID, error)
+
+	RevokeAllForUser(
+		ctx    context.Context,
+		userID uuid.UUID,
+	) error
+}
+
+// RelyingParty runs the WebAuthn ceremonies; see passkey.RelyingParty.
+type RelyingParty interface {
+	BeginRegistration(user passkey.User) (options, session []byte, err error)
+
+	FinishRegistration(
+		user     passkey.User,
+		session  []byte,
+		response []byte,
+	) (passkeyModel.Credential, error)
+
+	BeginLogin() (options, session []byte, err error)
+
+	FinishLogin(
+		session  []byte,
+		response []byte,
+		lookup   passkey.UserLookup,
+	) (passkeyModel.Credential, error)
+}
+
+// SecretBox encrypts secrets at rest, binding each to additionalData.
+type SecretBox interface {
+	Seal(plaintext, additionalData []byte) ([]byte, error)
+	Open(ciphertext, additionalData []byte) ([]byte, error)
+}
+
+type PasswordPolicy interface {
+	Validate(password string) error
+}
+
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(encoded, password string) (bool, error)
+	// NeedsRehash reports whether encoded uses a legacy algorithm or
+	// weaker parameters than new hashes get.
+	NeedsRehash(encoded string) bool
+}
+
+// Config holds the token lifetimes and per-flow limits of AuthService.
+type Config struct {
+	TokenTTL              time.Duration
+	RefreshTokenTTL       time.Duration
+	VerificationTokenTTL  time.Duration
+	PasswordResetTokenTTL time.Duration
+	// ResendVerificationLimit caps ResendVerification calls per email
+	// within ResendVerificationPeriod.
+	ResendVerificationLimit  int
+	ResendVerificationPeriod time.Duration
+	// After LoginLockoutThreshold failed logins for an email, or
+	// IPLockoutThreshold from one client IP, within LoginLockoutWindow,
+	// logins are refused for LoginLockoutDuration. Every further failure
+	// doubles the lockout up to MaxLoginLockoutDuration. A zero threshold
+	// disables that lockout.
+	LoginLockoutThreshold   int
+	IPLockoutThreshold      int
+	LoginLockoutWindow      time.Duration
+	LoginLockoutDuration    time.Duration
+	MaxLoginLockoutDuration time.Duration
+	// MFAIssuer names the service in authenticator apps. An MFA challenge
+	// expires after MFAChallengeTTL and allows MFAMaxAttempts codes.
+	// Confirming an enrollment issues RecoveryCodeCount recovery codes.
+	MFAIssuer         string
+	MFAChallengeTTL   time.Duration
+	MFAMaxAttempts    int
+	RecoveryCodeCount int
+	// PasskeyChallengeTTL bounds the time between the begin and finish
+	// calls of a passkey ceremony.
+	PasskeyChallengeTTL time.Duration
+	// UserCacheTTL bounds how long a user's account status and admin flag
+	// are cached. Zero disables the cache.
+	UserCacheTTL time.Duration
 }
 
 type AuthService struct {
-	log       *slog.Logger
-	txManager  TxManager
-	userRepo   UserRepo
-	eventRepo  EventRepo
-	cacheRepo  CacheRepo
-	tokenTTL   time.Duration
-	secretKey  string
+	log              *slog.Logger
+	txManager         TxManager
+	userRepo          UserRepo
+	eventRepo         EventRepo
+	cacheRepo         CacheRepo
+	refreshTokenRepo  RefreshTokenRepo
+	userTokenRepo     UserTokenRepo
+	rateLimiter       RateLimiter
+	loginAttemptRepo  LoginAttemptRepo
+	mfaRepo           MFARepo
+	secretBox         SecretBox
+	tokenBox          SecretBox
+	passkeyRepo       PasskeyRepo
+	relyingParty      RelyingParty
+	sessionRepo       SessionRepo
+	cfg               Config
+	tokenIssuer      *jwt.Issuer
+	passwordPolicy    PasswordPolicy
+	passwordHasher    PasswordHasher
+}
+
+// AuthDeps holds the collaborators an AuthService is built from.
+type AuthDeps struct {
+	TxManager        TxManager
+	UserRepo         UserRepo
+	EventRepo        EventRepo
+	CacheRepo        CacheRepo
+	RefreshTokenRepo RefreshTokenRepo
+	UserTokenRepo    UserTokenRepo
+	RateLimiter      RateLimiter
+	LoginAttemptRepo LoginAttemptRepo
+	MFARepo          MFARepo
+	SecretBox        SecretBox
+	// TokenBox seals the tokens carried by events for the mailer.
+	TokenBox         SecretBox
+	PasskeyRepo      PasskeyRepo
+	RelyingParty     RelyingParty
+	SessionRepo      SessionRepo
+	TokenIssuer      *jwt.Issuer
+	PasswordPolicy   PasswordPolicy
+	PasswordHasher   PasswordHasher
 }
 
 func NewAuthService(
-	log *slog.Logger,
-	txManager TxManager,
-	userRepo  UserRepo,
-	eventRepo EventRepo,
-	cacheRepo CacheRepo,
-	tokenTTL  time.Duration,
-	secretKey  string,
+	log  *slog.Logger,
+	deps AuthDeps,
+	cfg  Config,
 ) *AuthService {
 	return &AuthService{
-		log: 	   log,
-		txManager: txManager,
-		userRepo:  userRepo,
-		eventRepo: eventRepo,
-		cacheRepo: cacheRepo,
-		tokenTTL:  tokenTTL,
-		secretKey: secretKey,
+		log: 	          log,
+		txManager:        deps.TxManager,
+		userRepo:         deps.UserRepo,
+		eventRepo:        deps.EventRepo,
+		cacheRepo:        deps.CacheRepo,
+		refreshTokenRepo: deps.RefreshTokenRepo,
+		userTokenRepo:    deps.UserTokenRepo,
+		rateLimiter:      deps.RateLimiter,
+		loginAttemptRepo: deps.LoginAttemptRepo,
+		mfaRepo:          deps.MFARepo,
+		secretBox:        deps.SecretBox,
+		tokenBox:         deps.TokenBox,
+		passkeyRepo:      deps.PasskeyRepo,
+		relyingParty:     deps.RelyingParty,
+		sessionRepo:      deps.SessionRepo,
+		cfg:              cfg,
+		tokenIssuer:      deps.TokenIssuer,
+		passwordPolicy:   deps.PasswordPolicy,
+		passwordHasher:   deps.PasswordHasher,
 	}
 }
 
@@ -110,7 +562,14 @@ func (au *AuthService) Register(
 			slog.String("op", op),
 		)		
 
-		passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		email = userModel.NormalizeEmail(email)
+
+		if err := au.checkPassword(password); err != nil {
+			log.Info("password rejected", sl.Err(err))
+			return uuid.Nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		passHash, err := au.passwordHasher.Hash(password)
 		if err != nil {
 			log.Error("failed to generate password hash", sl.Err(err))
 			return uuid.Nil, fmt.Errorf("%s: generate password hash:%w", op, err)
@@ -118,24 +577,8 @@ func (au *AuthService) Register(
 		
 		user := userModel.User{
 			Email: email,
-			HashedPassword: string(passHash),
-		}
-
-		eventPayload := map[string]any{
-			"email":     email,
-			"action":    "registration",
-			"timestamp": time.Now().Format(time.RFC3339),
-		}
-
-		payloadBytes, err := json.Marshal(eventPayload)
-		if err != nil {
-			log.Error("failed to marshal eventPayload", sl.Err(err))
-			return uuid.Nil, fmt.Errorf("%s: marshal event payload: %w", op, err)
-		}	
-
-		event := eventModel.Event{
-			Payload: payloadBytes,
-			Status: eventModel.PENDING,
+			HashedPassword: passHash,
+			Status: userModel.StatusPendingVerification,
 		}
 
 		var userID uuid.UUID
@@ -145,11 +588,18 @@ func (au *AuthService) Register(
 			if err != nil {
 				return err 
 			}
+			event, err := au.newEvent(ctx, eventModel.UserRegisteredV1, userID, eventModel.UserRegistered{
+				UserID: userID,
+				Email:  email,
+			})
+			if err != nil {
+				return err
+			}
 			_, err = au.eventRepo.Save(ctx, event)
 			if err != nil {
 				return err 
 			}
-			return nil 
+			return au.requestVerification(ctx, userID, email)
 		})
 
 		if err != nil {
@@ -164,70 +614,532 @@ func (au *AuthService) Login(
     ctx context.Context,
     email string,
     password string,  
-) (string, error) {
+) (tokenModel.LoginResult, error) {
     const op = "AuthService.Login"
 
     log := au.log.With(
         slog.String("op", op),
     )
 
+	email = userModel.NormalizeEmail(email)
+
+	if err := au.checkLockout(ctx, log, email); err != nil {
+		log.Info("login locked out", sl.Err(err))
+		return tokenModel.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
 	user, err := au.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		if errors.Is(err, storage.ErrUserNotFound) {
 			log.Error("user not found", sl.Err(err))
-			return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+			au.saveEvent(ctx, log, eventModel.UserLoginFailedV1, uuid.Nil, eventModel.UserLoginFailed{
+				Email:  email,
+				Reason: eventModel.LoginFailedUnknownUser,
+			})
+			au.registerLoginFailure(ctx, log, email, nil)
+			return tokenModel.LoginResult{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
 		}
 		log.Error("failed to get user", sl.Err(err))
-		return "", fmt.Errorf("%s: %w", op, err)
+		return tokenModel.LoginResult{}, fmt.Errorf("%s: %w", op, err)
 	}
 
-    if err := bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(password)); err != nil {
+    if err := au.verifyPassword(user, password); err != nil {
         log.Info("invalid credentials", sl.Err(err))
-        return "", fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+        au.saveEvent(ctx, log, eventModel.UserLoginFailedV1, user.ID, eventModel.UserLoginFailed{
+            UserID: &user.ID,
+            Email:  email,
+            Reason: eventModel.LoginFailedInvalidPassword,
+        })
+        au.registerLoginFailure(ctx, log, email, &user.ID)
+        return tokenModel.LoginResult{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
     }	
 
-	token, err := au.cacheRepo.Get(ctx, email)
+	if err := statusError(user.Status); err != nil {
+		log.Info("account not active", slog.String("user_id", user.ID.String()), slog.String("status", string(user.Status)))
+		return tokenModel.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	au.resetLoginFailures(ctx, log, email)
+	au.rehashPassword(ctx, log, user, password)
+
+	if user.MFAEnabled || user.IsAdmin {
+		challenge, err := au.newMFAChallenge(ctx, user.ID, !user.MFAEnabled)
+		if err != nil {
+			log.Error("failed to issue mfa challenge", sl.Err(err))
+			return tokenModel.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("mfa challenge issued",
+			slog.String("user_id", user.ID.String()),
+			slog.Bool("enrollment_required", challenge.EnrollmentRequired),
+		)
+		return tokenModel.LoginResult{MFAChallenge: &challenge}, nil
+	}
+
+	tokens, err := au.completeLogin(ctx, log, user)
 	if err != nil {
-		log.Debug("cache miss", sl.Err(err))
-	} else {
-		log.Debug("cache hit")
-		return token, nil
+		return tokenModel.LoginResult{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokenModel.LoginResult{Tokens: tokens}, nil
+}
+
+// completeLogin issues the tokens of a new session for user, whose
+// credentials were checked.
+func (au *AuthService) completeLogin(
+	ctx  context.Context,
+	log  *slog.Logger,
+	user *userModel.User,
+) (tokenModel.TokenPair, error) {
+	tokens, err := au.newSession(ctx, log, user)
+	if err != nil {
+		return tokenModel.TokenPair{}, err
+	}
+
+	au.saveEvent(ctx, log, eventModel.UserLoggedInV1, user.ID, eventModel.UserLoggedIn{
+		UserID: user.ID,
+		Email:  user.Email,
+	})
+
+	return tokens, nil
+}
+
+func (au *AuthService) Refresh(
+	ctx context.Context,
+	refreshToken string,
+) (tokenModel.TokenPair, error) {
+	const op = "AuthService.Refresh"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	stored, err := au.refreshTokenRepo.GetByHash(ctx, opaque.Hash(refreshToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Info("refresh token not found")
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		log.Error("failed to get refresh token", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("family_id", stored.FamilyID.String()))
+
+	if stored.RevokedAt != nil {
+		log.Info("refresh token revoked")
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	if stored.UsedAt != nil {
+		log.Warn("refresh token reuse detected, revoking family")
+		au.revokeFamily(ctx, log, stored.FamilyID)
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrRefreshTokenReused)
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		log.Info("refresh token expired")
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+	}
+
+	user, err := au.userRepo.GetByID(ctx, stored.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("refresh token owner not found")
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidRefreshToken)
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := statusError(user.Status); err != nil {
+		log.Info("account not active", slog.String("status", string(user.Status)))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Login makes admins enroll, but a session may predate the promotion
+	// to admin; it has to log in again and enroll.
+	if user.IsAdmin && !user.MFAEnabled {
+		log.Info("admin without mfa", slog.String("user_id", user.ID.String()))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrMFAEnrollmentRequired)
+	}
+
+	var (
+		sessionID       uuid.UUID
+		newRefreshToken string
+	)
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := au.refreshTokenRepo.MarkUsed(ctx, stored.ID); err != nil {
+			return err
+		}
+
+		sessionID, err = au.sessionRepo.Touch(ctx, stored.FamilyID, time.Now().Add(au.cfg.RefreshTokenTTL))
+		if err != nil {
+			if !errors.Is(err, storage.ErrSessionNotFound) {
+				return err
+			}
+			// Families issued before sessions were recorded have none.
+			log.Debug("refresh token family has no session")
+		}
+
+		newRefreshToken, err = au.issueRefreshToken(ctx, stored.UserID, stored.FamilyID)
+		return err
+	})
+
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Warn("concurrent refresh token reuse detected, revoking family")
+			au.revokeFamily(ctx, log, stored.FamilyID)
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrRefreshTokenReused)
+		}
+		log.Error("transaction failed", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	accessToken, err := au.tokenIssuer.NewSessionToken(ctx, *user, sessionID, au.cfg.TokenTTL)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokenModel.TokenPair{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// ValidateToken checks the token's signature and revocation, and that its
+// subject's account is still active.
+func (au *AuthService) ValidateToken(
+	ctx   context.Context,
+	token string,
+) (*jwt.Claims, error) {
+	const op = "AuthService.ValidateToken"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	claims, err := au.verifyToken(ctx, log, token)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.checkStatus(ctx, log, claims.UserID); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return claims, nil
+}
+
+// verifyToken is ValidateToken without the account status check.
+func (au *AuthService) verifyToken(
+	ctx   context.Context,
+	log   *slog.Logger,
+	token string,
+) (*jwt.Claims, error) {
+	claims, err := au.tokenIssuer.ParseToken(token)
+	if err != nil {
+		log.Info("failed to parse token", sl.Err(err))
+		return nil, ErrInvalidToken
+	}
+
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+
+	revoked, err := au.cacheRepo.IsTokenRevoked(ctx, claims.ID, claims.UserID, claims.SessionID, issuedAt)
+	if err != nil {
+		log.Error("failed to check token revocation", sl.Err(err))
+		return nil, err
+	}
+
+	if revoked {
+		log.Info("token revoked", slog.String("jti", claims.ID))
+		return nil, ErrTokenRevoked
+	}
+
+	return claims, nil
+}
+
+// Introspect reports whether token is currently usable. Tokens that fail
+// validation are reported as inactive rather than as an error, as in RFC 7662.
+func (au *AuthService) Introspect(
+	ctx   context.Context,
+	token string,
+) (tokenModel.Introspection, error) {
+	const op = "AuthService.Introspect"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	claims, err := au.ValidateToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenRevoked) || isAccountError(err) {
+			return tokenModel.Introspection{Active: false}, nil
+		}
+		return tokenModel.Introspection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	isAdmin, err := au.IsAdmin(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, ErrInvalidCredentials) {
+			log.Info("token subject not found", slog.String("user_id", claims.UserID.String()))
+			return tokenModel.Introspection{Active: false}, nil
+		}
+		return tokenModel.Introspection{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	introspection := tokenModel.Introspection{
+		Active:    true,
+		UserID:    claims.UserID,
+		Email:     claims.Email,
+		IsAdmin:   isAdmin,
+		ExpiresAt: claims.ExpiresAt.Time,
+	}
+	if claims.IssuedAt != nil {
+		introspection.IssuedAt = claims.IssuedAt.Time
 	}
 
-    token, err = jwt.NewToken(*user, au.tokenTTL, au.secretKey)
-    if err != nil {
-        log.Error("failed to generate token", sl.Err(err))
-        return "", fmt.Errorf("%s: %w", op, err)
-    }
+	return introspection, nil
+}
+
+func (au *AuthService) RevokeToken(
+	ctx   context.Context,
+	token string,
+) error {
+	const op = "AuthService.RevokeToken"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	claims, err := au.tokenIssuer.ParseToken(token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			log.Debug("token already expired")
+			return nil
+		}
+		log.Info("failed to parse token", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, ErrInvalidToken)
+	}
 
-	eventPayload := map[string]any{
-		"email":     email,
-		"action":    "login",
-		"timestamp": time.Now().Format(time.RFC3339),
+	if err := au.revokeClaims(ctx, log, claims); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	payloadBytes, err := json.Marshal(eventPayload)
+	return nil
+}
+
+func (au *AuthService) Logout(
+	ctx          context.Context,
+	accessToken  string,
+	refreshToken string,
+) error {
+	const op = "AuthService.Logout"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	// An account that is no longer active can still log out.
+	claims, err := au.verifyToken(ctx, log, accessToken)
 	if err != nil {
-		log.Error("failed to marshal eventPayload", sl.Err(err))
-		return "", fmt.Errorf("%s: marshal event payload: %w", op, err)
-	}	
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.revokeClaims(ctx, log, claims); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	au.saveEvent(ctx, log, eventModel.UserLoggedOutV1, claims.UserID, eventModel.UserLoggedOut{
+		UserID: claims.UserID,
+	})
+
+	if claims.SessionID != uuid.Nil {
+		err := au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+			return au.endSession(ctx, claims.UserID, claims.SessionID)
+		})
+		if err != nil && !errors.Is(err, storage.ErrSessionNotFound) {
+			log.Error("failed to end session", sl.Err(err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		return nil
+	}
+
+	// Tokens issued before sessions were recorded name the family through
+	// the refresh token instead.
+	if refreshToken == "" {
+		return nil
+	}
+
+	stored, err := au.refreshTokenRepo.GetByHash(ctx, opaque.Hash(refreshToken))
+	if err != nil {
+		if errors.Is(err, storage.ErrTokenNotFound) {
+			log.Info("refresh token not found")
+			return nil
+		}
+		log.Error("failed to get refresh token", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if stored.UserID != claims.UserID {
+		log.Warn("refresh token belongs to another user")
+		return nil
+	}
+
+	if err := au.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID); err != nil {
+		log.Error("failed to revoke token family", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (au *AuthService) revokeClaims(
+	ctx    context.Context,
+	log    *slog.Logger,
+	claims *jwt.Claims,
+) error {
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+
+	if err := au.cacheRepo.RevokeToken(ctx, claims.ID, ttl); err != nil {
+		log.Error("failed to revoke token", sl.Err(err))
+		return err
+	}
+
+	return nil
+}
 
-	event := eventModel.Event{
-		Payload: payloadBytes,
-		Status: eventModel.PENDING,
+func (au *AuthService) JWKS() jwt.JWKS {
+	return au.tokenIssuer.JWKS()
+}
+
+// newEvent builds an outbox event correlated with the current request.
+func (au *AuthService) newEvent(
+	ctx       context.Context,
+	eventType string,
+	subject   uuid.UUID,
+	data      any,
+) (eventModel.Event, error) {
+	event, err := eventModel.NewEvent(eventType, subject, requestid.FromContext(ctx), time.Now(), data)
+	if err != nil {
+		return eventModel.Event{}, fmt.Errorf("build %s event: %w", eventType, err)
 	}
 
-	_, err = au.eventRepo.Save(ctx, event)
+	return event, nil
+}
+
+// saveEvent writes an informational event outside of any transaction.
+// Failures are logged and otherwise ignored.
+func (au *AuthService) saveEvent(
+	ctx       context.Context,
+	log       *slog.Logger,
+	eventType string,
+	subject   uuid.UUID,
+	data      any,
+) {
+	event, err := au.newEvent(ctx, eventType, subject, data)
 	if err != nil {
+		log.Error("failed to build event", sl.Err(err))
+		return
+	}
+
+	if _, err := au.eventRepo.Save(ctx, event); err != nil {
 		log.Error("failed to save event", sl.Err(err))
 	}
+}
+
+// issueUserToken stores the hash of a new single-use token for purpose and
+// returns the token with its expiry.
+func (au *AuthService) issueUserToken(
+	ctx     context.Context,
+	userID  uuid.UUID,
+	purpose string,
+	ttl     time.Duration,
+) (string, time.Time, error) {
+	token, err := opaque.NewToken(opaque.DefaultSize)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("generate %s token: %w", purpose, err)
+	}
 
-	err = au.cacheRepo.Set(ctx, email, token, 1*time.Hour)
+	expiresAt := time.Now().Add(ttl)
+
+	_, err = au.userTokenRepo.Save(ctx, tokenModel.UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: opaque.Hash(token),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("save %s token: %w", purpose, err)
+	}
+
+	return token, expiresAt, nil
+}
+
+// sealUserToken encrypts a token issued by issueUserToken for the event
+// that carries it to the mailer, bound to the user it was issued to.
+func (au *AuthService) sealUserToken(userID uuid.UUID, token string) ([]byte, error) {
+	sealed, err := au.tokenBox.Seal([]byte(token), userID[:])
 	if err != nil {
-		log.Debug("failed to cache token", sl.Err(err))
+		return nil, fmt.Errorf("seal token: %w", err)
 	}
 
-    return token, nil	
+	return sealed, nil
+}
+
+func (au *AuthService) issueRefreshToken(
+	ctx context.Context,
+	userID uuid.UUID,
+	familyID uuid.UUID,
+) (string, error) {
+	token, err := opaque.NewToken(opaque.DefaultSize)
+	if err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	_, err = au.refreshTokenRepo.Save(ctx, tokenModel.RefreshToken{
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: opaque.Hash(token),
+		ExpiresAt: time.Now().Add(au.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("save refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// revokeFamily revokes a refresh token family whose tokens were reused,
+// together with its session and the session's access tokens.
+func (au *AuthService) revokeFamily(
+	ctx context.Context,
+	log *slog.Logger,
+	familyID uuid.UUID,
+) {
+	if err := au.refreshTokenRepo.RevokeFamily(ctx, familyID); err != nil {
+		log.Error("failed to revoke token family", sl.Err(err))
+	}
+
+	sessionID, err := au.sessionRepo.RevokeByFamily(ctx, familyID)
+	if err != nil {
+		if !errors.Is(err, storage.ErrSessionNotFound) {
+			log.Error("failed to revoke session", sl.Err(err))
+		}
+		return
+	}
+
+	if err := au.cacheRepo.RevokeSession(ctx, sessionID, au.cfg.TokenTTL); err != nil {
+		log.Error("failed to revoke session tokens", sl.Err(err))
+	}
 }
 
 func (au *AuthService) IsAdmin(
@@ -240,20 +1152,13 @@ func (au *AuthService) IsAdmin(
         slog.String("op", op),
     )	
 
-    cacheVal, err := au.cacheRepo.Get(ctx, userID.String())
-    if err == nil {
-        log.Debug("cache hit")
-        switch cacheVal {
-        case "true":
-            return true, nil
-        case "false":
-            return false, nil
-        default:
-            log.Warn("invalid cache value", slog.String("value", cacheVal))
-        }
-    } else if !errors.Is(err, redis.Nil) {
-        log.Debug("cache error", sl.Err(err))
-    }
+	cached, version, err := au.cacheRepo.GetUserAdmin(ctx, userID)
+	if err == nil {
+		log.Debug("cache hit")
+		return cached, nil
+	} else if !errors.Is(err, storage.ErrKeyNotFound) {
+		log.Debug("cache error", sl.Err(err))
+	}
 
 	isAdmin, err := au.userRepo.IsAdmin(ctx, userID)
 	if err != nil {
@@ -265,14 +1170,11 @@ func (au *AuthService) IsAdmin(
 		return false, fmt.Errorf("%s: %w", op, err)
 	}
 	
-    cacheValue := "false"
-    if isAdmin {
-        cacheValue = "true"
-    }
-
-    if err := au.cacheRepo.Set(ctx, userID.String(), cacheValue, 1*time.Hour); err != nil {
-        log.Warn("failed to cache admin status", sl.Err(err))
-    }
+	if au.cfg.UserCacheTTL > 0 {
+		if err := au.cacheRepo.SetUserAdmin(ctx, userID, isAdmin, version, au.cfg.UserCacheTTL); err != nil {
+			log.Warn("failed to cache admin status", sl.Err(err))
+		}
+	}
 
 	return isAdmin, nil
 }
\ No newline at end of file
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/clientip"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/google/uuid"
+)
+
+// LockoutError is returned by Login and ChangePassword while the email or
+// the client IP is locked out after too many failed attempts.
+type LockoutError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LockoutError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrTooManyAttempts, e.RetryAfter)
+}
+
+func (e *LockoutError) Unwrap() error {
+	return ErrTooManyAttempts
+}
+
+// lockoutKeys returns the keys failed logins of email are counted
+// against, with the failure threshold of each. A zero threshold disables
+// that key.
+func (au *AuthService) lockoutKeys(ctx context.Context, email string) map[string]int {
+	keys := make(map[string]int, 2)
+
+	if au.cfg.LoginLockoutThreshold > 0 {
+		keys[emailLockoutKey(email)] = au.cfg.LoginLockoutThreshold
+	}
+
+	if ip := clientip.FromContext(ctx); ip != "" && au.cfg.IPLockoutThreshold > 0 {
+		keys["ip:"+ip] = au.cfg.IPLockoutThreshold
+	}
+
+	return keys
+}
+
+// checkLockout returns a *LockoutError if email or the client IP is
+// locked out. Lockout is a safeguard on top of the password check, so it
+// fails open when its store is unavailable.
+func (au *AuthService) checkLockout(ctx context.Context, log *slog.Logger, email string) error {
+	keys := au.lockoutKeys(ctx, email)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(keys))
+	for key := range keys {
+		names = append(names, key)
+	}
+
+	lockedFor, err := au.loginAttemptRepo.LockedFor(ctx, names...)
+	if err != nil {
+		log.Error("failed to check login lockout", sl.Err(err))
+		return nil
+	}
+
+	if lockedFor > 0 {
+		return &LockoutError{RetryAfter: lockedFor}
+	}
+
+	return nil
+}
+
+// registerLoginFailure counts a failed login of email against the email
+// and the client IP, locking out whichever reached its threshold. Unknown
+// emails are counted too, so lockouts do not reveal which accounts exist.
+func (au *AuthService) registerLoginFailure(
+	ctx    context.Context,
+	log    *slog.Logger,
+	email  string,
+	userID *uuid.UUID,
+) {
+	for key, threshold := range au.lockoutKeys(ctx, email) {
+		failures, err := au.loginAttemptRepo.RegisterFailure(ctx, key, au.cfg.LoginLockoutWindow)
+		if err != nil {
+			log.Error("failed to count login failure", sl.Err(err))
+			continue
+		}
+
+		if failures < threshold {
+			continue
+		}
+
+		duration := lockoutDuration(failures-threshold, au.cfg.LoginLockoutDuration, au.cfg.MaxLoginLockoutDuration)
+		if err := au.loginAttemptRepo.Lock(ctx, key, duration); err != nil {
+			log.Error("failed to lock out login", sl.Err(err))
+			continue
+		}
+
+		log.Warn("login locked out",
+			slog.String("key", key),
+			slog.Int("failures", failures),
+			slog.Duration("duration", duration),
+		)
+
+		if key != emailLockoutKey(email) {
+			continue
+		}
+
+		subject := uuid.Nil
+		if userID != nil {
+			subject = *userID
+		}
+		au.saveEvent(ctx, log, eventModel.UserLockedV1, subject, eventModel.UserLocked{
+			UserID:      userID,
+			Email:       email,
+			Failures:    failures,
+			LockedUntil: time.Now().Add(duration),
+		})
+	}
+}
+
+// resetLoginFailures forgets the failed logins of email after a
+// successful one. The client IP keeps its count, otherwise an attacker
+// could reset it by logging into an account of their own.
+func (au *AuthService) resetLoginFailures(ctx context.Context, log *slog.Logger, email string) {
+	if au.cfg.LoginLockoutThreshold <= 0 {
+		return
+	}
+
+	if err := au.loginAttemptRepo.Reset(ctx, emailLockoutKey(email)); err != nil {
+		log.Error("failed to reset login failures", sl.Err(err))
+	}
+}
+
+func emailLockoutKey(email string) string {
+	return "email:" + userModel.NormalizeEmail(email)
+}
+
+// lockoutDuration doubles base for every failure beyond the threshold,
+// up to limit.
+func lockoutDuration(extraFailures int, base, limit time.Duration) time.Duration {
+	duration := base
+	for range extraFailures {
+		if duration >= limit/2 {
+			return limit
+		}
+		duration *= 2
+	}
+
+	return min(duration, limit)
+}
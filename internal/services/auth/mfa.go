@@ -0,0 +1,495 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/mfaModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/lib/ratelimiter"
+	"github.com/Tbits007/auth/internal/lib/totp"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+// totpSkew is the number of time steps a code may be early or late.
+const totpSkew = 1
+
+// recoveryCodeAlphabet leaves out characters that are easily confused.
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// EnrollTOTP starts a TOTP enrollment and returns the secret to add to an
+// authenticator app. It takes effect once confirmed with ConfirmTOTP;
+// until then enrolling again replaces the secret. The caller
+// authenticates with an access token or, when the account must enroll
+// before it may log in, with the challenge its login returned.
+func (au *AuthService) EnrollTOTP(
+	ctx         context.Context,
+	accessToken string,
+	challengeID string,
+) (mfaModel.Enrollment, error) {
+	const op = "AuthService.EnrollTOTP"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	user, _, err := au.mfaUser(ctx, log, accessToken, challengeID)
+	if err != nil {
+		return mfaModel.Enrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("user_id", user.ID.String()))
+
+	if user.MFAEnabled {
+		return mfaModel.Enrollment{}, fmt.Errorf("%s: %w", op, ErrMFAAlreadyEnabled)
+	}
+
+	secret, err := totp.NewSecret()
+	if err != nil {
+		log.Error("failed to generate totp secret", sl.Err(err))
+		return mfaModel.Enrollment{}, fmt.Errorf("%s: generate secret: %w", op, err)
+	}
+
+	sealed, err := au.secretBox.Seal([]byte(secret), user.ID[:])
+	if err != nil {
+		log.Error("failed to encrypt totp secret", sl.Err(err))
+		return mfaModel.Enrollment{}, fmt.Errorf("%s: encrypt secret: %w", op, err)
+	}
+
+	if err := au.mfaRepo.SaveTOTP(ctx, user.ID, sealed); err != nil {
+		if errors.Is(err, storage.ErrMFAExists) {
+			return mfaModel.Enrollment{}, fmt.Errorf("%s: %w", op, ErrMFAAlreadyEnabled)
+		}
+		log.Error("failed to save totp secret", sl.Err(err))
+		return mfaModel.Enrollment{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return mfaModel.Enrollment{
+		Secret: secret,
+		URI:    totp.URI(au.cfg.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmTOTP activates the pending enrollment with a first code from the
+// authenticator app and returns one-time recovery codes, which are only
+// stored hashed. When authenticated with a login challenge, it completes
+// that login and also returns the session tokens.
+func (au *AuthService) ConfirmTOTP(
+	ctx         context.Context,
+	accessToken string,
+	challengeID string,
+	code        string,
+) ([]string, *tokenModel.TokenPair, error) {
+	const op = "AuthService.ConfirmTOTP"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	user, challenge, err := au.mfaUser(ctx, log, accessToken, challengeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("user_id", user.ID.String()))
+
+	if challenge != nil {
+		if err := au.countMFAAttempt(ctx, challenge.ID); err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	stored, err := au.mfaRepo.GetTOTP(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrMFANotFound) {
+			return nil, nil, fmt.Errorf("%s: %w", op, ErrMFANotEnrolled)
+		}
+		log.Error("failed to get totp", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if stored.ConfirmedAt != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, ErrMFAAlreadyEnabled)
+	}
+
+	step, err := au.checkTOTP(stored, code)
+	if err != nil {
+		log.Info("invalid totp code", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	codes, hashes, err := newRecoveryCodes(au.cfg.RecoveryCodeCount)
+	if err != nil {
+		log.Error("failed to generate recovery codes", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: generate recovery codes: %w", op, err)
+	}
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := au.mfaRepo.ConfirmTOTP(ctx, user.ID, step); err != nil {
+			return err
+		}
+
+		if err := au.mfaRepo.ReplaceRecoveryCodes(ctx, user.ID, hashes); err != nil {
+			return err
+		}
+
+		event, err := au.newEvent(ctx, eventModel.UserMFAEnabledV1, user.ID, eventModel.UserMFAEnabled{
+			UserID: user.ID,
+			Method: eventModel.MFAMethodTOTP,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = au.eventRepo.Save(ctx, event)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrMFANotFound) {
+			return nil, nil, fmt.Errorf("%s: %w", op, ErrMFAAlreadyEnabled)
+		}
+		log.Error("transaction failed", sl.Err(err))
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if challenge == nil {
+		return codes, nil, nil
+	}
+
+	if _, err := au.takeMFAChallenge(ctx, challenge.ID); err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user.MFAEnabled = true
+	tokens, err := au.completeLogin(ctx, log, user)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return codes, &tokens, nil
+}
+
+// VerifyMFA completes a login challenge with a TOTP code or an unused
+// recovery code and issues the session tokens.
+func (au *AuthService) VerifyMFA(
+	ctx         context.Context,
+	challengeID string,
+	code        string,
+) (tokenModel.TokenPair, error) {
+	const op = "AuthService.VerifyMFA"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	challenge, err := au.getMFAChallenge(ctx, challengeID)
+	if err != nil {
+		log.Info("invalid mfa challenge", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("user_id", challenge.UserID.String()))
+
+	if challenge.EnrollmentRequired {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrMFAEnrollmentRequired)
+	}
+
+	if err := au.countMFAAttempt(ctx, challenge.ID); err != nil {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := au.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidMFAChallenge)
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := statusError(user.Status); err != nil {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.verifySecondFactor(ctx, user.ID, code); err != nil {
+		log.Info("invalid mfa code", sl.Err(err))
+		au.saveEvent(ctx, log, eventModel.UserLoginFailedV1, user.ID, eventModel.UserLoginFailed{
+			UserID: &user.ID,
+			Email:  user.Email,
+			Reason: eventModel.LoginFailedInvalidMFACode,
+		})
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if _, err := au.takeMFAChallenge(ctx, challenge.ID); err != nil {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := au.completeLogin(ctx, log, user)
+	if err != nil {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// mfaUser resolves the caller of an MFA enrollment from an access token
+// or, failing that, from an enrollment challenge, which is returned too.
+func (au *AuthService) mfaUser(
+	ctx         context.Context,
+	log         *slog.Logger,
+	accessToken string,
+	challengeID string,
+) (*userModel.User, *mfaModel.Challenge, error) {
+	if accessToken != "" {
+		claims, err := au.ValidateToken(ctx, accessToken)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		user, err := au.userRepo.GetByID(ctx, claims.UserID)
+		if err != nil {
+			if errors.Is(err, storage.ErrUserNotFound) {
+				return nil, nil, ErrInvalidToken
+			}
+			log.Error("failed to get user", sl.Err(err))
+			return nil, nil, err
+		}
+
+		return user, nil, nil
+	}
+
+	challenge, err := au.getMFAChallenge(ctx, challengeID)
+	if err != nil {
+		log.Info("invalid mfa challenge", sl.Err(err))
+		return nil, nil, err
+	}
+
+	if !challenge.EnrollmentRequired {
+		return nil, nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := au.userRepo.GetByID(ctx, challenge.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, nil, ErrInvalidMFAChallenge
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return nil, nil, err
+	}
+
+	if err := statusError(user.Status); err != nil {
+		return nil, nil, err
+	}
+
+	return user, &challenge, nil
+}
+
+// verifySecondFactor accepts a current TOTP code, once, or an unused
+// recovery code of the user.
+func (au *AuthService) verifySecondFactor(
+	ctx    context.Context,
+	userID uuid.UUID,
+	code   string,
+) error {
+	code = strings.TrimSpace(code)
+
+	if len(code) != totp.Digits {
+		if err := au.mfaRepo.UseRecoveryCode(ctx, userID, hashRecoveryCode(code)); err != nil {
+			if errors.Is(err, storage.ErrCodeNotFound) {
+				return ErrInvalidMFACode
+			}
+			return err
+		}
+		return nil
+	}
+
+	stored, err := au.mfaRepo.GetTOTP(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrMFANotFound) {
+			return ErrMFANotEnrolled
+		}
+		return err
+	}
+
+	if stored.ConfirmedAt == nil {
+		return ErrMFANotEnrolled
+	}
+
+	step, err := au.checkTOTP(stored, code)
+	if err != nil {
+		return err
+	}
+
+	if err := au.mfaRepo.UseTOTPStep(ctx, userID, step); err != nil {
+		if errors.Is(err, storage.ErrCodeNotFound) {
+			return ErrInvalidMFACode
+		}
+		return err
+	}
+
+	return nil
+}
+
+// checkTOTP returns the time step code is valid for. Codes of the last
+// accepted step or earlier are rejected as replays.
+func (au *AuthService) checkTOTP(stored *mfaModel.TOTP, code string) (int64, error) {
+	secret, err := au.secretBox.Open(stored.EncryptedSecret, stored.UserID[:])
+	if err != nil {
+		return 0, fmt.Errorf("decrypt totp secret: %w", err)
+	}
+
+	step, ok := totp.Validate(string(secret), strings.TrimSpace(code), time.Now(), totpSkew)
+	if !ok || step <= stored.LastUsedStep {
+		return 0, ErrInvalidMFACode
+	}
+
+	return step, nil
+}
+
+func (au *AuthService) newMFAChallenge(
+	ctx                context.Context,
+	userID             uuid.UUID,
+	enrollmentRequired bool,
+) (mfaModel.Challenge, error) {
+	id, err := opaque.NewToken(opaque.DefaultSize)
+	if err != nil {
+		return mfaModel.Challenge{}, fmt.Errorf("generate mfa challenge: %w", err)
+	}
+
+	challenge := mfaModel.Challenge{
+		ID:                 id,
+		UserID:             userID,
+		EnrollmentRequired: enrollmentRequired,
+		ExpiresAt:          time.Now().Add(au.cfg.MFAChallengeTTL),
+	}
+
+	value, err := json.Marshal(challenge)
+	if err != nil {
+		return mfaModel.Challenge{}, fmt.Errorf("encode mfa challenge: %w", err)
+	}
+
+	if err := au.cacheRepo.SetMFAChallenge(ctx, id, value, au.cfg.MFAChallengeTTL); err != nil {
+		return mfaModel.Challenge{}, err
+	}
+
+	return challenge, nil
+}
+
+func (au *AuthService) getMFAChallenge(ctx context.Context, id string) (mfaModel.Challenge, error) {
+	if id == "" {
+		return mfaModel.Challenge{}, ErrInvalidMFAChallenge
+	}
+
+	value, err := au.cacheRepo.GetMFAChallenge(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return mfaModel.Challenge{}, ErrInvalidMFAChallenge
+		}
+		return mfaModel.Challenge{}, err
+	}
+
+	return decodeMFAChallenge(id, value)
+}
+
+// takeMFAChallenge consumes the challenge, so that it completes at most
+// one login even when several codes are checked concurrently.
+func (au *AuthService) takeMFAChallenge(ctx context.Context, id string) (mfaModel.Challenge, error) {
+	value, err := au.cacheRepo.TakeMFAChallenge(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return mfaModel.Challenge{}, ErrInvalidMFAChallenge
+		}
+		return mfaModel.Challenge{}, err
+	}
+
+	return decodeMFAChallenge(id, value)
+}
+
+// countMFAAttempt limits the codes tried against a challenge. Once they
+// run out the challenge is dropped and the login has to start over.
+func (au *AuthService) countMFAAttempt(ctx context.Context, id string) error {
+	err := au.rateLimiter.AllowMFAAttempt(ctx, id, au.cfg.MFAMaxAttempts, au.cfg.MFAChallengeTTL)
+	if err == nil {
+		return nil
+	}
+
+	if errors.Is(err, ratelimiter.ErrRateLimited) {
+		_ = au.cacheRepo.DeleteMFAChallenge(ctx, id)
+		return ErrTooManyAttempts
+	}
+
+	return err
+}
+
+func decodeMFAChallenge(id string, value []byte) (mfaModel.Challenge, error) {
+	var challenge mfaModel.Challenge
+	if err := json.Unmarshal(value, &challenge); err != nil {
+		return mfaModel.Challenge{}, fmt.Errorf("decode mfa challenge: %w", err)
+	}
+	challenge.ID = id
+
+	return challenge, nil
+}
+
+// newRecoveryCodes returns n recovery codes formatted as "xxxxx-xxxxx",
+// along with their hashes.
+func newRecoveryCodes(n int) ([]string, []string, error) {
+	codes := make([]string, 0, n)
+	hashes := make([]string, 0, n)
+
+	for range n {
+		b, err := randomChars(recoveryCodeAlphabet, 10)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		code := string(b[:5]) + "-" + string(b[5:])
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+
+	return codes, hashes, nil
+}
+
+// randomChars returns n characters drawn uniformly from alphabet. Bytes
+// past the largest multiple of len(alphabet) are rejected, as mapping
+// them with a modulo would favor the first characters.
+func randomChars(alphabet string, n int) ([]byte, error) {
+	limit := 256 - 256%len(alphabet)
+	result := make([]byte, 0, n)
+	buf := make([]byte, n)
+
+	for len(result) < n {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+
+		for _, c := range buf {
+			if int(c) < limit && len(result) < n {
+				result = append(result, alphabet[int(c)%len(alphabet)])
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// hashRecoveryCode ignores case, dashes and spaces, which users may type
+// differently.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	code = strings.NewReplacer("-", "", " ", "").Replace(code)
+
+	return opaque.Hash(code)
+}
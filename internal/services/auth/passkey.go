@@ -0,0 +1,304 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/lib/passkey"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+// BeginPasskeyRegistration starts registering a passkey for the caller
+// and returns the WebAuthn creation options for the browser. A new
+// registration replaces one the caller has not finished.
+func (au *AuthService) BeginPasskeyRegistration(
+	ctx         context.Context,
+	accessToken string,
+) ([]byte, error) {
+	const op = "AuthService.BeginPasskeyRegistration"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := au.passkeyOwner(ctx, log, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	options, session, err := au.relyingParty.BeginRegistration(user)
+	if err != nil {
+		log.Error("failed to begin passkey registration", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.cacheRepo.SetPasskeyRegistration(ctx, user.ID, session, au.cfg.PasskeyChallengeTTL); err != nil {
+		log.Error("failed to store passkey registration", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return options, nil
+}
+
+// FinishPasskeyRegistration verifies the authenticator's attestation
+// response and stores the new passkey.
+func (au *AuthService) FinishPasskeyRegistration(
+	ctx         context.Context,
+	accessToken string,
+	response    []byte,
+) (passkeyModel.Credential, error) {
+	const op = "AuthService.FinishPasskeyRegistration"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	user, err := au.passkeyOwner(ctx, log, accessToken)
+	if err != nil {
+		return passkeyModel.Credential{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("user_id", user.ID.String()))
+
+	session, err := au.cacheRepo.TakePasskeyRegistration(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return passkeyModel.Credential{}, fmt.Errorf("%s: %w", op, ErrInvalidPasskeyChallenge)
+		}
+		log.Error("failed to get passkey registration", sl.Err(err))
+		return passkeyModel.Credential{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	credential, err := au.relyingParty.FinishRegistration(user, session, response)
+	if err != nil {
+		if errors.Is(err, passkey.ErrInvalidResponse) {
+			log.Info("invalid passkey registration", sl.Err(err))
+			return passkeyModel.Credential{}, fmt.Errorf("%s: %w", op, ErrInvalidPasskey)
+		}
+		log.Error("failed to finish passkey registration", sl.Err(err))
+		return passkeyModel.Credential{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		id, err := au.passkeyRepo.Save(ctx, credential)
+		if err != nil {
+			return err
+		}
+		credential.ID = id
+
+		event, err := au.newEvent(ctx, eventModel.UserPasskeyRegisteredV1, user.ID, eventModel.UserPasskeyRegistered{
+			UserID:    user.ID,
+			PasskeyID: id,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = au.eventRepo.Save(ctx, event)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrPasskeyExists) {
+			return passkeyModel.Credential{}, fmt.Errorf("%s: %w", op, ErrPasskeyExists)
+		}
+		log.Error("transaction failed", sl.Err(err))
+		return passkeyModel.Credential{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("passkey registered", slog.String("passkey_id", credential.ID.String()))
+
+	return credential, nil
+}
+
+// BeginPasskeyLogin starts a passwordless login and returns the ID of
+// the challenge along with the WebAuthn request options. Any passkey of
+// any user may answer it.
+func (au *AuthService) BeginPasskeyLogin(
+	ctx context.Context,
+) (string, []byte, error) {
+	const op = "AuthService.BeginPasskeyLogin"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	challengeID, err := opaque.NewToken(opaque.DefaultSize)
+	if err != nil {
+		log.Error("failed to generate passkey challenge", sl.Err(err))
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	options, session, err := au.relyingParty.BeginLogin()
+	if err != nil {
+		log.Error("failed to begin passkey login", sl.Err(err))
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.cacheRepo.SetPasskeyLogin(ctx, challengeID, session, au.cfg.PasskeyChallengeTTL); err != nil {
+		log.Error("failed to store passkey challenge", sl.Err(err))
+		return "", nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return challengeID, options, nil
+}
+
+// FinishPasskeyLogin verifies the assertion response to a challenge and
+// issues the same tokens as Login. Each challenge can be answered once.
+// The relying party rejects assertions without user verification, so a
+// passkey is a factor of its own plus a PIN or biometric, and no further
+// MFA challenge is issued, for admins neither.
+func (au *AuthService) FinishPasskeyLogin(
+	ctx         context.Context,
+	challengeID string,
+	response    []byte,
+) (tokenModel.TokenPair, error) {
+	const op = "AuthService.FinishPasskeyLogin"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	session, err := au.cacheRepo.TakePasskeyLogin(ctx, challengeID)
+	if err != nil {
+		if errors.Is(err, storage.ErrKeyNotFound) {
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidPasskeyChallenge)
+		}
+		log.Error("failed to get passkey challenge", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var (
+		user      *userModel.User
+		lookupErr error
+	)
+	lookup := func(credentialID []byte, userID uuid.UUID) (passkey.User, error) {
+		var credential *passkeyModel.Credential
+		user, credential, lookupErr = au.passkeyUser(ctx, credentialID, userID)
+		if lookupErr != nil {
+			return passkey.User{}, lookupErr
+		}
+
+		return passkey.User{
+			ID:          user.ID,
+			Name:        user.Email,
+			Credentials: []passkeyModel.Credential{*credential},
+		}, nil
+	}
+
+	credential, err := au.relyingParty.FinishLogin(session, response, lookup)
+	if err != nil {
+		if lookupErr != nil && !errors.Is(lookupErr, ErrInvalidPasskey) {
+			log.Error("failed to look up passkey", sl.Err(lookupErr))
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, lookupErr)
+		}
+		if !errors.Is(err, passkey.ErrInvalidResponse) && !errors.Is(err, passkey.ErrCloned) {
+			log.Error("failed to finish passkey login", sl.Err(err))
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+		}
+
+		log.Info("invalid passkey login", sl.Err(err))
+		if user != nil {
+			au.saveEvent(ctx, log, eventModel.UserLoginFailedV1, user.ID, eventModel.UserLoginFailed{
+				UserID: &user.ID,
+				Email:  user.Email,
+				Reason: eventModel.LoginFailedInvalidPasskey,
+			})
+		}
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidPasskey)
+	}
+
+	log = log.With(slog.String("user_id", user.ID.String()))
+
+	if err := statusError(user.Status); err != nil {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.passkeyRepo.UpdateSignCount(ctx, credential.CredentialID, credential.SignCount); err != nil {
+		if errors.Is(err, storage.ErrPasskeyNotFound) {
+			log.Warn("passkey sign count conflict", slog.String("passkey_id", credential.ID.String()))
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidPasskey)
+		}
+		log.Error("failed to update passkey sign count", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	tokens, err := au.completeLogin(ctx, log, user)
+	if err != nil {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokens, nil
+}
+
+// passkeyOwner returns the caller of a passkey registration along with
+// the passkeys already registered, which the authenticator must not
+// register again.
+func (au *AuthService) passkeyOwner(
+	ctx         context.Context,
+	log         *slog.Logger,
+	accessToken string,
+) (passkey.User, error) {
+	claims, err := au.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return passkey.User{}, err
+	}
+
+	user, err := au.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return passkey.User{}, ErrInvalidToken
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return passkey.User{}, err
+	}
+
+	credentials, err := au.passkeyRepo.ListByUser(ctx, user.ID)
+	if err != nil {
+		log.Error("failed to list passkeys", sl.Err(err))
+		return passkey.User{}, err
+	}
+
+	return passkey.User{
+		ID:          user.ID,
+		Name:        user.Email,
+		Credentials: credentials,
+	}, nil
+}
+
+// passkeyUser returns a stored credential and its owner, provided the
+// owner is the user the authenticator named in its response.
+func (au *AuthService) passkeyUser(
+	ctx          context.Context,
+	credentialID []byte,
+	userID       uuid.UUID,
+) (*userModel.User, *passkeyModel.Credential, error) {
+	credential, err := au.passkeyRepo.GetByCredentialID(ctx, credentialID)
+	if err != nil {
+		if errors.Is(err, storage.ErrPasskeyNotFound) {
+			return nil, nil, ErrInvalidPasskey
+		}
+		return nil, nil, err
+	}
+
+	if credential.UserID != userID {
+		return nil, nil, ErrInvalidPasskey
+	}
+
+	user, err := au.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, nil, ErrInvalidPasskey
+		}
+		return nil, nil, err
+	}
+
+	return user, credential, nil
+}
@@ -0,0 +1,387 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+// ChangePassword replaces the password of the user behind accessToken
+// after checking oldPassword. With revokeOtherSessions all refresh and
+// access tokens of the user, accessToken included, are revoked and the
+// caller gets a fresh token pair to continue with; otherwise the returned
+// pair is empty.
+func (au *AuthService) ChangePassword(
+	ctx                 context.Context,
+	accessToken         string,
+	oldPassword         string,
+	newPassword         string,
+	revokeOtherSessions bool,
+) (tokenModel.TokenPair, error) {
+	const op = "AuthService.ChangePassword"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	claims, err := au.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log = log.With(slog.String("user_id", claims.UserID.String()))
+
+	user, err := au.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("token subject not found")
+			return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Guessing the old password with a stolen access token counts against
+	// the same lockout as guessing it at login.
+	if err := au.checkLockout(ctx, log, user.Email); err != nil {
+		log.Info("password change locked out", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.verifyPassword(user, oldPassword); err != nil {
+		log.Info("invalid credentials", sl.Err(err))
+		au.registerLoginFailure(ctx, log, user.Email, &user.ID)
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, ErrInvalidCredentials)
+	}
+
+	au.resetLoginFailures(ctx, log, user.Email)
+
+	if err := au.checkPassword(newPassword); err != nil {
+		log.Info("new password rejected", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := au.passwordHasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: generate password hash: %w", op, err)
+	}
+
+	var (
+		sessionID    uuid.UUID
+		refreshToken string
+	)
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := au.userRepo.UpdatePassword(ctx, user.ID, passHash); err != nil {
+			return err
+		}
+
+		event, err := au.newEvent(ctx, eventModel.UserPasswordChangedV1, user.ID, eventModel.UserPasswordChanged{
+			UserID:          user.ID,
+			SessionsRevoked: revokeOtherSessions,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := au.eventRepo.Save(ctx, event); err != nil {
+			return err
+		}
+
+		if !revokeOtherSessions {
+			return nil
+		}
+		if err := au.endAllSessions(ctx, user.ID); err != nil {
+			return err
+		}
+
+		// The caller continues with a new session, which is exempt from
+		// the user-wide revocation even though its tokens are issued in
+		// the same second.
+		sessionID, refreshToken, err = au.startSession(ctx, user)
+		if err != nil {
+			return err
+		}
+
+		if err := au.cacheRepo.RevokeUserTokens(ctx, user.ID, time.Now(), sessionID, au.cfg.TokenTTL); err != nil {
+			return err
+		}
+		return au.revokeClaims(ctx, log, claims)
+	})
+	if err != nil {
+		log.Error("transaction failed", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password changed", slog.Bool("sessions_revoked", revokeOtherSessions))
+
+	if !revokeOtherSessions {
+		return tokenModel.TokenPair{}, nil
+	}
+
+	token, err := au.tokenIssuer.NewSessionToken(ctx, *user, sessionID, au.cfg.TokenTTL)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+		return tokenModel.TokenPair{}, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tokenModel.TokenPair{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// RequestPasswordReset mails a password reset token to the account
+// registered under email and invalidates the previous ones. To avoid
+// revealing which emails are registered it succeeds silently for unknown,
+// disabled and deleted accounts.
+func (au *AuthService) RequestPasswordReset(
+	ctx   context.Context,
+	email string,
+) error {
+	const op = "AuthService.RequestPasswordReset"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	email = userModel.NormalizeEmail(email)
+
+	user, err := au.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+			return nil
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !canResetPassword(user.Status) {
+		log.Info("password reset not allowed", slog.String("status", string(user.Status)))
+		return nil
+	}
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := au.userTokenRepo.InvalidateAll(ctx, user.ID, tokenModel.PurposePasswordReset); err != nil {
+			return err
+		}
+
+		token, expiresAt, err := au.issueUserToken(ctx, user.ID, tokenModel.PurposePasswordReset, au.cfg.PasswordResetTokenTTL)
+		if err != nil {
+			return err
+		}
+
+		sealed, err := au.sealUserToken(user.ID, token)
+		if err != nil {
+			return err
+		}
+
+		event, err := au.newEvent(ctx, eventModel.UserPasswordResetRequestedV1, user.ID, eventModel.UserPasswordResetRequested{
+			UserID:         user.ID,
+			Email:          user.Email,
+			EncryptedToken: sealed,
+			ExpiresAt:      expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = au.eventRepo.Save(ctx, event)
+		return err
+	})
+	if err != nil {
+		log.Error("transaction failed", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset requested", slog.String("user_id", user.ID.String()))
+
+	return nil
+}
+
+// ResetPassword consumes a password reset token, sets newPassword if it
+// meets the password policy and revokes all of the user's refresh and
+// access tokens.
+func (au *AuthService) ResetPassword(
+	ctx         context.Context,
+	token       string,
+	newPassword string,
+) error {
+	const op = "AuthService.ResetPassword"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	if err := au.checkPassword(newPassword); err != nil {
+		log.Info("new password rejected", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	passHash, err := au.passwordHasher.Hash(newPassword)
+	if err != nil {
+		log.Error("failed to generate password hash", sl.Err(err))
+		return fmt.Errorf("%s: generate password hash: %w", op, err)
+	}
+
+	var user *userModel.User
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		stored, err := au.userTokenRepo.Consume(ctx, tokenModel.PurposePasswordReset, opaque.Hash(token))
+		if err != nil {
+			return err
+		}
+
+		user, err = au.userRepo.GetByID(ctx, stored.UserID)
+		if err != nil {
+			return err
+		}
+
+		if !canResetPassword(user.Status) {
+			return statusError(user.Status)
+		}
+
+		if err := au.userRepo.UpdatePassword(ctx, user.ID, passHash); err != nil {
+			return err
+		}
+
+		if err := au.userTokenRepo.InvalidateAll(ctx, user.ID, tokenModel.PurposePasswordReset); err != nil {
+			return err
+		}
+
+		event, err := au.newEvent(ctx, eventModel.UserPasswordResetV1, user.ID, eventModel.UserPasswordReset{
+			UserID: user.ID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := au.eventRepo.Save(ctx, event); err != nil {
+			return err
+		}
+
+		// Revoking last means a Redis failure rolls the reset back, so the
+		// token can be used again; see revokeAllTokens.
+		return au.revokeAllTokens(ctx, user.ID)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrTokenNotFound):
+			log.Info("password reset token not found")
+			return fmt.Errorf("%s: %w", op, ErrInvalidResetToken)
+		case isAccountError(err):
+			log.Info("password reset not allowed", sl.Err(err))
+			return fmt.Errorf("%s: %w", op, err)
+		}
+		log.Error("transaction failed", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.Info("password reset", slog.String("user_id", user.ID.String()))
+
+	return nil
+}
+
+// revokeAllTokens ends every session of userID, revoking its refresh
+// tokens and every access token issued to it so far.
+//
+// It must run inside a transaction, as the last write before the commit.
+// The Redis revocation cannot be rolled back, but it fails safe: a Redis
+// failure rolls the whole change back so that the caller can retry, and
+// a failed commit after it only ends the user's access tokens early.
+// Revoking after the commit instead would leave access tokens valid for
+// up to TokenTTL whenever Redis is unavailable.
+func (au *AuthService) revokeAllTokens(
+	ctx    context.Context,
+	userID uuid.UUID,
+) error {
+	if err := au.endAllSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	return au.cacheRepo.RevokeUserTokens(ctx, userID, time.Now(), uuid.Nil, au.cfg.TokenTTL)
+}
+
+// endAllSessions ends every session of userID in the database and revokes
+// their refresh tokens. The access tokens stay valid until the caller
+// revokes them in the cache.
+func (au *AuthService) endAllSessions(
+	ctx    context.Context,
+	userID uuid.UUID,
+) error {
+	if err := au.sessionRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return err
+	}
+
+	return au.refreshTokenRepo.RevokeAllForUser(ctx, userID)
+}
+
+// checkPassword applies the password policy. Violations are reported as
+// ErrInvalidPassword wrapping the policy's own error.
+func (au *AuthService) checkPassword(password string) error {
+	if err := au.passwordPolicy.Validate(password); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPassword, err)
+	}
+
+	return nil
+}
+
+// verifyPassword checks password against the stored hash of user. A
+// mismatch is reported as ErrInvalidCredentials.
+func (au *AuthService) verifyPassword(user *userModel.User, password string) error {
+	ok, err := au.passwordHasher.Verify(user.HashedPassword, password)
+	if err != nil {
+		return fmt.Errorf("verify password hash: %w", err)
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// rehashPassword upgrades the stored hash of user after a successful
+// login when it uses a legacy algorithm or weaker parameters. Failures
+// only cost the upgrade, so they are logged and the login proceeds.
+func (au *AuthService) rehashPassword(
+	ctx      context.Context,
+	log      *slog.Logger,
+	user     *userModel.User,
+	password string,
+) {
+	if !au.passwordHasher.NeedsRehash(user.HashedPassword) {
+		return
+	}
+
+	passHash, err := au.passwordHasher.Hash(password)
+	if err != nil {
+		log.Warn("failed to rehash password", sl.Err(err))
+		return
+	}
+
+	if err := au.userRepo.UpdatePassword(ctx, user.ID, passHash); err != nil {
+		log.Warn("failed to store rehashed password", sl.Err(err))
+		return
+	}
+
+	user.HashedPassword = passHash
+	log.Info("password rehashed", slog.String("user_id", user.ID.String()))
+}
+
+// canResetPassword reports whether an account in status may recover its
+// password. Disabled and deleted accounts stay closed.
+func canResetPassword(status userModel.Status) bool {
+	return status != userModel.StatusDisabled && status != userModel.StatusDeleted
+}
@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+func (au *AuthService) HasPermission(
+	ctx        context.Context,
+	userID     uuid.UUID,
+	permission string,
+) (bool, error) {
+	const op = "AuthService.HasPermission"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	allowed, err := au.userRepo.HasPermission(ctx, userID, permission)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found", sl.Err(err))
+			return false, fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		log.Error("failed to check permission", sl.Err(err))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return allowed, nil
+}
+
+// Authorize validates accessToken and checks that its subject holds
+// permission.
+func (au *AuthService) Authorize(
+	ctx         context.Context,
+	accessToken string,
+	permission  string,
+) (*jwt.Claims, error) {
+	const op = "AuthService.Authorize"
+
+	claims, err := au.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	allowed, err := au.HasPermission(ctx, claims.UserID, permission)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidToken)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !allowed {
+		au.log.Info("permission denied",
+			slog.String("op", op),
+			slog.String("user_id", claims.UserID.String()),
+			slog.String("permission", permission),
+		)
+		return nil, fmt.Errorf("%s: %w", op, ErrPermissionDenied)
+	}
+
+	return claims, nil
+}
+
+func (au *AuthService) AssignRole(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+	role        string,
+) error {
+	const op = "AuthService.AssignRole"
+
+	return au.changeRole(ctx, op, accessToken, userID, role, eventModel.UserRoleAssignedV1, au.userRepo.AssignRole)
+}
+
+func (au *AuthService) RevokeRole(
+	ctx         context.Context,
+	accessToken string,
+	userID      uuid.UUID,
+	role        string,
+) error {
+	const op = "AuthService.RevokeRole"
+
+	return au.changeRole(ctx, op, accessToken, userID, role, eventModel.UserRoleRevokedV1, au.userRepo.RevokeRole)
+}
+
+func (au *AuthService) changeRole(
+	ctx         context.Context,
+	op          string,
+	accessToken string,
+	userID      uuid.UUID,
+	role        string,
+	eventType   string,
+	apply       func(ctx context.Context, userID uuid.UUID, role string) error,
+) error {
+	log := au.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+		slog.String("role", role),
+	)
+
+	actor, err := au.Authorize(ctx, accessToken, roleModel.PermRolesAssign)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := apply(ctx, userID, role); err != nil {
+			return err
+		}
+
+		event, err := au.newEvent(ctx, eventType, userID, eventModel.UserRoleChanged{
+			UserID:  userID,
+			Role:    role,
+			ActorID: actor.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		_, err = au.eventRepo.Save(ctx, event)
+		return err
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrRoleNotFound):
+			log.Info("unknown role")
+			return fmt.Errorf("%s: %w", op, ErrUnknownRole)
+		case errors.Is(err, storage.ErrUserNotFound):
+			log.Info("user not found")
+			return fmt.Errorf("%s: %w", op, ErrUserNotFound)
+		}
+		log.Error("transaction failed", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	au.evictUser(ctx, log, userID)
+
+	log.Info("role changed", slog.String("actor_id", actor.UserID.String()))
+
+	return nil
+}
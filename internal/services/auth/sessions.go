@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/clientip"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/useragent"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+// ListSessions returns the active sessions of the user behind accessToken,
+// most recently seen first, along with the ID of the session accessToken
+// belongs to.
+func (au *AuthService) ListSessions(
+	ctx         context.Context,
+	accessToken string,
+) ([]sessionModel.Session, uuid.UUID, error) {
+	const op = "AuthService.ListSessions"
+
+	claims, err := au.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	sessions, err := au.ListUserSessions(ctx, claims.UserID)
+	if err != nil {
+		return nil, uuid.Nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, claims.SessionID, nil
+}
+
+// RevokeSession ends one session of the user behind accessToken, which may
+// be the caller's own.
+func (au *AuthService) RevokeSession(
+	ctx         context.Context,
+	accessToken string,
+	sessionID   uuid.UUID,
+) error {
+	const op = "AuthService.RevokeSession"
+
+	claims, err := au.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.revokeSession(ctx, claims.UserID, sessionID, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeAllSessions logs the user behind accessToken out everywhere,
+// including the caller's own session.
+func (au *AuthService) RevokeAllSessions(
+	ctx         context.Context,
+	accessToken string,
+) error {
+	const op = "AuthService.RevokeAllSessions"
+
+	claims, err := au.ValidateToken(ctx, accessToken)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := au.revokeSessions(ctx, claims.UserID, nil); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// ListUserSessions returns the active sessions of userID. Callers check
+// that they may see them.
+func (au *AuthService) ListUserSessions(
+	ctx    context.Context,
+	userID uuid.UUID,
+) ([]sessionModel.Session, error) {
+	const op = "AuthService.ListUserSessions"
+
+	log := au.log.With(
+		slog.String("op", op),
+		slog.String("user_id", userID.String()),
+	)
+
+	sessions, err := au.sessionRepo.ListActive(ctx, userID)
+	if err != nil {
+		log.Error("failed to list sessions", sl.Err(err))
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// RevokeUserSession ends the session sessionID of userID on behalf of the
+// admin actorID.
+func (au *AuthService) RevokeUserSession(
+	ctx       context.Context,
+	actorID   uuid.UUID,
+	userID    uuid.UUID,
+	sessionID uuid.UUID,
+) error {
+	const op = "AuthService.RevokeUserSession"
+
+	if err := au.revokeSession(ctx, userID, sessionID, &actorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeUserSessions ends every session of userID on behalf of the admin
+// actorID.
+func (au *AuthService) RevokeUserSessions(
+	ctx     context.Context,
+	actorID uuid.UUID,
+	userID  uuid.UUID,
+) error {
+	const op = "AuthService.RevokeUserSessions"
+
+	if err := au.revokeSessions(ctx, userID, &actorID); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (au *AuthService) revokeSession(
+	ctx       context.Context,
+	userID    uuid.UUID,
+	sessionID uuid.UUID,
+	actorID   *uuid.UUID,
+) error {
+	log := au.log.With(
+		slog.String("user_id", userID.String()),
+		slog.String("session_id", sessionID.String()),
+	)
+
+	err := au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		event, err := au.newEvent(ctx, eventModel.UserSessionsRevokedV1, userID, eventModel.UserSessionsRevoked{
+			UserID:    userID,
+			SessionID: &sessionID,
+			ActorID:   actorID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := au.eventRepo.Save(ctx, event); err != nil {
+			return err
+		}
+
+		return au.endSession(ctx, userID, sessionID)
+	})
+	if err != nil {
+		if errors.Is(err, storage.ErrSessionNotFound) {
+			log.Info("session not found")
+			return ErrSessionNotFound
+		}
+		log.Error("transaction failed", sl.Err(err))
+		return err
+	}
+
+	log.Info("session revoked")
+
+	return nil
+}
+
+func (au *AuthService) revokeSessions(
+	ctx     context.Context,
+	userID  uuid.UUID,
+	actorID *uuid.UUID,
+) error {
+	log := au.log.With(
+		slog.String("user_id", userID.String()),
+	)
+
+	err := au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		event, err := au.newEvent(ctx, eventModel.UserSessionsRevokedV1, userID, eventModel.UserSessionsRevoked{
+			UserID:  userID,
+			ActorID: actorID,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := au.eventRepo.Save(ctx, event); err != nil {
+			return err
+		}
+
+		// Revoking last means a Redis failure rolls the revocation back;
+		// see revokeAllTokens.
+		return au.revokeAllTokens(ctx, userID)
+	})
+	if err != nil {
+		log.Error("transaction failed", sl.Err(err))
+		return err
+	}
+
+	log.Info("all sessions revoked")
+
+	return nil
+}
+
+// endSession revokes the session sessionID of userID, its refresh token
+// family and the access tokens issued for it. Like revokeAllTokens it
+// must run inside a transaction as its last write, so that a Redis
+// failure rolls the revocation back.
+func (au *AuthService) endSession(
+	ctx       context.Context,
+	userID    uuid.UUID,
+	sessionID uuid.UUID,
+) error {
+	session, err := au.sessionRepo.Revoke(ctx, userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if err := au.refreshTokenRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+		return err
+	}
+
+	return au.cacheRepo.RevokeSession(ctx, sessionID, au.cfg.TokenTTL)
+}
+
+// newSession records a session for user on the calling device and issues
+// its token pair.
+func (au *AuthService) newSession(
+	ctx  context.Context,
+	log  *slog.Logger,
+	user *userModel.User,
+) (tokenModel.TokenPair, error) {
+	var (
+		sessionID    uuid.UUID
+		refreshToken string
+	)
+
+	err := au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		sessionID, refreshToken, err = au.startSession(ctx, user)
+		return err
+	})
+	if err != nil {
+		log.Error("failed to start session", sl.Err(err))
+		return tokenModel.TokenPair{}, err
+	}
+
+	token, err := au.tokenIssuer.NewSessionToken(ctx, *user, sessionID, au.cfg.TokenTTL)
+	if err != nil {
+		log.Error("failed to generate token", sl.Err(err))
+		return tokenModel.TokenPair{}, err
+	}
+
+	return tokenModel.TokenPair{
+		AccessToken:  token,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+// startSession records a session for user on the calling device and
+// issues its refresh token. It must run inside a transaction.
+func (au *AuthService) startSession(
+	ctx  context.Context,
+	user *userModel.User,
+) (uuid.UUID, string, error) {
+	familyID := uuid.New()
+
+	sessionID, err := au.sessionRepo.Save(ctx, sessionModel.Session{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		UserAgent: useragent.FromContext(ctx),
+		IP:        clientip.FromContext(ctx),
+		ExpiresAt: time.Now().Add(au.cfg.RefreshTokenTTL),
+	})
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	refreshToken, err := au.issueRefreshToken(ctx, user.ID, familyID)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	return sessionID, refreshToken, nil
+}
@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+// statusError returns the error for an account that may not log in or use
+// its tokens, or nil for an active one.
+func statusError(status userModel.Status) error {
+	switch status {
+	case userModel.StatusActive:
+		return nil
+	case userModel.StatusPendingVerification:
+		return ErrAccountNotVerified
+	case userModel.StatusDeleted:
+		return ErrAccountDeleted
+	default:
+		return ErrAccountDisabled
+	}
+}
+
+func isAccountError(err error) bool {
+	return errors.Is(err, ErrAccountNotVerified) ||
+		errors.Is(err, ErrAccountDisabled) ||
+		errors.Is(err, ErrAccountDeleted)
+}
+
+// checkStatus returns statusError for the user's current status, read
+// through the cache. The status read from the database is only cached if
+// the user was not invalidated in the meantime.
+func (au *AuthService) checkStatus(
+	ctx    context.Context,
+	log    *slog.Logger,
+	userID uuid.UUID,
+) error {
+	status, version, err := au.cacheRepo.GetUserStatus(ctx, userID)
+	if err == nil {
+		log.Debug("cache hit")
+		return statusError(status)
+	} else if !errors.Is(err, storage.ErrKeyNotFound) {
+		log.Debug("cache error", sl.Err(err))
+	}
+
+	user, err := au.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("token subject not found", slog.String("user_id", userID.String()))
+			return ErrInvalidToken
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return err
+	}
+
+	if au.cfg.UserCacheTTL > 0 {
+		if err := au.cacheRepo.SetUserStatus(ctx, userID, user.Status, version, au.cfg.UserCacheTTL); err != nil {
+			log.Warn("failed to cache account status", sl.Err(err))
+		}
+	}
+
+	return statusError(user.Status)
+}
+
+// changeStatus moves the user from one of the given statuses to to and
+// records the transition. It must run inside a transaction; the caller
+// calls evictUser once the transaction commits.
+func (au *AuthService) changeStatus(
+	ctx     context.Context,
+	userID  uuid.UUID,
+	from    []userModel.Status,
+	to      userModel.Status,
+	actorID *uuid.UUID,
+) error {
+	prev, err := au.userRepo.SetStatus(ctx, userID, from, to)
+	if err != nil {
+		return err
+	}
+
+	event, err := au.newEvent(ctx, eventModel.UserStatusChangedV1, userID, eventModel.UserStatusChanged{
+		UserID:  userID,
+		From:    string(prev),
+		To:      string(to),
+		ActorID: actorID,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = au.eventRepo.Save(ctx, event)
+	return err
+}
+
+// evictUser drops the cached status and admin flag of userID. Whatever
+// changes a user calls it once the change is committed.
+func (au *AuthService) evictUser(
+	ctx    context.Context,
+	log    *slog.Logger,
+	userID uuid.UUID,
+) {
+	if err := au.cacheRepo.InvalidateUser(ctx, userID); err != nil {
+		log.Warn("failed to evict cached user", sl.Err(err))
+	}
+}
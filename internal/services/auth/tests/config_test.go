@@ -0,0 +1,151 @@
+package tests
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/lib/passkey"
+	"github.com/Tbits007/auth/internal/lib/password"
+	"github.com/Tbits007/auth/internal/lib/secretbox"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/auth/tests/mocks"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testConfig = auth.Config{
+	TokenTTL:                 time.Hour,
+	RefreshTokenTTL:          24 * time.Hour,
+	VerificationTokenTTL:     24 * time.Hour,
+	PasswordResetTokenTTL:    time.Hour,
+	ResendVerificationLimit:  3,
+	ResendVerificationPeriod: time.Hour,
+	MFAIssuer:                "auth",
+	MFAChallengeTTL:          5 * time.Minute,
+	MFAMaxAttempts:           5,
+	RecoveryCodeCount:        10,
+	PasskeyChallengeTTL:      5 * time.Minute,
+	UserCacheTTL:             5 * time.Minute,
+}
+
+var testPasswordPolicy = mustPolicy(password.NewPolicy(password.Config{
+	MinLength: 8,
+	MaxLength: 64,
+}, nil))
+
+func mustPolicy(policy *password.Policy, err error) *password.Policy {
+	if err != nil {
+		panic(err)
+	}
+
+	return policy
+}
+
+// testPasswordHasher uses the cheapest bcrypt cost so that fixtures hashed
+// at that cost or above never trigger a rehash on login.
+var testPasswordHasher = password.NewHasher(password.Bcrypt{Cost: bcrypt.MinCost})
+
+var testSecretBox = mustSecretBox(secretbox.New(make([]byte, 32)))
+
+var testTokenBox = mustSecretBox(secretbox.New(bytes.Repeat([]byte{1}, 32)))
+
+func mustSecretBox(box *secretbox.Box, err error) *secretbox.Box {
+	if err != nil {
+		panic(err)
+	}
+
+	return box
+}
+
+const testPasskeyOrigin = "https://auth.example.com"
+
+var testRelyingParty = mustRelyingParty(passkey.New(passkey.Config{
+	RPID:          "auth.example.com",
+	RPDisplayName: "Auth",
+	RPOrigins:     []string{testPasskeyOrigin},
+	Timeout:       time.Minute,
+}))
+
+func mustRelyingParty(rp *passkey.RelyingParty, err error) *passkey.RelyingParty {
+	if err != nil {
+		panic(err)
+	}
+
+	return rp
+}
+
+// testMocks are the mocked dependencies of a service built by
+// newTestService. Mocks without expectations fail the test if called.
+type testMocks struct {
+	txManager        *mocks.MockTxManager
+	userRepo         *mocks.MockUserRepo
+	eventRepo        *mocks.MockEventRepo
+	cacheRepo        *mocks.MockCacheRepo
+	refreshTokenRepo *mocks.MockRefreshTokenRepo
+	userTokenRepo    *mocks.MockUserTokenRepo
+	rateLimiter      *mocks.MockRateLimiter
+	loginAttemptRepo *mocks.MockLoginAttemptRepo
+	mfaRepo          *mocks.MockMFARepo
+	passkeyRepo      *mocks.MockPasskeyRepo
+	sessionRepo      *mocks.MockSessionRepo
+}
+
+type testOption func(deps *auth.AuthDeps, cfg *auth.Config)
+
+func withConfig(cfg auth.Config) testOption {
+	return func(_ *auth.AuthDeps, c *auth.Config) {
+		*c = cfg
+	}
+}
+
+func withHasher(hasher *password.Hasher) testOption {
+	return func(deps *auth.AuthDeps, _ *auth.Config) {
+		deps.PasswordHasher = hasher
+	}
+}
+
+// newTestService builds an AuthService from fresh mocks and the test
+// fixtures above, which opts may replace.
+func newTestService(t *testing.T, opts ...testOption) (*auth.AuthService, testMocks) {
+	m := testMocks{
+		txManager:        mocks.NewMockTxManager(t),
+		userRepo:         mocks.NewMockUserRepo(t),
+		eventRepo:        mocks.NewMockEventRepo(t),
+		cacheRepo:        mocks.NewMockCacheRepo(t),
+		refreshTokenRepo: mocks.NewMockRefreshTokenRepo(t),
+		userTokenRepo:    mocks.NewMockUserTokenRepo(t),
+		rateLimiter:      mocks.NewMockRateLimiter(t),
+		loginAttemptRepo: mocks.NewMockLoginAttemptRepo(t),
+		mfaRepo:          mocks.NewMockMFARepo(t),
+		passkeyRepo:      mocks.NewMockPasskeyRepo(t),
+		sessionRepo:      mocks.NewMockSessionRepo(t),
+	}
+
+	deps := auth.AuthDeps{
+		TxManager:        m.txManager,
+		UserRepo:         m.userRepo,
+		EventRepo:        m.eventRepo,
+		CacheRepo:        m.cacheRepo,
+		RefreshTokenRepo: m.refreshTokenRepo,
+		UserTokenRepo:    m.userTokenRepo,
+		RateLimiter:      m.rateLimiter,
+		LoginAttemptRepo: m.loginAttemptRepo,
+		MFARepo:          m.mfaRepo,
+		SecretBox:        testSecretBox,
+		TokenBox:         testTokenBox,
+		PasskeyRepo:      m.passkeyRepo,
+		RelyingParty:     testRelyingParty,
+		SessionRepo:      m.sessionRepo,
+		TokenIssuer:      testutils.NewIssuer("secret"),
+		PasswordPolicy:   testPasswordPolicy,
+		PasswordHasher:   testPasswordHasher,
+	}
+	cfg := testConfig
+
+	for _, opt := range opts {
+		opt(&deps, &cfg)
+	}
+
+	return auth.NewAuthService(testutils.Log, deps, cfg), m
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntrospect_Active(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, user.ID).
+		Return(true, 0, nil)
+
+	introspection, err := service.Introspect(ctx, token)
+
+	require.NoError(t, err)
+	assert.True(t, introspection.Active)
+	assert.Equal(t, user.ID, introspection.UserID)
+	assert.Equal(t, user.Email, introspection.Email)
+	assert.True(t, introspection.IsAdmin)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), introspection.ExpiresAt, time.Minute)
+	assert.WithinDuration(t, time.Now(), introspection.IssuedAt, time.Minute)
+}
+
+func TestIntrospect_RevokedIsInactive(t *testing.T) {
+	ctx := context.Background()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(true, nil)
+
+	introspection, err := service.Introspect(ctx, token)
+
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+	assert.Equal(t, uuid.Nil, introspection.UserID)
+}
+
+func TestIntrospect_ExpiredIsInactive(t *testing.T) {
+	ctx := context.Background()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, userModel.User{ID: uuid.New()}, -time.Minute)
+	require.NoError(t, err)
+
+	service, _ := newTestService(t)
+
+	introspection, err := service.Introspect(ctx, token)
+
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+}
+
+func TestIntrospect_UnknownSubjectIsInactive(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New()}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return("", 0, storage.ErrKeyNotFound)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(nil, storage.ErrUserNotFound)
+
+	introspection, err := service.Introspect(ctx, token)
+
+	require.NoError(t, err)
+	assert.False(t, introspection.Active)
+}
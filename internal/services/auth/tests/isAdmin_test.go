@@ -4,15 +4,11 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"time"
 
-	"github.com/Tbits007/auth/internal/services/testutils"
 	"github.com/Tbits007/auth/internal/storage"
 	"github.com/google/uuid"
-	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/Tbits007/auth/internal/services/auth/tests/mocks"
 	"github.com/Tbits007/auth/internal/services/auth"
 )
 
@@ -20,96 +16,56 @@ func TestIsAdmin_CacheHitTrue(t *testing.T) {
 	ctx := context.Background()
 	testUserID := uuid.New()
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
-
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return("true", nil)
-
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(true, 0, nil)
 
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.NoError(t, err)
 	assert.True(t, isAdmin)
 
-	mockUserRepo.AssertNotCalled(t, "IsAdmin")
+	m.userRepo.AssertNotCalled(t, "IsAdmin")
 }
 
 func TestIsAdmin_CacheHitFalse(t *testing.T) {
 	ctx := context.Background()
 	testUserID := uuid.New()
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
-
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return("false", nil)
-
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 0, nil)
 
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.NoError(t, err)
 	assert.False(t, isAdmin)
 
-	mockUserRepo.AssertNotCalled(t, "IsAdmin")
+	m.userRepo.AssertNotCalled(t, "IsAdmin")
 }
 
 func TestIsAdmin_CacheHitInvalid(t *testing.T) {
 	ctx := context.Background()
 	testUserID := uuid.New()
-	cacheValue := "invalid"
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return(cacheValue, nil)
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 0, errors.New("invalid admin flag"))
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		IsAdmin(ctx, testUserID).
 		Return(true, nil)
 
-	mockCacheRepo.EXPECT().
-		Set(ctx, testUserID.String(), "true", time.Hour).
+	m.cacheRepo.EXPECT().
+		SetUserAdmin(ctx, testUserID, true, int64(0), testConfig.UserCacheTTL).
 		Return(nil)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.NoError(t, err)
@@ -120,37 +76,50 @@ func TestIsAdmin_CacheMiss(t *testing.T) {
 	ctx := context.Background()
 	testUserID := uuid.New()
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return("", redis.Nil)
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 3, storage.ErrKeyNotFound)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		IsAdmin(ctx, testUserID).
 		Return(false, nil)
 
-	mockCacheRepo.EXPECT().
-		Set(ctx, testUserID.String(), "false", time.Hour).
+	// The flag is cached against the version seen on the miss, so that an
+	// eviction in between keeps it out of the cache.
+	m.cacheRepo.EXPECT().
+		SetUserAdmin(ctx, testUserID, false, int64(3), testConfig.UserCacheTTL).
 		Return(nil)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	isAdmin, err := service.IsAdmin(ctx, testUserID)
+
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+}
+
+func TestIsAdmin_CacheDisabled(t *testing.T) {
+	ctx := context.Background()
+	testUserID := uuid.New()
+
+	cfg := testConfig
+	cfg.UserCacheTTL = 0
+
+	service, m := newTestService(t, withConfig(cfg))
+
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 0, storage.ErrKeyNotFound)
+
+	m.userRepo.EXPECT().
+		IsAdmin(ctx, testUserID).
+		Return(false, nil)
 
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.NoError(t, err)
 	assert.False(t, isAdmin)
+	m.cacheRepo.AssertNotCalled(t, "SetUserAdmin")
 }
 
 func TestIsAdmin_UserNotFound(t *testing.T) {
@@ -158,29 +127,16 @@ func TestIsAdmin_UserNotFound(t *testing.T) {
 	testUserID := uuid.New()
 	expectedErr := storage.ErrUserNotFound
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return("", redis.Nil) 
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 0, storage.ErrKeyNotFound)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		IsAdmin(ctx, testUserID).
 		Return(false, expectedErr)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.Error(t, err)
@@ -193,29 +149,16 @@ func TestIsAdmin_UserRepoError(t *testing.T) {
 	testUserID := uuid.New()
 	expectedErr := errors.New("user repo error")
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return("", redis.Nil) 
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 0, storage.ErrKeyNotFound)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		IsAdmin(ctx, testUserID).
 		Return(false, expectedErr)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.Error(t, err)
@@ -228,33 +171,20 @@ func TestIsAdmin_CacheSetError(t *testing.T) {
 	testUserID := uuid.New()
 	cacheErr := errors.New("cache set error")
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return("", redis.Nil) 
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 0, storage.ErrKeyNotFound)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		IsAdmin(ctx, testUserID).
 		Return(true, nil)
 
-	mockCacheRepo.EXPECT().
-		Set(ctx, testUserID.String(), "true", time.Hour).
+	m.cacheRepo.EXPECT().
+		SetUserAdmin(ctx, testUserID, true, int64(0), testConfig.UserCacheTTL).
 		Return(cacheErr)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.NoError(t, err)
@@ -266,33 +196,20 @@ func TestIsAdmin_CacheErrorNotNil(t *testing.T) {
 	testUserID := uuid.New()
 	cacheErr := errors.New("cache error")
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testUserID.String()).
-		Return("", cacheErr) 
+	m.cacheRepo.EXPECT().
+		GetUserAdmin(ctx, testUserID).
+		Return(false, 0, cacheErr)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		IsAdmin(ctx, testUserID).
 		Return(true, nil)
 
-	mockCacheRepo.EXPECT().
-		Set(ctx, testUserID.String(), "true", time.Hour).
+	m.cacheRepo.EXPECT().
+		SetUserAdmin(ctx, testUserID, true, int64(0), testConfig.UserCacheTTL).
 		Return(nil)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
 	isAdmin, err := service.IsAdmin(ctx, testUserID)
 
 	require.NoError(t, err)
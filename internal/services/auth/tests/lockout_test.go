@@ -0,0 +1,325 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/clientip"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func lockoutConfig() auth.Config {
+	cfg := testConfig
+	cfg.LoginLockoutThreshold = 5
+	cfg.IPLockoutThreshold = 20
+	cfg.LoginLockoutWindow = 15 * time.Minute
+	cfg.LoginLockoutDuration = time.Minute
+	cfg.MaxLoginLockoutDuration = time.Hour
+	return cfg
+}
+
+func TestLogin_LockedOut(t *testing.T) {
+	ctx := clientip.WithIP(context.Background(), "192.0.2.10")
+
+	service, m := newTestService(t, withConfig(lockoutConfig()))
+
+	m.loginAttemptRepo.EXPECT().
+		LockedFor(ctx, mock.Anything, mock.Anything).
+		RunAndReturn(func(ctx context.Context, keys ...string) (time.Duration, error) {
+			assert.ElementsMatch(t, []string{"email:test@example.com", "ip:192.0.2.10"}, keys)
+			return 42 * time.Second, nil
+		})
+
+	_, err := service.Login(ctx, "Test@Example.com", "password123")
+
+	var lockoutErr *auth.LockoutError
+	require.ErrorAs(t, err, &lockoutErr)
+	assert.ErrorIs(t, err, auth.ErrTooManyAttempts)
+	assert.Equal(t, 42*time.Second, lockoutErr.RetryAfter)
+
+	m.userRepo.AssertNotCalled(t, "GetByEmail")
+}
+
+func TestLogin_FailureLocksOutWithBackoff(t *testing.T) {
+	ctx := context.Background()
+	testEmail := "test@example.com"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+
+	service, m := newTestService(t, withConfig(lockoutConfig()))
+
+	m.loginAttemptRepo.EXPECT().
+		LockedFor(ctx, "email:test@example.com").
+		Return(0, nil)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		RegisterFailure(ctx, "email:test@example.com", 15*time.Minute).
+		Return(7, nil)
+
+	// Two failures beyond the threshold of 5 double the lockout twice.
+	m.loginAttemptRepo.EXPECT().
+		Lock(ctx, "email:test@example.com", 4*time.Minute).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoginFailedV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			if event.EventType != eventModel.UserLockedV1 {
+				return false
+			}
+			var envelope struct {
+				Data eventModel.UserLocked `json:"data"`
+			}
+			if err := json.Unmarshal(event.Payload, &envelope); err != nil {
+				return false
+			}
+			return envelope.Data.UserID != nil && *envelope.Data.UserID == user.ID && envelope.Data.Failures == 7
+		})).
+		Return(uuid.New(), nil)
+
+	_, err := service.Login(ctx, testEmail, "wrong-password")
+
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
+
+func TestLogin_UnknownEmailCountsAgainstIP(t *testing.T) {
+	ctx := clientip.WithIP(context.Background(), "192.0.2.10")
+	testEmail := "nobody@example.com"
+
+	service, m := newTestService(t, withConfig(lockoutConfig()))
+
+	m.loginAttemptRepo.EXPECT().
+		LockedFor(ctx, mock.Anything, mock.Anything).
+		Return(0, nil)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(nil, storage.ErrUserNotFound)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoginFailedV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.loginAttemptRepo.EXPECT().
+		RegisterFailure(ctx, "email:nobody@example.com", 15*time.Minute).
+		Return(1, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		RegisterFailure(ctx, "ip:192.0.2.10", 15*time.Minute).
+		Return(20, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		Lock(ctx, "ip:192.0.2.10", time.Minute).
+		Return(nil)
+
+	_, err := service.Login(ctx, testEmail, "password123")
+
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	m.eventRepo.AssertNumberOfCalls(t, "Save", 1)
+}
+
+func TestLogin_SuccessResetsEmailFailures(t *testing.T) {
+	ctx := clientip.WithIP(context.Background(), "192.0.2.10")
+	testEmail := "test@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+
+	service, m := newTestService(t, withConfig(lockoutConfig()))
+
+	m.loginAttemptRepo.EXPECT().
+		LockedFor(ctx, mock.Anything, mock.Anything).
+		Return(0, nil)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		Reset(ctx, "email:test@example.com").
+		Return(nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	_, err := service.Login(ctx, testEmail, testPassword)
+
+	require.NoError(t, err)
+	m.loginAttemptRepo.AssertNotCalled(t, "Reset", ctx, "ip:192.0.2.10")
+}
+
+func TestLogin_LockoutStoreDownFailsOpen(t *testing.T) {
+	ctx := context.Background()
+	testEmail := "test@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+
+	service, m := newTestService(t, withConfig(lockoutConfig()))
+
+	m.loginAttemptRepo.EXPECT().
+		LockedFor(ctx, "email:test@example.com").
+		Return(0, errors.New("redis down"))
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		Reset(ctx, "email:test@example.com").
+		Return(errors.New("redis down"))
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	_, err := service.Login(ctx, testEmail, testPassword)
+
+	require.NoError(t, err)
+}
+
+func TestChangePassword_LockedOut(t *testing.T) {
+	ctx := context.Background()
+	user := &userModel.User{
+		ID:     uuid.New(),
+		Email:  "Test@Example.com",
+		Status: userModel.StatusActive,
+	}
+
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, *user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t, withConfig(lockoutConfig()))
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(user, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		LockedFor(ctx, "email:test@example.com").
+		Return(42*time.Second, nil)
+
+	_, err = service.ChangePassword(ctx, token, "password123", "new_password", false)
+
+	var lockoutErr *auth.LockoutError
+	require.ErrorAs(t, err, &lockoutErr)
+	assert.Equal(t, 42*time.Second, lockoutErr.RetryAfter)
+	m.userRepo.AssertNotCalled(t, "UpdatePassword")
+}
+
+func TestChangePassword_FailureCountsTowardsLockout(t *testing.T) {
+	ctx := context.Background()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &userModel.User{
+		ID:             uuid.New(),
+		Email:          "test@example.com",
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, *user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t, withConfig(lockoutConfig()))
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(user, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		LockedFor(ctx, "email:test@example.com").
+		Return(0, nil)
+
+	m.loginAttemptRepo.EXPECT().
+		RegisterFailure(ctx, "email:test@example.com", 15*time.Minute).
+		Return(1, nil)
+
+	_, err = service.ChangePassword(ctx, token, "wrong-password", "new_password", false)
+
+	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	m.loginAttemptRepo.AssertNotCalled(t, "Lock")
+	m.userRepo.AssertNotCalled(t, "UpdatePassword")
+}
@@ -6,9 +6,14 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
 	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/clientip"
+	"github.com/Tbits007/auth/internal/lib/password"
+	"github.com/Tbits007/auth/internal/lib/useragent"
 	"github.com/Tbits007/auth/internal/services/auth"
-	"github.com/Tbits007/auth/internal/services/auth/tests/mocks"
 	"github.com/Tbits007/auth/internal/services/testutils"
 	"github.com/Tbits007/auth/internal/storage"
 	"github.com/google/uuid"
@@ -18,39 +23,106 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-
-func TestLogin_CacheHit(t *testing.T) {
-	ctx := context.Background()
+func TestLogin_RecordsSession(t *testing.T) {
+	ctx := useragent.WithUserAgent(clientip.WithIP(context.Background(), "203.0.113.7"), "curl/8.0")
 	testEmail := "test@example.com"
 	testPassword := "password123"
-	expectedToken := "cached_token"
+	sessionID := uuid.New()
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.DefaultCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+
+	service, m := newTestService(t)
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testEmail).
-		Return(expectedToken, nil)
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	var familyID uuid.UUID
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(session sessionModel.Session) bool {
+			familyID = session.FamilyID
+			return session.UserID == user.ID &&
+				session.FamilyID != uuid.Nil &&
+				session.UserAgent == "curl/8.0" &&
+				session.IP == "203.0.113.7" &&
+				session.ExpiresAt.After(time.Now())
+		})).
+		Return(sessionID, nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(token tokenModel.RefreshToken) bool {
+			return token.UserID == user.ID && token.FamilyID == familyID && token.TokenHash != ""
+		})).
+		Return(uuid.New(), nil)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
 
-	token, err := service.Login(ctx, testEmail, testPassword)
+	issuer := testutils.NewIssuer("secret")
 
+	result, err := service.Login(ctx, testEmail, testPassword)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Tokens.RefreshToken)
+
+	claims, err := issuer.ParseToken(result.Tokens.AccessToken)
 	require.NoError(t, err)
-	assert.Equal(t, expectedToken, token)
+	assert.Equal(t, sessionID, claims.SessionID)
+
+	m.cacheRepo.AssertNotCalled(t, "SetMFAChallenge")
+}
+
+func TestLogin_MixedCaseEmail(t *testing.T) {
+	ctx := context.Background()
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          "test@example.com",
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+
+	service, m := newTestService(t)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, "test@example.com").
+		Return(&user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	result, err := service.Login(ctx, " Test@Example.COM ", testPassword)
 
-	mockUserRepo.AssertNotCalled(t, "GetByEmail")
-	mockEventRepo.AssertNotCalled(t, "Save")
+	require.NoError(t, err)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
 }
 
 func TestLogin_UserNotFound(t *testing.T) {
@@ -59,36 +131,23 @@ func TestLogin_UserNotFound(t *testing.T) {
 	testPassword := "password123"
 	expectedErr := storage.ErrUserNotFound
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
-
-	mockCacheRepo.EXPECT().
-		Get(ctx, testEmail).
-		Return("", errors.New("cache miss"))
+	service, m := newTestService(t)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		GetByEmail(ctx, testEmail).
 		Return(nil, expectedErr)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoginFailedV1
+		})).
+		Return(uuid.New(), nil)
 
-	token, err := service.Login(ctx, testEmail, testPassword)
+	result, err := service.Login(ctx, testEmail, testPassword)
 
 	require.Error(t, err)
-	assert.Empty(t, token)
+	assert.Empty(t, result)
 	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
-
-	mockEventRepo.AssertNotCalled(t, "Save")
 }
 
 func TestLogin_InvalidCredentials(t *testing.T) {
@@ -99,38 +158,61 @@ func TestLogin_InvalidCredentials(t *testing.T) {
 	user := userModel.User{
 		Email:          testEmail,
 		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
 	}
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testEmail).
-		Return("", errors.New("cache miss"))
-
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		GetByEmail(ctx, testEmail).
 		Return(&user, nil)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoginFailedV1
+		})).
+		Return(uuid.New(), nil)
 
-	token, err := service.Login(ctx, testEmail, testPassword)
+	result, err := service.Login(ctx, testEmail, testPassword)
 
 	require.Error(t, err)
-	assert.Empty(t, token)
+	assert.Empty(t, result)
 	assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+}
 
-	mockEventRepo.AssertNotCalled(t, "Save")
+func TestLogin_InactiveAccount(t *testing.T) {
+	testEmail := "test@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+
+	for status, expectedErr := range map[userModel.Status]error{
+		userModel.StatusPendingVerification: auth.ErrAccountNotVerified,
+		userModel.StatusDisabled:            auth.ErrAccountDisabled,
+		userModel.StatusDeleted:             auth.ErrAccountDeleted,
+	} {
+		t.Run(string(status), func(t *testing.T) {
+			ctx := context.Background()
+			user := userModel.User{
+				ID:             uuid.New(),
+				Email:          testEmail,
+				HashedPassword: string(hashedPassword),
+				Status:         status,
+			}
+
+			service, m := newTestService(t)
+
+			m.userRepo.EXPECT().
+				GetByEmail(ctx, testEmail).
+				Return(&user, nil)
+
+			result, err := service.Login(ctx, testEmail, testPassword)
+
+			assert.Empty(t, result)
+			assert.ErrorIs(t, err, expectedErr)
+			m.cacheRepo.AssertNotCalled(t, "SetMFAChallenge")
+			m.refreshTokenRepo.AssertNotCalled(t, "Save")
+		})
+	}
 }
 
 func TestLogin_UserRepoError(t *testing.T) {
@@ -139,36 +221,19 @@ func TestLogin_UserRepoError(t *testing.T) {
 	testPassword := "password123"
 	expectedErr := errors.New("user repo error")
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
-
-	mockCacheRepo.EXPECT().
-		Get(ctx, testEmail).
-		Return("", errors.New("cache miss"))
+	service, m := newTestService(t)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		GetByEmail(ctx, testEmail).
 		Return(nil, expectedErr)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
-	token, err := service.Login(ctx, testEmail, testPassword)
+	result, err := service.Login(ctx, testEmail, testPassword)
 
 	require.Error(t, err)
-	assert.Empty(t, token)
+	assert.Empty(t, result)
 	assert.Contains(t, err.Error(), expectedErr.Error())
 
-	mockEventRepo.AssertNotCalled(t, "Save")
+	m.eventRepo.AssertNotCalled(t, "Save")
 }
 
 func TestLogin_Success(t *testing.T) {
@@ -180,43 +245,38 @@ func TestLogin_Success(t *testing.T) {
 		ID:             uuid.New(),
 		Email:          testEmail,
 		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
 	}
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
-
-	mockCacheRepo.EXPECT().
-		Get(ctx, testEmail).
-		Return("", errors.New("cache miss"))
+	service, m := newTestService(t)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		GetByEmail(ctx, testEmail).
 		Return(&user, nil)
 
-	mockEventRepo.EXPECT().
-		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
 		Return(uuid.New(), nil)
 
-	mockCacheRepo.EXPECT().
-		Set(ctx, testEmail, mock.AnythingOfType("string"), time.Hour).
-		Return(nil)
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
 
-	token, err := service.Login(ctx, testEmail, testPassword)
+	result, err := service.Login(ctx, testEmail, testPassword)
 
 	require.NoError(t, err)
-	assert.NotEmpty(t, token)
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+	assert.NotEmpty(t, result.Tokens.RefreshToken)
 }
 
 func TestLogin_EventSaveError(t *testing.T) {
@@ -228,47 +288,79 @@ func TestLogin_EventSaveError(t *testing.T) {
 		ID:             uuid.New(),
 		Email:          testEmail,
 		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
 	}
 	expectedErr := errors.New("event save error")
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
-
-	mockCacheRepo.EXPECT().
-		Get(ctx, testEmail).
-		Return("", errors.New("cache miss"))
+	service, m := newTestService(t)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		GetByEmail(ctx, testEmail).
 		Return(&user, nil)
 
-	mockEventRepo.EXPECT().
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
 		Save(ctx, mock.AnythingOfType("eventModel.Event")).
 		Return(uuid.Nil, expectedErr)
 
-	mockCacheRepo.EXPECT().
-		Set(ctx, testEmail, mock.AnythingOfType("string"), time.Hour).
-		Return(nil)
+	result, err := service.Login(ctx, testEmail, testPassword)
+
+	require.NoError(t, err) 
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+}
+
+func TestLogin_SessionSaveError(t *testing.T) {
+	ctx := context.Background()
+	testEmail := "test@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.DefaultCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+	expectedErr := errors.New("session save error")
+
+	service, m := newTestService(t)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
 
-	token, err := service.Login(ctx, testEmail, testPassword)
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.Nil, expectedErr)
 
-	require.NoError(t, err) 
-	assert.NotEmpty(t, token)
+	result, err := service.Login(ctx, testEmail, testPassword)
+
+	require.ErrorIs(t, err, expectedErr)
+	assert.Empty(t, result)
+
+	m.refreshTokenRepo.AssertNotCalled(t, "Save")
+	m.eventRepo.AssertNotCalled(t, "Save")
 }
 
-func TestLogin_CacheSetError(t *testing.T) {
+func TestLogin_RefreshTokenSaveError(t *testing.T) {
 	ctx := context.Background()
 	testEmail := "test@example.com"
 	testPassword := "password123"
@@ -277,42 +369,130 @@ func TestLogin_CacheSetError(t *testing.T) {
 		ID:             uuid.New(),
 		Email:          testEmail,
 		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
 	}
-	cacheErr := errors.New("cache set error")
+	expectedErr := errors.New("refresh token save error")
+
+	service, m := newTestService(t)
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.Nil, expectedErr)
+
+	result, err := service.Login(ctx, testEmail, testPassword)
+
+	require.Error(t, err)
+	assert.Empty(t, result)
+	assert.Contains(t, err.Error(), expectedErr.Error())
+
+	m.eventRepo.AssertNotCalled(t, "Save")
+}
+
+func TestLogin_RehashesLegacyHash(t *testing.T) {
+	ctx := context.Background()
+	testEmail := "test@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+	argon2id := password.Argon2id{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
 
-	mockCacheRepo.EXPECT().
-		Get(ctx, testEmail).
-		Return("", errors.New("cache miss"))
+	service, m := newTestService(t, withHasher(password.NewHasher(argon2id, password.Bcrypt{Cost: bcrypt.MinCost})))
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		GetByEmail(ctx, testEmail).
 		Return(&user, nil)
 
-	mockEventRepo.EXPECT().
+	m.userRepo.EXPECT().
+		UpdatePassword(ctx, user.ID, mock.MatchedBy(func(hash string) bool {
+			ok, err := argon2id.Verify(hash, testPassword)
+			return err == nil && ok
+		})).
+		Return(nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
 		Save(ctx, mock.AnythingOfType("eventModel.Event")).
 		Return(uuid.New(), nil)
 
-	mockCacheRepo.EXPECT().
-		Set(ctx, testEmail, mock.AnythingOfType("string"), time.Hour).
-		Return(cacheErr)
+	_, err := service.Login(ctx, testEmail, testPassword)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
+	require.NoError(t, err)
+}
 
-	token, err := service.Login(ctx, testEmail, testPassword)
+func TestLogin_RehashFailureDoesNotFailLogin(t *testing.T) {
+	ctx := context.Background()
+	testEmail := "test@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+	}
+
+	service, m := newTestService(t, withHasher(password.NewHasher(password.Bcrypt{Cost: bcrypt.MinCost + 1})))
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
+
+	m.userRepo.EXPECT().
+		UpdatePassword(ctx, user.ID, mock.AnythingOfType("string")).
+		Return(errors.New("db down"))
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	result, err := service.Login(ctx, testEmail, testPassword)
 
 	require.NoError(t, err)
-	assert.NotEmpty(t, token)
-}
\ No newline at end of file
+	assert.NotEmpty(t, result.Tokens.AccessToken)
+}
@@ -0,0 +1,254 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateToken_Success(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	claims, err := service.ValidateToken(ctx, token)
+
+	require.NoError(t, err)
+	assert.Equal(t, user.ID, claims.UserID)
+	assert.Equal(t, user.Email, claims.Email)
+}
+
+func TestValidateToken_InactiveAccount(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return("", 3, storage.ErrKeyNotFound)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&userModel.User{ID: user.ID, Status: userModel.StatusDisabled}, nil)
+
+	m.cacheRepo.EXPECT().
+		SetUserStatus(ctx, user.ID, userModel.StatusDisabled, int64(3), testConfig.UserCacheTTL).
+		Return(nil)
+
+	_, err = service.ValidateToken(ctx, token)
+
+	assert.ErrorIs(t, err, auth.ErrAccountDisabled)
+}
+
+func TestValidateToken_Revoked(t *testing.T) {
+	ctx := context.Background()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(true, nil)
+
+	claims, err := service.ValidateToken(ctx, token)
+
+	require.Error(t, err)
+	assert.Nil(t, claims)
+	assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+}
+
+func TestValidateToken_RevocationCheckError(t *testing.T) {
+	ctx := context.Background()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+	cacheErr := errors.New("cache error")
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, cacheErr)
+
+	claims, err := service.ValidateToken(ctx, token)
+
+	require.Error(t, err)
+	assert.Nil(t, claims)
+	assert.Contains(t, err.Error(), cacheErr.Error())
+}
+
+func TestValidateToken_WrongSecret(t *testing.T) {
+	ctx := context.Background()
+	token, err := testutils.NewIssuer("other_secret").NewToken(ctx, userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	claims, err := service.ValidateToken(ctx, token)
+
+	require.Error(t, err)
+	assert.Nil(t, claims)
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+
+	m.cacheRepo.AssertNotCalled(t, "IsTokenRevoked")
+}
+
+func TestRevokeToken_Success(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		RevokeToken(ctx, mock.AnythingOfType("string"), mock.MatchedBy(func(ttl time.Duration) bool {
+			return ttl > 0 && ttl <= time.Hour
+		})).
+		Return(nil)
+
+	err = service.RevokeToken(ctx, token)
+
+	require.NoError(t, err)
+}
+
+func TestRevokeToken_AlreadyExpired(t *testing.T) {
+	ctx := context.Background()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, userModel.User{ID: uuid.New()}, -time.Minute)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	err = service.RevokeToken(ctx, token)
+
+	require.NoError(t, err)
+	m.cacheRepo.AssertNotCalled(t, "RevokeToken")
+}
+
+func TestLogout_RevokesAccessAndRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+	testRefreshToken := "refresh_token"
+	stored := tokenModel.RefreshToken{
+		ID:       uuid.New(),
+		UserID:   user.ID,
+		FamilyID: uuid.New(),
+	}
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeToken(ctx, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoggedOutV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(&stored, nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeFamily(ctx, stored.FamilyID).
+		Return(nil)
+
+	err = service.Logout(ctx, token, testRefreshToken)
+
+	require.NoError(t, err)
+}
+
+func TestLogout_ForeignRefreshTokenIgnored(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+	testRefreshToken := "refresh_token"
+	stored := tokenModel.RefreshToken{
+		ID:       uuid.New(),
+		UserID:   uuid.New(),
+		FamilyID: uuid.New(),
+	}
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeToken(ctx, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoggedOutV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(&stored, nil)
+
+	err = service.Logout(ctx, token, testRefreshToken)
+
+	require.NoError(t, err)
+	m.refreshTokenRepo.AssertNotCalled(t, "RevokeFamily")
+}
+
+func TestLogout_RevokedToken(t *testing.T) {
+	ctx := context.Background()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, userModel.User{ID: uuid.New()}, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(true, nil)
+
+	err = service.Logout(ctx, token, "")
+
+	require.Error(t, err)
+	assert.ErrorIs(t, err, auth.ErrTokenRevoked)
+	m.cacheRepo.AssertNotCalled(t, "RevokeToken")
+}
@@ -0,0 +1,497 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/mfaModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/lib/ratelimiter"
+	"github.com/Tbits007/auth/internal/lib/totp"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testTOTPSecret = "JBSWY3DPEHPK3PXP"
+
+func encodeChallenge(t *testing.T, userID uuid.UUID, enrollmentRequired bool) []byte {
+	t.Helper()
+
+	value, err := json.Marshal(mfaModel.Challenge{
+		UserID:             userID,
+		EnrollmentRequired: enrollmentRequired,
+		ExpiresAt:          time.Now().Add(testConfig.MFAChallengeTTL),
+	})
+	require.NoError(t, err)
+
+	return value
+}
+
+func sealedTOTP(t *testing.T, userID uuid.UUID, confirmed bool) *mfaModel.TOTP {
+	t.Helper()
+
+	sealed, err := testSecretBox.Seal([]byte(testTOTPSecret), userID[:])
+	require.NoError(t, err)
+
+	stored := &mfaModel.TOTP{
+		UserID:          userID,
+		EncryptedSecret: sealed,
+		CreatedAt:       time.Now(),
+	}
+	if confirmed {
+		now := time.Now()
+		stored.ConfirmedAt = &now
+	}
+
+	return stored
+}
+
+func currentCode(t *testing.T) string {
+	t.Helper()
+
+	code, err := totp.Code(testTOTPSecret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	return code
+}
+
+func TestLogin_MFAEnabledReturnsChallenge(t *testing.T) {
+	ctx := context.Background()
+	testEmail := "test@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+		MFAEnabled:     true,
+	}
+
+	service, m := newTestService(t)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
+
+	m.cacheRepo.EXPECT().
+		SetMFAChallenge(ctx, mock.AnythingOfType("string"), mock.Anything, testConfig.MFAChallengeTTL).
+		Return(nil)
+
+	result, err := service.Login(ctx, testEmail, testPassword)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Tokens)
+	require.NotNil(t, result.MFAChallenge)
+	assert.NotEmpty(t, result.MFAChallenge.ID)
+	assert.Equal(t, user.ID, result.MFAChallenge.UserID)
+	assert.False(t, result.MFAChallenge.EnrollmentRequired)
+
+	m.refreshTokenRepo.AssertNotCalled(t, "Save")
+	m.eventRepo.AssertNotCalled(t, "Save")
+}
+
+func TestLogin_AdminWithoutMFARequiresEnrollment(t *testing.T) {
+	ctx := context.Background()
+	testEmail := "admin@example.com"
+	testPassword := "password123"
+	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(testPassword), bcrypt.MinCost)
+	user := userModel.User{
+		ID:             uuid.New(),
+		Email:          testEmail,
+		HashedPassword: string(hashedPassword),
+		Status:         userModel.StatusActive,
+		IsAdmin:        true,
+	}
+
+	service, m := newTestService(t)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, testEmail).
+		Return(&user, nil)
+
+	m.cacheRepo.EXPECT().
+		SetMFAChallenge(ctx, mock.AnythingOfType("string"), mock.Anything, testConfig.MFAChallengeTTL).
+		Return(nil)
+
+	result, err := service.Login(ctx, testEmail, testPassword)
+
+	require.NoError(t, err)
+	assert.Empty(t, result.Tokens)
+	require.NotNil(t, result.MFAChallenge)
+	assert.True(t, result.MFAChallenge.EnrollmentRequired)
+}
+
+func TestEnrollTOTP_WithEnrollmentChallenge(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+	user := userModel.User{
+		ID:      uuid.New(),
+		Email:   "admin@example.com",
+		Status:  userModel.StatusActive,
+		IsAdmin: true,
+	}
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetMFAChallenge(ctx, challengeID).
+		Return(encodeChallenge(t, user.ID, true), nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	var sealed []byte
+	m.mfaRepo.EXPECT().
+		SaveTOTP(ctx, user.ID, mock.AnythingOfType("[]uint8")).
+		RunAndReturn(func(ctx context.Context, userID uuid.UUID, encryptedSecret []byte) error {
+			sealed = encryptedSecret
+			return nil
+		})
+
+	enrollment, err := service.EnrollTOTP(ctx, "", challengeID)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, enrollment.Secret)
+	assert.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/"), enrollment.URI)
+
+	secret, err := testSecretBox.Open(sealed, user.ID[:])
+	require.NoError(t, err)
+	assert.Equal(t, enrollment.Secret, string(secret))
+}
+
+func TestEnrollTOTP_RejectsLoginChallenge(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+	userID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetMFAChallenge(ctx, challengeID).
+		Return(encodeChallenge(t, userID, false), nil)
+
+	_, err := service.EnrollTOTP(ctx, "", challengeID)
+
+	assert.ErrorIs(t, err, auth.ErrInvalidMFAChallenge)
+	m.mfaRepo.AssertNotCalled(t, "SaveTOTP")
+}
+
+func TestConfirmTOTP_CompletesEnrollmentLogin(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+	user := userModel.User{
+		ID:      uuid.New(),
+		Email:   "admin@example.com",
+		Status:  userModel.StatusActive,
+		IsAdmin: true,
+	}
+	challenge := encodeChallenge(t, user.ID, true)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetMFAChallenge(ctx, challengeID).
+		Return(challenge, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.rateLimiter.EXPECT().
+		AllowMFAAttempt(ctx, challengeID, testConfig.MFAMaxAttempts, testConfig.MFAChallengeTTL).
+		Return(nil)
+
+	m.mfaRepo.EXPECT().
+		GetTOTP(ctx, user.ID).
+		Return(sealedTOTP(t, user.ID, false), nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.mfaRepo.EXPECT().
+		ConfirmTOTP(ctx, user.ID, mock.AnythingOfType("int64")).
+		Return(nil)
+
+	var hashes []string
+	m.mfaRepo.EXPECT().
+		ReplaceRecoveryCodes(ctx, user.ID, mock.AnythingOfType("[]string")).
+		RunAndReturn(func(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+			hashes = codeHashes
+			return nil
+		})
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserMFAEnabledV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.cacheRepo.EXPECT().
+		TakeMFAChallenge(ctx, challengeID).
+		Return(challenge, nil)
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(token tokenModel.RefreshToken) bool {
+			return token.UserID == user.ID
+		})).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoggedInV1
+		})).
+		Return(uuid.New(), nil)
+
+	codes, tokens, err := service.ConfirmTOTP(ctx, "", challengeID, currentCode(t))
+
+	require.NoError(t, err)
+	require.Len(t, codes, testConfig.RecoveryCodeCount)
+	require.Len(t, hashes, testConfig.RecoveryCodeCount)
+	for _, code := range codes {
+		assert.Regexp(t, `^[a-hjkmnp-z2-9]{5}-[a-hjkmnp-z2-9]{5}$`, code)
+	}
+	assert.Equal(t, opaque.Hash(strings.ReplaceAll(codes[0], "-", "")), hashes[0])
+	require.NotNil(t, tokens)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+}
+
+func TestVerifyMFA_TOTPCode(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+	user := userModel.User{
+		ID:         uuid.New(),
+		Email:      "test@example.com",
+		Status:     userModel.StatusActive,
+		MFAEnabled: true,
+	}
+	challenge := encodeChallenge(t, user.ID, false)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetMFAChallenge(ctx, challengeID).
+		Return(challenge, nil)
+
+	m.rateLimiter.EXPECT().
+		AllowMFAAttempt(ctx, challengeID, testConfig.MFAMaxAttempts, testConfig.MFAChallengeTTL).
+		Return(nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.mfaRepo.EXPECT().
+		GetTOTP(ctx, user.ID).
+		Return(sealedTOTP(t, user.ID, true), nil)
+
+	m.mfaRepo.EXPECT().
+		UseTOTPStep(ctx, user.ID, mock.AnythingOfType("int64")).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		TakeMFAChallenge(ctx, challengeID).
+		Return(challenge, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	tokens, err := service.VerifyMFA(ctx, challengeID, currentCode(t))
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+}
+
+func TestVerifyMFA_RecoveryCode(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+	user := userModel.User{
+		ID:         uuid.New(),
+		Email:      "test@example.com",
+		Status:     userModel.StatusActive,
+		MFAEnabled: true,
+	}
+	challenge := encodeChallenge(t, user.ID, false)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetMFAChallenge(ctx, challengeID).
+		Return(challenge, nil)
+
+	m.rateLimiter.EXPECT().
+		AllowMFAAttempt(ctx, challengeID, testConfig.MFAMaxAttempts, testConfig.MFAChallengeTTL).
+		Return(nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.mfaRepo.EXPECT().
+		UseRecoveryCode(ctx, user.ID, opaque.Hash("abcdefghjk")).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		TakeMFAChallenge(ctx, challengeID).
+		Return(challenge, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	tokens, err := service.VerifyMFA(ctx, challengeID, "ABCDE-FGHJK")
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	m.mfaRepo.AssertNotCalled(t, "GetTOTP")
+}
+
+func TestVerifyMFA_ReplayedCode(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+	user := userModel.User{
+		ID:         uuid.New(),
+		Email:      "test@example.com",
+		Status:     userModel.StatusActive,
+		MFAEnabled: true,
+	}
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetMFAChallenge(ctx, challengeID).
+		Return(encodeChallenge(t, user.ID, false), nil)
+
+	m.rateLimiter.EXPECT().
+		AllowMFAAttempt(ctx, challengeID, testConfig.MFAMaxAttempts, testConfig.MFAChallengeTTL).
+		Return(nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	stored := sealedTOTP(t, user.ID, true)
+	stored.LastUsedStep = totp.Step(time.Now())
+	m.mfaRepo.EXPECT().
+		GetTOTP(ctx, user.ID).
+		Return(stored, nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoginFailedV1
+		})).
+		Return(uuid.New(), nil)
+
+	_, err := service.VerifyMFA(ctx, challengeID, currentCode(t))
+
+	assert.ErrorIs(t, err, auth.ErrInvalidMFACode)
+	m.mfaRepo.AssertNotCalled(t, "UseTOTPStep")
+	m.cacheRepo.AssertNotCalled(t, "TakeMFAChallenge")
+	m.refreshTokenRepo.AssertNotCalled(t, "Save")
+}
+
+func TestVerifyMFA_TooManyAttempts(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+	userID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		GetMFAChallenge(ctx, challengeID).
+		Return(encodeChallenge(t, userID, false), nil)
+
+	m.rateLimiter.EXPECT().
+		AllowMFAAttempt(ctx, challengeID, testConfig.MFAMaxAttempts, testConfig.MFAChallengeTTL).
+		Return(ratelimiter.ErrRateLimited)
+
+	m.cacheRepo.EXPECT().
+		DeleteMFAChallenge(ctx, challengeID).
+		Return(nil)
+
+	_, err := service.VerifyMFA(ctx, challengeID, "123456")
+
+	assert.ErrorIs(t, err, auth.ErrTooManyAttempts)
+	m.userRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestVerifyMFA_InvalidChallenge(t *testing.T) {
+	ctx := context.Background()
+	challengeID := "challenge"
+
+	tests := []struct {
+		name    string
+		value   []byte
+		err     error
+		wantErr error
+	}{
+		{name: "unknown challenge", err: storage.ErrKeyNotFound, wantErr: auth.ErrInvalidMFAChallenge},
+		{name: "enrollment challenge", value: encodeChallenge(t, uuid.New(), true), wantErr: auth.ErrMFAEnrollmentRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, m := newTestService(t)
+
+			m.cacheRepo.EXPECT().
+				GetMFAChallenge(ctx, challengeID).
+				Return(tt.value, tt.err)
+
+			_, err := service.VerifyMFA(ctx, challengeID, "123456")
+
+			assert.True(t, errors.Is(err, tt.wantErr), err)
+			m.rateLimiter.AssertNotCalled(t, "AllowMFAAttempt")
+		})
+	}
+}
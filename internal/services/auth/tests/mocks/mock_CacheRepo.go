@@ -7,6 +7,10 @@ import (
 	time "time"
 
 	mock "github.com/stretchr/testify/mock"
+
+	userModel "github.com/Tbits007/auth/internal/domain/models/userModel"
+
+	uuid "github.com/google/uuid"
 )
 
 // MockCacheRepo is an autogenerated mock type for the CacheRepo type
@@ -22,27 +26,76 @@ func (_m *MockCacheRepo) EXPECT() *MockCacheRepo_Expecter {
 	return &MockCacheRepo_Expecter{mock: &_m.Mock}
 }
 
-// Get provides a mock function with given fields: ctx, key
-func (_m *MockCacheRepo) Get(ctx context.Context, key string) (string, error) {
-	ret := _m.Called(ctx, key)
+// DeleteMFAChallenge provides a mock function with given fields: ctx, id
+func (_m *MockCacheRepo) DeleteMFAChallenge(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Get")
+		panic("no return value specified for DeleteMFAChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
 	}
 
-	var r0 string
+	return r0
+}
+
+// MockCacheRepo_DeleteMFAChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteMFAChallenge'
+type MockCacheRepo_DeleteMFAChallenge_Call struct {
+	*mock.Call
+}
+
+// DeleteMFAChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockCacheRepo_Expecter) DeleteMFAChallenge(ctx interface{}, id interface{}) *MockCacheRepo_DeleteMFAChallenge_Call {
+	return &MockCacheRepo_DeleteMFAChallenge_Call{Call: _e.mock.On("DeleteMFAChallenge", ctx, id)}
+}
+
+func (_c *MockCacheRepo_DeleteMFAChallenge_Call) Run(run func(ctx context.Context, id string)) *MockCacheRepo_DeleteMFAChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_DeleteMFAChallenge_Call) Return(_a0 error) *MockCacheRepo_DeleteMFAChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_DeleteMFAChallenge_Call) RunAndReturn(run func(context.Context, string) error) *MockCacheRepo_DeleteMFAChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetMFAChallenge provides a mock function with given fields: ctx, id
+func (_m *MockCacheRepo) GetMFAChallenge(ctx context.Context, id string) ([]byte, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMFAChallenge")
+	}
+
+	var r0 []byte
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
-		return rf(ctx, key)
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, id)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
-		r0 = rf(ctx, key)
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, id)
 	} else {
-		r0 = ret.Get(0).(string)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, key)
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -50,46 +103,174 @@ func (_m *MockCacheRepo) Get(ctx context.Context, key string) (string, error) {
 	return r0, r1
 }
 
-// MockCacheRepo_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
-type MockCacheRepo_Get_Call struct {
+// MockCacheRepo_GetMFAChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetMFAChallenge'
+type MockCacheRepo_GetMFAChallenge_Call struct {
 	*mock.Call
 }
 
-// Get is a helper method to define mock.On call
+// GetMFAChallenge is a helper method to define mock.On call
 //   - ctx context.Context
-//   - key string
-func (_e *MockCacheRepo_Expecter) Get(ctx interface{}, key interface{}) *MockCacheRepo_Get_Call {
-	return &MockCacheRepo_Get_Call{Call: _e.mock.On("Get", ctx, key)}
+//   - id string
+func (_e *MockCacheRepo_Expecter) GetMFAChallenge(ctx interface{}, id interface{}) *MockCacheRepo_GetMFAChallenge_Call {
+	return &MockCacheRepo_GetMFAChallenge_Call{Call: _e.mock.On("GetMFAChallenge", ctx, id)}
 }
 
-func (_c *MockCacheRepo_Get_Call) Run(run func(ctx context.Context, key string)) *MockCacheRepo_Get_Call {
+func (_c *MockCacheRepo_GetMFAChallenge_Call) Run(run func(ctx context.Context, id string)) *MockCacheRepo_GetMFAChallenge_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		run(args[0].(context.Context), args[1].(string))
 	})
 	return _c
 }
 
-func (_c *MockCacheRepo_Get_Call) Return(_a0 string, _a1 error) *MockCacheRepo_Get_Call {
+func (_c *MockCacheRepo_GetMFAChallenge_Call) Return(_a0 []byte, _a1 error) *MockCacheRepo_GetMFAChallenge_Call {
 	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *MockCacheRepo_Get_Call) RunAndReturn(run func(context.Context, string) (string, error)) *MockCacheRepo_Get_Call {
+func (_c *MockCacheRepo_GetMFAChallenge_Call) RunAndReturn(run func(context.Context, string) ([]byte, error)) *MockCacheRepo_GetMFAChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetUserAdmin provides a mock function with given fields: ctx, userID
+func (_m *MockCacheRepo) GetUserAdmin(ctx context.Context, userID uuid.UUID) (bool, int64, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUserAdmin")
+	}
+
+	var r0 bool
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (bool, int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) bool); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) int64); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockCacheRepo_GetUserAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserAdmin'
+type MockCacheRepo_GetUserAdmin_Call struct {
+	*mock.Call
+}
+
+// GetUserAdmin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockCacheRepo_Expecter) GetUserAdmin(ctx interface{}, userID interface{}) *MockCacheRepo_GetUserAdmin_Call {
+	return &MockCacheRepo_GetUserAdmin_Call{Call: _e.mock.On("GetUserAdmin", ctx, userID)}
+}
+
+func (_c *MockCacheRepo_GetUserAdmin_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockCacheRepo_GetUserAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_GetUserAdmin_Call) Return(_a0 bool, _a1 int64, _a2 error) *MockCacheRepo_GetUserAdmin_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockCacheRepo_GetUserAdmin_Call) RunAndReturn(run func(context.Context, uuid.UUID) (bool, int64, error)) *MockCacheRepo_GetUserAdmin_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Set provides a mock function with given fields: ctx, key, value, expiration
-func (_m *MockCacheRepo) Set(ctx context.Context, key string, value any, expiration time.Duration) error {
-	ret := _m.Called(ctx, key, value, expiration)
+// GetUserStatus provides a mock function with given fields: ctx, userID
+func (_m *MockCacheRepo) GetUserStatus(ctx context.Context, userID uuid.UUID) (userModel.Status, int64, error) {
+	ret := _m.Called(ctx, userID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Set")
+		panic("no return value specified for GetUserStatus")
+	}
+
+	var r0 userModel.Status
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (userModel.Status, int64, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) userModel.Status); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(userModel.Status)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) int64); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, uuid.UUID) error); ok {
+		r2 = rf(ctx, userID)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockCacheRepo_GetUserStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetUserStatus'
+type MockCacheRepo_GetUserStatus_Call struct {
+	*mock.Call
+}
+
+// GetUserStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockCacheRepo_Expecter) GetUserStatus(ctx interface{}, userID interface{}) *MockCacheRepo_GetUserStatus_Call {
+	return &MockCacheRepo_GetUserStatus_Call{Call: _e.mock.On("GetUserStatus", ctx, userID)}
+}
+
+func (_c *MockCacheRepo_GetUserStatus_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockCacheRepo_GetUserStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_GetUserStatus_Call) Return(_a0 userModel.Status, _a1 int64, _a2 error) *MockCacheRepo_GetUserStatus_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockCacheRepo_GetUserStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID) (userModel.Status, int64, error)) *MockCacheRepo_GetUserStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InvalidateUser provides a mock function with given fields: ctx, userID
+func (_m *MockCacheRepo) InvalidateUser(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateUser")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, any, time.Duration) error); ok {
-		r0 = rf(ctx, key, value, expiration)
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -97,33 +278,661 @@ func (_m *MockCacheRepo) Set(ctx context.Context, key string, value any, expirat
 	return r0
 }
 
-// MockCacheRepo_Set_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Set'
-type MockCacheRepo_Set_Call struct {
+// MockCacheRepo_InvalidateUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateUser'
+type MockCacheRepo_InvalidateUser_Call struct {
 	*mock.Call
 }
 
-// Set is a helper method to define mock.On call
+// InvalidateUser is a helper method to define mock.On call
 //   - ctx context.Context
-//   - key string
-//   - value any
+//   - userID uuid.UUID
+func (_e *MockCacheRepo_Expecter) InvalidateUser(ctx interface{}, userID interface{}) *MockCacheRepo_InvalidateUser_Call {
+	return &MockCacheRepo_InvalidateUser_Call{Call: _e.mock.On("InvalidateUser", ctx, userID)}
+}
+
+func (_c *MockCacheRepo_InvalidateUser_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockCacheRepo_InvalidateUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_InvalidateUser_Call) Return(_a0 error) *MockCacheRepo_InvalidateUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_InvalidateUser_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockCacheRepo_InvalidateUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IsTokenRevoked provides a mock function with given fields: ctx, jti, userID, sessionID, issuedAt
+func (_m *MockCacheRepo) IsTokenRevoked(ctx context.Context, jti string, userID uuid.UUID, sessionID uuid.UUID, issuedAt time.Time) (bool, error) {
+	ret := _m.Called(ctx, jti, userID, sessionID, issuedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsTokenRevoked")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, uuid.UUID, time.Time) (bool, error)); ok {
+		return rf(ctx, jti, userID, sessionID, issuedAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, uuid.UUID, uuid.UUID, time.Time) bool); ok {
+		r0 = rf(ctx, jti, userID, sessionID, issuedAt)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, uuid.UUID, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, jti, userID, sessionID, issuedAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCacheRepo_IsTokenRevoked_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IsTokenRevoked'
+type MockCacheRepo_IsTokenRevoked_Call struct {
+	*mock.Call
+}
+
+// IsTokenRevoked is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+//   - userID uuid.UUID
+//   - sessionID uuid.UUID
+//   - issuedAt time.Time
+func (_e *MockCacheRepo_Expecter) IsTokenRevoked(ctx interface{}, jti interface{}, userID interface{}, sessionID interface{}, issuedAt interface{}) *MockCacheRepo_IsTokenRevoked_Call {
+	return &MockCacheRepo_IsTokenRevoked_Call{Call: _e.mock.On("IsTokenRevoked", ctx, jti, userID, sessionID, issuedAt)}
+}
+
+func (_c *MockCacheRepo_IsTokenRevoked_Call) Run(run func(ctx context.Context, jti string, userID uuid.UUID, sessionID uuid.UUID, issuedAt time.Time)) *MockCacheRepo_IsTokenRevoked_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(uuid.UUID), args[3].(uuid.UUID), args[4].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_IsTokenRevoked_Call) Return(_a0 bool, _a1 error) *MockCacheRepo_IsTokenRevoked_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCacheRepo_IsTokenRevoked_Call) RunAndReturn(run func(context.Context, string, uuid.UUID, uuid.UUID, time.Time) (bool, error)) *MockCacheRepo_IsTokenRevoked_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeSession provides a mock function with given fields: ctx, sessionID, expiration
+func (_m *MockCacheRepo) RevokeSession(ctx context.Context, sessionID uuid.UUID, expiration time.Duration) error {
+	ret := _m.Called(ctx, sessionID, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeSession")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Duration) error); ok {
+		r0 = rf(ctx, sessionID, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_RevokeSession_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeSession'
+type MockCacheRepo_RevokeSession_Call struct {
+	*mock.Call
+}
+
+// RevokeSession is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sessionID uuid.UUID
 //   - expiration time.Duration
-func (_e *MockCacheRepo_Expecter) Set(ctx interface{}, key interface{}, value interface{}, expiration interface{}) *MockCacheRepo_Set_Call {
-	return &MockCacheRepo_Set_Call{Call: _e.mock.On("Set", ctx, key, value, expiration)}
+func (_e *MockCacheRepo_Expecter) RevokeSession(ctx interface{}, sessionID interface{}, expiration interface{}) *MockCacheRepo_RevokeSession_Call {
+	return &MockCacheRepo_RevokeSession_Call{Call: _e.mock.On("RevokeSession", ctx, sessionID, expiration)}
 }
 
-func (_c *MockCacheRepo_Set_Call) Run(run func(ctx context.Context, key string, value any, expiration time.Duration)) *MockCacheRepo_Set_Call {
+func (_c *MockCacheRepo_RevokeSession_Call) Run(run func(ctx context.Context, sessionID uuid.UUID, expiration time.Duration)) *MockCacheRepo_RevokeSession_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string), args[2].(any), args[3].(time.Duration))
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Duration))
 	})
 	return _c
 }
 
-func (_c *MockCacheRepo_Set_Call) Return(_a0 error) *MockCacheRepo_Set_Call {
+func (_c *MockCacheRepo_RevokeSession_Call) Return(_a0 error) *MockCacheRepo_RevokeSession_Call {
 	_c.Call.Return(_a0)
 	return _c
 }
 
-func (_c *MockCacheRepo_Set_Call) RunAndReturn(run func(context.Context, string, any, time.Duration) error) *MockCacheRepo_Set_Call {
+func (_c *MockCacheRepo_RevokeSession_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Duration) error) *MockCacheRepo_RevokeSession_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeToken provides a mock function with given fields: ctx, jti, expiration
+func (_m *MockCacheRepo) RevokeToken(ctx context.Context, jti string, expiration time.Duration) error {
+	ret := _m.Called(ctx, jti, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeToken")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, jti, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_RevokeToken_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeToken'
+type MockCacheRepo_RevokeToken_Call struct {
+	*mock.Call
+}
+
+// RevokeToken is a helper method to define mock.On call
+//   - ctx context.Context
+//   - jti string
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) RevokeToken(ctx interface{}, jti interface{}, expiration interface{}) *MockCacheRepo_RevokeToken_Call {
+	return &MockCacheRepo_RevokeToken_Call{Call: _e.mock.On("RevokeToken", ctx, jti, expiration)}
+}
+
+func (_c *MockCacheRepo_RevokeToken_Call) Run(run func(ctx context.Context, jti string, expiration time.Duration)) *MockCacheRepo_RevokeToken_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_RevokeToken_Call) Return(_a0 error) *MockCacheRepo_RevokeToken_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_RevokeToken_Call) RunAndReturn(run func(context.Context, string, time.Duration) error) *MockCacheRepo_RevokeToken_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeUserTokens provides a mock function with given fields: ctx, userID, revokedAt, keep, expiration
+func (_m *MockCacheRepo) RevokeUserTokens(ctx context.Context, userID uuid.UUID, revokedAt time.Time, keep uuid.UUID, expiration time.Duration) error {
+	ret := _m.Called(ctx, userID, revokedAt, keep, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeUserTokens")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, uuid.UUID, time.Duration) error); ok {
+		r0 = rf(ctx, userID, revokedAt, keep, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_RevokeUserTokens_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeUserTokens'
+type MockCacheRepo_RevokeUserTokens_Call struct {
+	*mock.Call
+}
+
+// RevokeUserTokens is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - revokedAt time.Time
+//   - keep uuid.UUID
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) RevokeUserTokens(ctx interface{}, userID interface{}, revokedAt interface{}, keep interface{}, expiration interface{}) *MockCacheRepo_RevokeUserTokens_Call {
+	return &MockCacheRepo_RevokeUserTokens_Call{Call: _e.mock.On("RevokeUserTokens", ctx, userID, revokedAt, keep, expiration)}
+}
+
+func (_c *MockCacheRepo_RevokeUserTokens_Call) Run(run func(ctx context.Context, userID uuid.UUID, revokedAt time.Time, keep uuid.UUID, expiration time.Duration)) *MockCacheRepo_RevokeUserTokens_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time), args[3].(uuid.UUID), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_RevokeUserTokens_Call) Return(_a0 error) *MockCacheRepo_RevokeUserTokens_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_RevokeUserTokens_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, uuid.UUID, time.Duration) error) *MockCacheRepo_RevokeUserTokens_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetMFAChallenge provides a mock function with given fields: ctx, id, challenge, expiration
+func (_m *MockCacheRepo) SetMFAChallenge(ctx context.Context, id string, challenge []byte, expiration time.Duration) error {
+	ret := _m.Called(ctx, id, challenge, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMFAChallenge")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, time.Duration) error); ok {
+		r0 = rf(ctx, id, challenge, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_SetMFAChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetMFAChallenge'
+type MockCacheRepo_SetMFAChallenge_Call struct {
+	*mock.Call
+}
+
+// SetMFAChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+//   - challenge []byte
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) SetMFAChallenge(ctx interface{}, id interface{}, challenge interface{}, expiration interface{}) *MockCacheRepo_SetMFAChallenge_Call {
+	return &MockCacheRepo_SetMFAChallenge_Call{Call: _e.mock.On("SetMFAChallenge", ctx, id, challenge, expiration)}
+}
+
+func (_c *MockCacheRepo_SetMFAChallenge_Call) Run(run func(ctx context.Context, id string, challenge []byte, expiration time.Duration)) *MockCacheRepo_SetMFAChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_SetMFAChallenge_Call) Return(_a0 error) *MockCacheRepo_SetMFAChallenge_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_SetMFAChallenge_Call) RunAndReturn(run func(context.Context, string, []byte, time.Duration) error) *MockCacheRepo_SetMFAChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPasskeyLogin provides a mock function with given fields: ctx, challengeID, session, expiration
+func (_m *MockCacheRepo) SetPasskeyLogin(ctx context.Context, challengeID string, session []byte, expiration time.Duration) error {
+	ret := _m.Called(ctx, challengeID, session, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPasskeyLogin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []byte, time.Duration) error); ok {
+		r0 = rf(ctx, challengeID, session, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_SetPasskeyLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPasskeyLogin'
+type MockCacheRepo_SetPasskeyLogin_Call struct {
+	*mock.Call
+}
+
+// SetPasskeyLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challengeID string
+//   - session []byte
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) SetPasskeyLogin(ctx interface{}, challengeID interface{}, session interface{}, expiration interface{}) *MockCacheRepo_SetPasskeyLogin_Call {
+	return &MockCacheRepo_SetPasskeyLogin_Call{Call: _e.mock.On("SetPasskeyLogin", ctx, challengeID, session, expiration)}
+}
+
+func (_c *MockCacheRepo_SetPasskeyLogin_Call) Run(run func(ctx context.Context, challengeID string, session []byte, expiration time.Duration)) *MockCacheRepo_SetPasskeyLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].([]byte), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_SetPasskeyLogin_Call) Return(_a0 error) *MockCacheRepo_SetPasskeyLogin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_SetPasskeyLogin_Call) RunAndReturn(run func(context.Context, string, []byte, time.Duration) error) *MockCacheRepo_SetPasskeyLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetPasskeyRegistration provides a mock function with given fields: ctx, userID, session, expiration
+func (_m *MockCacheRepo) SetPasskeyRegistration(ctx context.Context, userID uuid.UUID, session []byte, expiration time.Duration) error {
+	ret := _m.Called(ctx, userID, session, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetPasskeyRegistration")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte, time.Duration) error); ok {
+		r0 = rf(ctx, userID, session, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_SetPasskeyRegistration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetPasskeyRegistration'
+type MockCacheRepo_SetPasskeyRegistration_Call struct {
+	*mock.Call
+}
+
+// SetPasskeyRegistration is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - session []byte
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) SetPasskeyRegistration(ctx interface{}, userID interface{}, session interface{}, expiration interface{}) *MockCacheRepo_SetPasskeyRegistration_Call {
+	return &MockCacheRepo_SetPasskeyRegistration_Call{Call: _e.mock.On("SetPasskeyRegistration", ctx, userID, session, expiration)}
+}
+
+func (_c *MockCacheRepo_SetPasskeyRegistration_Call) Run(run func(ctx context.Context, userID uuid.UUID, session []byte, expiration time.Duration)) *MockCacheRepo_SetPasskeyRegistration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].([]byte), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_SetPasskeyRegistration_Call) Return(_a0 error) *MockCacheRepo_SetPasskeyRegistration_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_SetPasskeyRegistration_Call) RunAndReturn(run func(context.Context, uuid.UUID, []byte, time.Duration) error) *MockCacheRepo_SetPasskeyRegistration_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserAdmin provides a mock function with given fields: ctx, userID, isAdmin, version, expiration
+func (_m *MockCacheRepo) SetUserAdmin(ctx context.Context, userID uuid.UUID, isAdmin bool, version int64, expiration time.Duration) error {
+	ret := _m.Called(ctx, userID, isAdmin, version, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserAdmin")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, bool, int64, time.Duration) error); ok {
+		r0 = rf(ctx, userID, isAdmin, version, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_SetUserAdmin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserAdmin'
+type MockCacheRepo_SetUserAdmin_Call struct {
+	*mock.Call
+}
+
+// SetUserAdmin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - isAdmin bool
+//   - version int64
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) SetUserAdmin(ctx interface{}, userID interface{}, isAdmin interface{}, version interface{}, expiration interface{}) *MockCacheRepo_SetUserAdmin_Call {
+	return &MockCacheRepo_SetUserAdmin_Call{Call: _e.mock.On("SetUserAdmin", ctx, userID, isAdmin, version, expiration)}
+}
+
+func (_c *MockCacheRepo_SetUserAdmin_Call) Run(run func(ctx context.Context, userID uuid.UUID, isAdmin bool, version int64, expiration time.Duration)) *MockCacheRepo_SetUserAdmin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(bool), args[3].(int64), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_SetUserAdmin_Call) Return(_a0 error) *MockCacheRepo_SetUserAdmin_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_SetUserAdmin_Call) RunAndReturn(run func(context.Context, uuid.UUID, bool, int64, time.Duration) error) *MockCacheRepo_SetUserAdmin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetUserStatus provides a mock function with given fields: ctx, userID, status, version, expiration
+func (_m *MockCacheRepo) SetUserStatus(ctx context.Context, userID uuid.UUID, status userModel.Status, version int64, expiration time.Duration) error {
+	ret := _m.Called(ctx, userID, status, version, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetUserStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, userModel.Status, int64, time.Duration) error); ok {
+		r0 = rf(ctx, userID, status, version, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_SetUserStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetUserStatus'
+type MockCacheRepo_SetUserStatus_Call struct {
+	*mock.Call
+}
+
+// SetUserStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - status userModel.Status
+//   - version int64
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) SetUserStatus(ctx interface{}, userID interface{}, status interface{}, version interface{}, expiration interface{}) *MockCacheRepo_SetUserStatus_Call {
+	return &MockCacheRepo_SetUserStatus_Call{Call: _e.mock.On("SetUserStatus", ctx, userID, status, version, expiration)}
+}
+
+func (_c *MockCacheRepo_SetUserStatus_Call) Run(run func(ctx context.Context, userID uuid.UUID, status userModel.Status, version int64, expiration time.Duration)) *MockCacheRepo_SetUserStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(userModel.Status), args[3].(int64), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_SetUserStatus_Call) Return(_a0 error) *MockCacheRepo_SetUserStatus_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_SetUserStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID, userModel.Status, int64, time.Duration) error) *MockCacheRepo_SetUserStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TakeMFAChallenge provides a mock function with given fields: ctx, id
+func (_m *MockCacheRepo) TakeMFAChallenge(ctx context.Context, id string) ([]byte, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakeMFAChallenge")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCacheRepo_TakeMFAChallenge_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakeMFAChallenge'
+type MockCacheRepo_TakeMFAChallenge_Call struct {
+	*mock.Call
+}
+
+// TakeMFAChallenge is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id string
+func (_e *MockCacheRepo_Expecter) TakeMFAChallenge(ctx interface{}, id interface{}) *MockCacheRepo_TakeMFAChallenge_Call {
+	return &MockCacheRepo_TakeMFAChallenge_Call{Call: _e.mock.On("TakeMFAChallenge", ctx, id)}
+}
+
+func (_c *MockCacheRepo_TakeMFAChallenge_Call) Run(run func(ctx context.Context, id string)) *MockCacheRepo_TakeMFAChallenge_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_TakeMFAChallenge_Call) Return(_a0 []byte, _a1 error) *MockCacheRepo_TakeMFAChallenge_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCacheRepo_TakeMFAChallenge_Call) RunAndReturn(run func(context.Context, string) ([]byte, error)) *MockCacheRepo_TakeMFAChallenge_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TakePasskeyLogin provides a mock function with given fields: ctx, challengeID
+func (_m *MockCacheRepo) TakePasskeyLogin(ctx context.Context, challengeID string) ([]byte, error) {
+	ret := _m.Called(ctx, challengeID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakePasskeyLogin")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, challengeID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, challengeID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, challengeID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCacheRepo_TakePasskeyLogin_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakePasskeyLogin'
+type MockCacheRepo_TakePasskeyLogin_Call struct {
+	*mock.Call
+}
+
+// TakePasskeyLogin is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challengeID string
+func (_e *MockCacheRepo_Expecter) TakePasskeyLogin(ctx interface{}, challengeID interface{}) *MockCacheRepo_TakePasskeyLogin_Call {
+	return &MockCacheRepo_TakePasskeyLogin_Call{Call: _e.mock.On("TakePasskeyLogin", ctx, challengeID)}
+}
+
+func (_c *MockCacheRepo_TakePasskeyLogin_Call) Run(run func(ctx context.Context, challengeID string)) *MockCacheRepo_TakePasskeyLogin_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_TakePasskeyLogin_Call) Return(_a0 []byte, _a1 error) *MockCacheRepo_TakePasskeyLogin_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCacheRepo_TakePasskeyLogin_Call) RunAndReturn(run func(context.Context, string) ([]byte, error)) *MockCacheRepo_TakePasskeyLogin_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TakePasskeyRegistration provides a mock function with given fields: ctx, userID
+func (_m *MockCacheRepo) TakePasskeyRegistration(ctx context.Context, userID uuid.UUID) ([]byte, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TakePasskeyRegistration")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]byte, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []byte); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockCacheRepo_TakePasskeyRegistration_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TakePasskeyRegistration'
+type MockCacheRepo_TakePasskeyRegistration_Call struct {
+	*mock.Call
+}
+
+// TakePasskeyRegistration is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockCacheRepo_Expecter) TakePasskeyRegistration(ctx interface{}, userID interface{}) *MockCacheRepo_TakePasskeyRegistration_Call {
+	return &MockCacheRepo_TakePasskeyRegistration_Call{Call: _e.mock.On("TakePasskeyRegistration", ctx, userID)}
+}
+
+func (_c *MockCacheRepo_TakePasskeyRegistration_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockCacheRepo_TakePasskeyRegistration_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_TakePasskeyRegistration_Call) Return(_a0 []byte, _a1 error) *MockCacheRepo_TakePasskeyRegistration_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockCacheRepo_TakePasskeyRegistration_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]byte, error)) *MockCacheRepo_TakePasskeyRegistration_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -0,0 +1,261 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockLoginAttemptRepo is an autogenerated mock type for the LoginAttemptRepo type
+type MockLoginAttemptRepo struct {
+	mock.Mock
+}
+
+type MockLoginAttemptRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockLoginAttemptRepo) EXPECT() *MockLoginAttemptRepo_Expecter {
+	return &MockLoginAttemptRepo_Expecter{mock: &_m.Mock}
+}
+
+// Lock provides a mock function with given fields: ctx, key, duration
+func (_m *MockLoginAttemptRepo) Lock(ctx context.Context, key string, duration time.Duration) error {
+	ret := _m.Called(ctx, key, duration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Lock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, duration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLoginAttemptRepo_Lock_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Lock'
+type MockLoginAttemptRepo_Lock_Call struct {
+	*mock.Call
+}
+
+// Lock is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - duration time.Duration
+func (_e *MockLoginAttemptRepo_Expecter) Lock(ctx interface{}, key interface{}, duration interface{}) *MockLoginAttemptRepo_Lock_Call {
+	return &MockLoginAttemptRepo_Lock_Call{Call: _e.mock.On("Lock", ctx, key, duration)}
+}
+
+func (_c *MockLoginAttemptRepo_Lock_Call) Run(run func(ctx context.Context, key string, duration time.Duration)) *MockLoginAttemptRepo_Lock_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_Lock_Call) Return(_a0 error) *MockLoginAttemptRepo_Lock_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_Lock_Call) RunAndReturn(run func(context.Context, string, time.Duration) error) *MockLoginAttemptRepo_Lock_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// LockedFor provides a mock function with given fields: ctx, keys
+func (_m *MockLoginAttemptRepo) LockedFor(ctx context.Context, keys ...string) (time.Duration, error) {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for LockedFor")
+	}
+
+	var r0 time.Duration
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) (time.Duration, error)); ok {
+		return rf(ctx, keys...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) time.Duration); ok {
+		r0 = rf(ctx, keys...)
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...string) error); ok {
+		r1 = rf(ctx, keys...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLoginAttemptRepo_LockedFor_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'LockedFor'
+type MockLoginAttemptRepo_LockedFor_Call struct {
+	*mock.Call
+}
+
+// LockedFor is a helper method to define mock.On call
+//   - ctx context.Context
+//   - keys ...string
+func (_e *MockLoginAttemptRepo_Expecter) LockedFor(ctx interface{}, keys ...interface{}) *MockLoginAttemptRepo_LockedFor_Call {
+	return &MockLoginAttemptRepo_LockedFor_Call{Call: _e.mock.On("LockedFor",
+		append([]interface{}{ctx}, keys...)...)}
+}
+
+func (_c *MockLoginAttemptRepo_LockedFor_Call) Run(run func(ctx context.Context, keys ...string)) *MockLoginAttemptRepo_LockedFor_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		variadicArgs := make([]string, len(args)-1)
+		for i, a := range args[1:] {
+			if a != nil {
+				variadicArgs[i] = a.(string)
+			}
+		}
+		run(args[0].(context.Context), variadicArgs...)
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_LockedFor_Call) Return(_a0 time.Duration, _a1 error) *MockLoginAttemptRepo_LockedFor_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_LockedFor_Call) RunAndReturn(run func(context.Context, ...string) (time.Duration, error)) *MockLoginAttemptRepo_LockedFor_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RegisterFailure provides a mock function with given fields: ctx, key, window
+func (_m *MockLoginAttemptRepo) RegisterFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	ret := _m.Called(ctx, key, window)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RegisterFailure")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) (int, error)); ok {
+		return rf(ctx, key, window)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Duration) int); ok {
+		r0 = rf(ctx, key, window)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Duration) error); ok {
+		r1 = rf(ctx, key, window)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockLoginAttemptRepo_RegisterFailure_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RegisterFailure'
+type MockLoginAttemptRepo_RegisterFailure_Call struct {
+	*mock.Call
+}
+
+// RegisterFailure is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+//   - window time.Duration
+func (_e *MockLoginAttemptRepo_Expecter) RegisterFailure(ctx interface{}, key interface{}, window interface{}) *MockLoginAttemptRepo_RegisterFailure_Call {
+	return &MockLoginAttemptRepo_RegisterFailure_Call{Call: _e.mock.On("RegisterFailure", ctx, key, window)}
+}
+
+func (_c *MockLoginAttemptRepo_RegisterFailure_Call) Run(run func(ctx context.Context, key string, window time.Duration)) *MockLoginAttemptRepo_RegisterFailure_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_RegisterFailure_Call) Return(_a0 int, _a1 error) *MockLoginAttemptRepo_RegisterFailure_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_RegisterFailure_Call) RunAndReturn(run func(context.Context, string, time.Duration) (int, error)) *MockLoginAttemptRepo_RegisterFailure_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reset provides a mock function with given fields: ctx, key
+func (_m *MockLoginAttemptRepo) Reset(ctx context.Context, key string) error {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockLoginAttemptRepo_Reset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reset'
+type MockLoginAttemptRepo_Reset_Call struct {
+	*mock.Call
+}
+
+// Reset is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key string
+func (_e *MockLoginAttemptRepo_Expecter) Reset(ctx interface{}, key interface{}) *MockLoginAttemptRepo_Reset_Call {
+	return &MockLoginAttemptRepo_Reset_Call{Call: _e.mock.On("Reset", ctx, key)}
+}
+
+func (_c *MockLoginAttemptRepo_Reset_Call) Run(run func(ctx context.Context, key string)) *MockLoginAttemptRepo_Reset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_Reset_Call) Return(_a0 error) *MockLoginAttemptRepo_Reset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockLoginAttemptRepo_Reset_Call) RunAndReturn(run func(context.Context, string) error) *MockLoginAttemptRepo_Reset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockLoginAttemptRepo creates a new instance of MockLoginAttemptRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockLoginAttemptRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockLoginAttemptRepo {
+	mock := &MockLoginAttemptRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
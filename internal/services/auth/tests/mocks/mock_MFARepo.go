@@ -0,0 +1,338 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mfaModel "github.com/Tbits007/auth/internal/domain/models/mfaModel"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockMFARepo is an autogenerated mock type for the MFARepo type
+type MockMFARepo struct {
+	mock.Mock
+}
+
+type MockMFARepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockMFARepo) EXPECT() *MockMFARepo_Expecter {
+	return &MockMFARepo_Expecter{mock: &_m.Mock}
+}
+
+// ConfirmTOTP provides a mock function with given fields: ctx, userID, step
+func (_m *MockMFARepo) ConfirmTOTP(ctx context.Context, userID uuid.UUID, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ConfirmTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepo_ConfirmTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ConfirmTOTP'
+type MockMFARepo_ConfirmTOTP_Call struct {
+	*mock.Call
+}
+
+// ConfirmTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - step int64
+func (_e *MockMFARepo_Expecter) ConfirmTOTP(ctx interface{}, userID interface{}, step interface{}) *MockMFARepo_ConfirmTOTP_Call {
+	return &MockMFARepo_ConfirmTOTP_Call{Call: _e.mock.On("ConfirmTOTP", ctx, userID, step)}
+}
+
+func (_c *MockMFARepo_ConfirmTOTP_Call) Run(run func(ctx context.Context, userID uuid.UUID, step int64)) *MockMFARepo_ConfirmTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockMFARepo_ConfirmTOTP_Call) Return(_a0 error) *MockMFARepo_ConfirmTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepo_ConfirmTOTP_Call) RunAndReturn(run func(context.Context, uuid.UUID, int64) error) *MockMFARepo_ConfirmTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetTOTP provides a mock function with given fields: ctx, userID
+func (_m *MockMFARepo) GetTOTP(ctx context.Context, userID uuid.UUID) (*mfaModel.TOTP, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTOTP")
+	}
+
+	var r0 *mfaModel.TOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*mfaModel.TOTP, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *mfaModel.TOTP); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*mfaModel.TOTP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockMFARepo_GetTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetTOTP'
+type MockMFARepo_GetTOTP_Call struct {
+	*mock.Call
+}
+
+// GetTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockMFARepo_Expecter) GetTOTP(ctx interface{}, userID interface{}) *MockMFARepo_GetTOTP_Call {
+	return &MockMFARepo_GetTOTP_Call{Call: _e.mock.On("GetTOTP", ctx, userID)}
+}
+
+func (_c *MockMFARepo_GetTOTP_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockMFARepo_GetTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockMFARepo_GetTOTP_Call) Return(_a0 *mfaModel.TOTP, _a1 error) *MockMFARepo_GetTOTP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockMFARepo_GetTOTP_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*mfaModel.TOTP, error)) *MockMFARepo_GetTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReplaceRecoveryCodes provides a mock function with given fields: ctx, userID, codeHashes
+func (_m *MockMFARepo) ReplaceRecoveryCodes(ctx context.Context, userID uuid.UUID, codeHashes []string) error {
+	ret := _m.Called(ctx, userID, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceRecoveryCodes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []string) error); ok {
+		r0 = rf(ctx, userID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepo_ReplaceRecoveryCodes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplaceRecoveryCodes'
+type MockMFARepo_ReplaceRecoveryCodes_Call struct {
+	*mock.Call
+}
+
+// ReplaceRecoveryCodes is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - codeHashes []string
+func (_e *MockMFARepo_Expecter) ReplaceRecoveryCodes(ctx interface{}, userID interface{}, codeHashes interface{}) *MockMFARepo_ReplaceRecoveryCodes_Call {
+	return &MockMFARepo_ReplaceRecoveryCodes_Call{Call: _e.mock.On("ReplaceRecoveryCodes", ctx, userID, codeHashes)}
+}
+
+func (_c *MockMFARepo_ReplaceRecoveryCodes_Call) Run(run func(ctx context.Context, userID uuid.UUID, codeHashes []string)) *MockMFARepo_ReplaceRecoveryCodes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].([]string))
+	})
+	return _c
+}
+
+func (_c *MockMFARepo_ReplaceRecoveryCodes_Call) Return(_a0 error) *MockMFARepo_ReplaceRecoveryCodes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepo_ReplaceRecoveryCodes_Call) RunAndReturn(run func(context.Context, uuid.UUID, []string) error) *MockMFARepo_ReplaceRecoveryCodes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SaveTOTP provides a mock function with given fields: ctx, userID, encryptedSecret
+func (_m *MockMFARepo) SaveTOTP(ctx context.Context, userID uuid.UUID, encryptedSecret []byte) error {
+	ret := _m.Called(ctx, userID, encryptedSecret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTOTP")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []byte) error); ok {
+		r0 = rf(ctx, userID, encryptedSecret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepo_SaveTOTP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveTOTP'
+type MockMFARepo_SaveTOTP_Call struct {
+	*mock.Call
+}
+
+// SaveTOTP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - encryptedSecret []byte
+func (_e *MockMFARepo_Expecter) SaveTOTP(ctx interface{}, userID interface{}, encryptedSecret interface{}) *MockMFARepo_SaveTOTP_Call {
+	return &MockMFARepo_SaveTOTP_Call{Call: _e.mock.On("SaveTOTP", ctx, userID, encryptedSecret)}
+}
+
+func (_c *MockMFARepo_SaveTOTP_Call) Run(run func(ctx context.Context, userID uuid.UUID, encryptedSecret []byte)) *MockMFARepo_SaveTOTP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].([]byte))
+	})
+	return _c
+}
+
+func (_c *MockMFARepo_SaveTOTP_Call) Return(_a0 error) *MockMFARepo_SaveTOTP_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepo_SaveTOTP_Call) RunAndReturn(run func(context.Context, uuid.UUID, []byte) error) *MockMFARepo_SaveTOTP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, userID, codeHash
+func (_m *MockMFARepo) UseRecoveryCode(ctx context.Context, userID uuid.UUID, codeHash string) error {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseRecoveryCode")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepo_UseRecoveryCode_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseRecoveryCode'
+type MockMFARepo_UseRecoveryCode_Call struct {
+	*mock.Call
+}
+
+// UseRecoveryCode is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - codeHash string
+func (_e *MockMFARepo_Expecter) UseRecoveryCode(ctx interface{}, userID interface{}, codeHash interface{}) *MockMFARepo_UseRecoveryCode_Call {
+	return &MockMFARepo_UseRecoveryCode_Call{Call: _e.mock.On("UseRecoveryCode", ctx, userID, codeHash)}
+}
+
+func (_c *MockMFARepo_UseRecoveryCode_Call) Run(run func(ctx context.Context, userID uuid.UUID, codeHash string)) *MockMFARepo_UseRecoveryCode_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockMFARepo_UseRecoveryCode_Call) Return(_a0 error) *MockMFARepo_UseRecoveryCode_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepo_UseRecoveryCode_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockMFARepo_UseRecoveryCode_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UseTOTPStep provides a mock function with given fields: ctx, userID, step
+func (_m *MockMFARepo) UseTOTPStep(ctx context.Context, userID uuid.UUID, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseTOTPStep")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockMFARepo_UseTOTPStep_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UseTOTPStep'
+type MockMFARepo_UseTOTPStep_Call struct {
+	*mock.Call
+}
+
+// UseTOTPStep is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - step int64
+func (_e *MockMFARepo_Expecter) UseTOTPStep(ctx interface{}, userID interface{}, step interface{}) *MockMFARepo_UseTOTPStep_Call {
+	return &MockMFARepo_UseTOTPStep_Call{Call: _e.mock.On("UseTOTPStep", ctx, userID, step)}
+}
+
+func (_c *MockMFARepo_UseTOTPStep_Call) Run(run func(ctx context.Context, userID uuid.UUID, step int64)) *MockMFARepo_UseTOTPStep_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(int64))
+	})
+	return _c
+}
+
+func (_c *MockMFARepo_UseTOTPStep_Call) Return(_a0 error) *MockMFARepo_UseTOTPStep_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockMFARepo_UseTOTPStep_Call) RunAndReturn(run func(context.Context, uuid.UUID, int64) error) *MockMFARepo_UseTOTPStep_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockMFARepo creates a new instance of MockMFARepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockMFARepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockMFARepo {
+	mock := &MockMFARepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
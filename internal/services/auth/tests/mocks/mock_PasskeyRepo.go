@@ -0,0 +1,264 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	passkeyModel "github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockPasskeyRepo is an autogenerated mock type for the PasskeyRepo type
+type MockPasskeyRepo struct {
+	mock.Mock
+}
+
+type MockPasskeyRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPasskeyRepo) EXPECT() *MockPasskeyRepo_Expecter {
+	return &MockPasskeyRepo_Expecter{mock: &_m.Mock}
+}
+
+// GetByCredentialID provides a mock function with given fields: ctx, credentialID
+func (_m *MockPasskeyRepo) GetByCredentialID(ctx context.Context, credentialID []byte) (*passkeyModel.Credential, error) {
+	ret := _m.Called(ctx, credentialID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByCredentialID")
+	}
+
+	var r0 *passkeyModel.Credential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (*passkeyModel.Credential, error)); ok {
+		return rf(ctx, credentialID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) *passkeyModel.Credential); ok {
+		r0 = rf(ctx, credentialID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*passkeyModel.Credential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, credentialID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPasskeyRepo_GetByCredentialID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByCredentialID'
+type MockPasskeyRepo_GetByCredentialID_Call struct {
+	*mock.Call
+}
+
+// GetByCredentialID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - credentialID []byte
+func (_e *MockPasskeyRepo_Expecter) GetByCredentialID(ctx interface{}, credentialID interface{}) *MockPasskeyRepo_GetByCredentialID_Call {
+	return &MockPasskeyRepo_GetByCredentialID_Call{Call: _e.mock.On("GetByCredentialID", ctx, credentialID)}
+}
+
+func (_c *MockPasskeyRepo_GetByCredentialID_Call) Run(run func(ctx context.Context, credentialID []byte)) *MockPasskeyRepo_GetByCredentialID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]byte))
+	})
+	return _c
+}
+
+func (_c *MockPasskeyRepo_GetByCredentialID_Call) Return(_a0 *passkeyModel.Credential, _a1 error) *MockPasskeyRepo_GetByCredentialID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPasskeyRepo_GetByCredentialID_Call) RunAndReturn(run func(context.Context, []byte) (*passkeyModel.Credential, error)) *MockPasskeyRepo_GetByCredentialID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListByUser provides a mock function with given fields: ctx, userID
+func (_m *MockPasskeyRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]passkeyModel.Credential, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []passkeyModel.Credential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]passkeyModel.Credential, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []passkeyModel.Credential); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]passkeyModel.Credential)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPasskeyRepo_ListByUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListByUser'
+type MockPasskeyRepo_ListByUser_Call struct {
+	*mock.Call
+}
+
+// ListByUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockPasskeyRepo_Expecter) ListByUser(ctx interface{}, userID interface{}) *MockPasskeyRepo_ListByUser_Call {
+	return &MockPasskeyRepo_ListByUser_Call{Call: _e.mock.On("ListByUser", ctx, userID)}
+}
+
+func (_c *MockPasskeyRepo_ListByUser_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockPasskeyRepo_ListByUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockPasskeyRepo_ListByUser_Call) Return(_a0 []passkeyModel.Credential, _a1 error) *MockPasskeyRepo_ListByUser_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPasskeyRepo_ListByUser_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]passkeyModel.Credential, error)) *MockPasskeyRepo_ListByUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, credential
+func (_m *MockPasskeyRepo) Save(ctx context.Context, credential passkeyModel.Credential) (uuid.UUID, error) {
+	ret := _m.Called(ctx, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, passkeyModel.Credential) (uuid.UUID, error)); ok {
+		return rf(ctx, credential)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, passkeyModel.Credential) uuid.UUID); ok {
+		r0 = rf(ctx, credential)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, passkeyModel.Credential) error); ok {
+		r1 = rf(ctx, credential)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockPasskeyRepo_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockPasskeyRepo_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - credential passkeyModel.Credential
+func (_e *MockPasskeyRepo_Expecter) Save(ctx interface{}, credential interface{}) *MockPasskeyRepo_Save_Call {
+	return &MockPasskeyRepo_Save_Call{Call: _e.mock.On("Save", ctx, credential)}
+}
+
+func (_c *MockPasskeyRepo_Save_Call) Run(run func(ctx context.Context, credential passkeyModel.Credential)) *MockPasskeyRepo_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(passkeyModel.Credential))
+	})
+	return _c
+}
+
+func (_c *MockPasskeyRepo_Save_Call) Return(_a0 uuid.UUID, _a1 error) *MockPasskeyRepo_Save_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockPasskeyRepo_Save_Call) RunAndReturn(run func(context.Context, passkeyModel.Credential) (uuid.UUID, error)) *MockPasskeyRepo_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdateSignCount provides a mock function with given fields: ctx, credentialID, signCount
+func (_m *MockPasskeyRepo) UpdateSignCount(ctx context.Context, credentialID []byte, signCount uint32) error {
+	ret := _m.Called(ctx, credentialID, signCount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSignCount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, uint32) error); ok {
+		r0 = rf(ctx, credentialID, signCount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPasskeyRepo_UpdateSignCount_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateSignCount'
+type MockPasskeyRepo_UpdateSignCount_Call struct {
+	*mock.Call
+}
+
+// UpdateSignCount is a helper method to define mock.On call
+//   - ctx context.Context
+//   - credentialID []byte
+//   - signCount uint32
+func (_e *MockPasskeyRepo_Expecter) UpdateSignCount(ctx interface{}, credentialID interface{}, signCount interface{}) *MockPasskeyRepo_UpdateSignCount_Call {
+	return &MockPasskeyRepo_UpdateSignCount_Call{Call: _e.mock.On("UpdateSignCount", ctx, credentialID, signCount)}
+}
+
+func (_c *MockPasskeyRepo_UpdateSignCount_Call) Run(run func(ctx context.Context, credentialID []byte, signCount uint32)) *MockPasskeyRepo_UpdateSignCount_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]byte), args[2].(uint32))
+	})
+	return _c
+}
+
+func (_c *MockPasskeyRepo_UpdateSignCount_Call) Return(_a0 error) *MockPasskeyRepo_UpdateSignCount_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPasskeyRepo_UpdateSignCount_Call) RunAndReturn(run func(context.Context, []byte, uint32) error) *MockPasskeyRepo_UpdateSignCount_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPasskeyRepo creates a new instance of MockPasskeyRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPasskeyRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPasskeyRepo {
+	mock := &MockPasskeyRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
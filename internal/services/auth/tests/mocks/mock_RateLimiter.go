@@ -0,0 +1,135 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	time "time"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRateLimiter is an autogenerated mock type for the RateLimiter type
+type MockRateLimiter struct {
+	mock.Mock
+}
+
+type MockRateLimiter_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRateLimiter) EXPECT() *MockRateLimiter_Expecter {
+	return &MockRateLimiter_Expecter{mock: &_m.Mock}
+}
+
+// AllowMFAAttempt provides a mock function with given fields: ctx, challengeID, limit, period
+func (_m *MockRateLimiter) AllowMFAAttempt(ctx context.Context, challengeID string, limit int, period time.Duration) error {
+	ret := _m.Called(ctx, challengeID, limit, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllowMFAAttempt")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Duration) error); ok {
+		r0 = rf(ctx, challengeID, limit, period)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRateLimiter_AllowMFAAttempt_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AllowMFAAttempt'
+type MockRateLimiter_AllowMFAAttempt_Call struct {
+	*mock.Call
+}
+
+// AllowMFAAttempt is a helper method to define mock.On call
+//   - ctx context.Context
+//   - challengeID string
+//   - limit int
+//   - period time.Duration
+func (_e *MockRateLimiter_Expecter) AllowMFAAttempt(ctx interface{}, challengeID interface{}, limit interface{}, period interface{}) *MockRateLimiter_AllowMFAAttempt_Call {
+	return &MockRateLimiter_AllowMFAAttempt_Call{Call: _e.mock.On("AllowMFAAttempt", ctx, challengeID, limit, period)}
+}
+
+func (_c *MockRateLimiter_AllowMFAAttempt_Call) Run(run func(ctx context.Context, challengeID string, limit int, period time.Duration)) *MockRateLimiter_AllowMFAAttempt_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockRateLimiter_AllowMFAAttempt_Call) Return(_a0 error) *MockRateLimiter_AllowMFAAttempt_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRateLimiter_AllowMFAAttempt_Call) RunAndReturn(run func(context.Context, string, int, time.Duration) error) *MockRateLimiter_AllowMFAAttempt_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AllowVerificationResend provides a mock function with given fields: ctx, email, limit, period
+func (_m *MockRateLimiter) AllowVerificationResend(ctx context.Context, email string, limit int, period time.Duration) error {
+	ret := _m.Called(ctx, email, limit, period)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AllowVerificationResend")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, time.Duration) error); ok {
+		r0 = rf(ctx, email, limit, period)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRateLimiter_AllowVerificationResend_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AllowVerificationResend'
+type MockRateLimiter_AllowVerificationResend_Call struct {
+	*mock.Call
+}
+
+// AllowVerificationResend is a helper method to define mock.On call
+//   - ctx context.Context
+//   - email string
+//   - limit int
+//   - period time.Duration
+func (_e *MockRateLimiter_Expecter) AllowVerificationResend(ctx interface{}, email interface{}, limit interface{}, period interface{}) *MockRateLimiter_AllowVerificationResend_Call {
+	return &MockRateLimiter_AllowVerificationResend_Call{Call: _e.mock.On("AllowVerificationResend", ctx, email, limit, period)}
+}
+
+func (_c *MockRateLimiter_AllowVerificationResend_Call) Run(run func(ctx context.Context, email string, limit int, period time.Duration)) *MockRateLimiter_AllowVerificationResend_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(int), args[3].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockRateLimiter_AllowVerificationResend_Call) Return(_a0 error) *MockRateLimiter_AllowVerificationResend_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRateLimiter_AllowVerificationResend_Call) RunAndReturn(run func(context.Context, string, int, time.Duration) error) *MockRateLimiter_AllowVerificationResend_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRateLimiter creates a new instance of MockRateLimiter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRateLimiter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRateLimiter {
+	mock := &MockRateLimiter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
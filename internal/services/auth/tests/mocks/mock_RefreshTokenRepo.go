@@ -0,0 +1,298 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	tokenModel "github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockRefreshTokenRepo is an autogenerated mock type for the RefreshTokenRepo type
+type MockRefreshTokenRepo struct {
+	mock.Mock
+}
+
+type MockRefreshTokenRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRefreshTokenRepo) EXPECT() *MockRefreshTokenRepo_Expecter {
+	return &MockRefreshTokenRepo_Expecter{mock: &_m.Mock}
+}
+
+// GetByHash provides a mock function with given fields: ctx, tokenHash
+func (_m *MockRefreshTokenRepo) GetByHash(ctx context.Context, tokenHash string) (*tokenModel.RefreshToken, error) {
+	ret := _m.Called(ctx, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByHash")
+	}
+
+	var r0 *tokenModel.RefreshToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*tokenModel.RefreshToken, error)); ok {
+		return rf(ctx, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *tokenModel.RefreshToken); ok {
+		r0 = rf(ctx, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tokenModel.RefreshToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRefreshTokenRepo_GetByHash_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByHash'
+type MockRefreshTokenRepo_GetByHash_Call struct {
+	*mock.Call
+}
+
+// GetByHash is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenHash string
+func (_e *MockRefreshTokenRepo_Expecter) GetByHash(ctx interface{}, tokenHash interface{}) *MockRefreshTokenRepo_GetByHash_Call {
+	return &MockRefreshTokenRepo_GetByHash_Call{Call: _e.mock.On("GetByHash", ctx, tokenHash)}
+}
+
+func (_c *MockRefreshTokenRepo_GetByHash_Call) Run(run func(ctx context.Context, tokenHash string)) *MockRefreshTokenRepo_GetByHash_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_GetByHash_Call) Return(_a0 *tokenModel.RefreshToken, _a1 error) *MockRefreshTokenRepo_GetByHash_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_GetByHash_Call) RunAndReturn(run func(context.Context, string) (*tokenModel.RefreshToken, error)) *MockRefreshTokenRepo_GetByHash_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkUsed provides a mock function with given fields: ctx, tokenID
+func (_m *MockRefreshTokenRepo) MarkUsed(ctx context.Context, tokenID uuid.UUID) error {
+	ret := _m.Called(ctx, tokenID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkUsed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, tokenID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRefreshTokenRepo_MarkUsed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkUsed'
+type MockRefreshTokenRepo_MarkUsed_Call struct {
+	*mock.Call
+}
+
+// MarkUsed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tokenID uuid.UUID
+func (_e *MockRefreshTokenRepo_Expecter) MarkUsed(ctx interface{}, tokenID interface{}) *MockRefreshTokenRepo_MarkUsed_Call {
+	return &MockRefreshTokenRepo_MarkUsed_Call{Call: _e.mock.On("MarkUsed", ctx, tokenID)}
+}
+
+func (_c *MockRefreshTokenRepo_MarkUsed_Call) Run(run func(ctx context.Context, tokenID uuid.UUID)) *MockRefreshTokenRepo_MarkUsed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_MarkUsed_Call) Return(_a0 error) *MockRefreshTokenRepo_MarkUsed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_MarkUsed_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockRefreshTokenRepo_MarkUsed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAllForUser provides a mock function with given fields: ctx, userID
+func (_m *MockRefreshTokenRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRefreshTokenRepo_RevokeAllForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAllForUser'
+type MockRefreshTokenRepo_RevokeAllForUser_Call struct {
+	*mock.Call
+}
+
+// RevokeAllForUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockRefreshTokenRepo_Expecter) RevokeAllForUser(ctx interface{}, userID interface{}) *MockRefreshTokenRepo_RevokeAllForUser_Call {
+	return &MockRefreshTokenRepo_RevokeAllForUser_Call{Call: _e.mock.On("RevokeAllForUser", ctx, userID)}
+}
+
+func (_c *MockRefreshTokenRepo_RevokeAllForUser_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockRefreshTokenRepo_RevokeAllForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_RevokeAllForUser_Call) Return(_a0 error) *MockRefreshTokenRepo_RevokeAllForUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_RevokeAllForUser_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockRefreshTokenRepo_RevokeAllForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeFamily provides a mock function with given fields: ctx, familyID
+func (_m *MockRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeFamily")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRefreshTokenRepo_RevokeFamily_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeFamily'
+type MockRefreshTokenRepo_RevokeFamily_Call struct {
+	*mock.Call
+}
+
+// RevokeFamily is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyID uuid.UUID
+func (_e *MockRefreshTokenRepo_Expecter) RevokeFamily(ctx interface{}, familyID interface{}) *MockRefreshTokenRepo_RevokeFamily_Call {
+	return &MockRefreshTokenRepo_RevokeFamily_Call{Call: _e.mock.On("RevokeFamily", ctx, familyID)}
+}
+
+func (_c *MockRefreshTokenRepo_RevokeFamily_Call) Run(run func(ctx context.Context, familyID uuid.UUID)) *MockRefreshTokenRepo_RevokeFamily_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_RevokeFamily_Call) Return(_a0 error) *MockRefreshTokenRepo_RevokeFamily_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_RevokeFamily_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockRefreshTokenRepo_RevokeFamily_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, token
+func (_m *MockRefreshTokenRepo) Save(ctx context.Context, token tokenModel.RefreshToken) (uuid.UUID, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, tokenModel.RefreshToken) (uuid.UUID, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, tokenModel.RefreshToken) uuid.UUID); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, tokenModel.RefreshToken) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRefreshTokenRepo_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockRefreshTokenRepo_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token tokenModel.RefreshToken
+func (_e *MockRefreshTokenRepo_Expecter) Save(ctx interface{}, token interface{}) *MockRefreshTokenRepo_Save_Call {
+	return &MockRefreshTokenRepo_Save_Call{Call: _e.mock.On("Save", ctx, token)}
+}
+
+func (_c *MockRefreshTokenRepo_Save_Call) Run(run func(ctx context.Context, token tokenModel.RefreshToken)) *MockRefreshTokenRepo_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tokenModel.RefreshToken))
+	})
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_Save_Call) Return(_a0 uuid.UUID, _a1 error) *MockRefreshTokenRepo_Save_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRefreshTokenRepo_Save_Call) RunAndReturn(run func(context.Context, tokenModel.RefreshToken) (uuid.UUID, error)) *MockRefreshTokenRepo_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRefreshTokenRepo creates a new instance of MockRefreshTokenRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRefreshTokenRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRefreshTokenRepo {
+	mock := &MockRefreshTokenRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,385 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	sessionModel "github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockSessionRepo is an autogenerated mock type for the SessionRepo type
+type MockSessionRepo struct {
+	mock.Mock
+}
+
+type MockSessionRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSessionRepo) EXPECT() *MockSessionRepo_Expecter {
+	return &MockSessionRepo_Expecter{mock: &_m.Mock}
+}
+
+// ListActive provides a mock function with given fields: ctx, userID
+func (_m *MockSessionRepo) ListActive(ctx context.Context, userID uuid.UUID) ([]sessionModel.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListActive")
+	}
+
+	var r0 []sessionModel.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]sessionModel.Session, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []sessionModel.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]sessionModel.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionRepo_ListActive_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListActive'
+type MockSessionRepo_ListActive_Call struct {
+	*mock.Call
+}
+
+// ListActive is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockSessionRepo_Expecter) ListActive(ctx interface{}, userID interface{}) *MockSessionRepo_ListActive_Call {
+	return &MockSessionRepo_ListActive_Call{Call: _e.mock.On("ListActive", ctx, userID)}
+}
+
+func (_c *MockSessionRepo_ListActive_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockSessionRepo_ListActive_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepo_ListActive_Call) Return(_a0 []sessionModel.Session, _a1 error) *MockSessionRepo_ListActive_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionRepo_ListActive_Call) RunAndReturn(run func(context.Context, uuid.UUID) ([]sessionModel.Session, error)) *MockSessionRepo_ListActive_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function with given fields: ctx, userID, sessionID
+func (_m *MockSessionRepo) Revoke(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID) (*sessionModel.Session, error) {
+	ret := _m.Called(ctx, userID, sessionID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Revoke")
+	}
+
+	var r0 *sessionModel.Session
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) (*sessionModel.Session, error)); ok {
+		return rf(ctx, userID, sessionID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *sessionModel.Session); ok {
+		r0 = rf(ctx, userID, sessionID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*sessionModel.Session)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID, sessionID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionRepo_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type MockSessionRepo_Revoke_Call struct {
+	*mock.Call
+}
+
+// Revoke is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - sessionID uuid.UUID
+func (_e *MockSessionRepo_Expecter) Revoke(ctx interface{}, userID interface{}, sessionID interface{}) *MockSessionRepo_Revoke_Call {
+	return &MockSessionRepo_Revoke_Call{Call: _e.mock.On("Revoke", ctx, userID, sessionID)}
+}
+
+func (_c *MockSessionRepo_Revoke_Call) Run(run func(ctx context.Context, userID uuid.UUID, sessionID uuid.UUID)) *MockSessionRepo_Revoke_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepo_Revoke_Call) Return(_a0 *sessionModel.Session, _a1 error) *MockSessionRepo_Revoke_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionRepo_Revoke_Call) RunAndReturn(run func(context.Context, uuid.UUID, uuid.UUID) (*sessionModel.Session, error)) *MockSessionRepo_Revoke_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAllForUser provides a mock function with given fields: ctx, userID
+func (_m *MockSessionRepo) RevokeAllForUser(ctx context.Context, userID uuid.UUID) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAllForUser")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockSessionRepo_RevokeAllForUser_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAllForUser'
+type MockSessionRepo_RevokeAllForUser_Call struct {
+	*mock.Call
+}
+
+// RevokeAllForUser is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockSessionRepo_Expecter) RevokeAllForUser(ctx interface{}, userID interface{}) *MockSessionRepo_RevokeAllForUser_Call {
+	return &MockSessionRepo_RevokeAllForUser_Call{Call: _e.mock.On("RevokeAllForUser", ctx, userID)}
+}
+
+func (_c *MockSessionRepo_RevokeAllForUser_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockSessionRepo_RevokeAllForUser_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepo_RevokeAllForUser_Call) Return(_a0 error) *MockSessionRepo_RevokeAllForUser_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockSessionRepo_RevokeAllForUser_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockSessionRepo_RevokeAllForUser_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeByFamily provides a mock function with given fields: ctx, familyID
+func (_m *MockSessionRepo) RevokeByFamily(ctx context.Context, familyID uuid.UUID) (uuid.UUID, error) {
+	ret := _m.Called(ctx, familyID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeByFamily")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (uuid.UUID, error)); ok {
+		return rf(ctx, familyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) uuid.UUID); ok {
+		r0 = rf(ctx, familyID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, familyID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionRepo_RevokeByFamily_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeByFamily'
+type MockSessionRepo_RevokeByFamily_Call struct {
+	*mock.Call
+}
+
+// RevokeByFamily is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyID uuid.UUID
+func (_e *MockSessionRepo_Expecter) RevokeByFamily(ctx interface{}, familyID interface{}) *MockSessionRepo_RevokeByFamily_Call {
+	return &MockSessionRepo_RevokeByFamily_Call{Call: _e.mock.On("RevokeByFamily", ctx, familyID)}
+}
+
+func (_c *MockSessionRepo_RevokeByFamily_Call) Run(run func(ctx context.Context, familyID uuid.UUID)) *MockSessionRepo_RevokeByFamily_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepo_RevokeByFamily_Call) Return(_a0 uuid.UUID, _a1 error) *MockSessionRepo_RevokeByFamily_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionRepo_RevokeByFamily_Call) RunAndReturn(run func(context.Context, uuid.UUID) (uuid.UUID, error)) *MockSessionRepo_RevokeByFamily_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, session
+func (_m *MockSessionRepo) Save(ctx context.Context, session sessionModel.Session) (uuid.UUID, error) {
+	ret := _m.Called(ctx, session)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, sessionModel.Session) (uuid.UUID, error)); ok {
+		return rf(ctx, session)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, sessionModel.Session) uuid.UUID); ok {
+		r0 = rf(ctx, session)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, sessionModel.Session) error); ok {
+		r1 = rf(ctx, session)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionRepo_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockSessionRepo_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - session sessionModel.Session
+func (_e *MockSessionRepo_Expecter) Save(ctx interface{}, session interface{}) *MockSessionRepo_Save_Call {
+	return &MockSessionRepo_Save_Call{Call: _e.mock.On("Save", ctx, session)}
+}
+
+func (_c *MockSessionRepo_Save_Call) Run(run func(ctx context.Context, session sessionModel.Session)) *MockSessionRepo_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(sessionModel.Session))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepo_Save_Call) Return(_a0 uuid.UUID, _a1 error) *MockSessionRepo_Save_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionRepo_Save_Call) RunAndReturn(run func(context.Context, sessionModel.Session) (uuid.UUID, error)) *MockSessionRepo_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Touch provides a mock function with given fields: ctx, familyID, expiresAt
+func (_m *MockSessionRepo) Touch(ctx context.Context, familyID uuid.UUID, expiresAt time.Time) (uuid.UUID, error) {
+	ret := _m.Called(ctx, familyID, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Touch")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) (uuid.UUID, error)); ok {
+		return rf(ctx, familyID, expiresAt)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) uuid.UUID); ok {
+		r0 = rf(ctx, familyID, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r1 = rf(ctx, familyID, expiresAt)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockSessionRepo_Touch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Touch'
+type MockSessionRepo_Touch_Call struct {
+	*mock.Call
+}
+
+// Touch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - familyID uuid.UUID
+//   - expiresAt time.Time
+func (_e *MockSessionRepo_Expecter) Touch(ctx interface{}, familyID interface{}, expiresAt interface{}) *MockSessionRepo_Touch_Call {
+	return &MockSessionRepo_Touch_Call{Call: _e.mock.On("Touch", ctx, familyID, expiresAt)}
+}
+
+func (_c *MockSessionRepo_Touch_Call) Run(run func(ctx context.Context, familyID uuid.UUID, expiresAt time.Time)) *MockSessionRepo_Touch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time))
+	})
+	return _c
+}
+
+func (_c *MockSessionRepo_Touch_Call) Return(_a0 uuid.UUID, _a1 error) *MockSessionRepo_Touch_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockSessionRepo_Touch_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time) (uuid.UUID, error)) *MockSessionRepo_Touch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSessionRepo creates a new instance of MockSessionRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSessionRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSessionRepo {
+	mock := &MockSessionRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
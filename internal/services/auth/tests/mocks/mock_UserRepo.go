@@ -24,6 +24,54 @@ func (_m *MockUserRepo) EXPECT() *MockUserRepo_Expecter {
 	return &MockUserRepo_Expecter{mock: &_m.Mock}
 }
 
+// AssignRole provides a mock function with given fields: ctx, userID, role
+func (_m *MockUserRepo) AssignRole(ctx context.Context, userID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AssignRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepo_AssignRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AssignRole'
+type MockUserRepo_AssignRole_Call struct {
+	*mock.Call
+}
+
+// AssignRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - role string
+func (_e *MockUserRepo_Expecter) AssignRole(ctx interface{}, userID interface{}, role interface{}) *MockUserRepo_AssignRole_Call {
+	return &MockUserRepo_AssignRole_Call{Call: _e.mock.On("AssignRole", ctx, userID, role)}
+}
+
+func (_c *MockUserRepo_AssignRole_Call) Run(run func(ctx context.Context, userID uuid.UUID, role string)) *MockUserRepo_AssignRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_AssignRole_Call) Return(_a0 error) *MockUserRepo_AssignRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepo_AssignRole_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockUserRepo_AssignRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetByEmail provides a mock function with given fields: ctx, email
 func (_m *MockUserRepo) GetByEmail(ctx context.Context, email string) (*userModel.User, error) {
 	ret := _m.Called(ctx, email)
@@ -83,6 +131,123 @@ func (_c *MockUserRepo_GetByEmail_Call) RunAndReturn(run func(context.Context, s
 	return _c
 }
 
+// GetByID provides a mock function with given fields: ctx, userID
+func (_m *MockUserRepo) GetByID(ctx context.Context, userID uuid.UUID) (*userModel.User, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *userModel.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*userModel.User, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *userModel.User); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*userModel.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepo_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockUserRepo_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+func (_e *MockUserRepo_Expecter) GetByID(ctx interface{}, userID interface{}) *MockUserRepo_GetByID_Call {
+	return &MockUserRepo_GetByID_Call{Call: _e.mock.On("GetByID", ctx, userID)}
+}
+
+func (_c *MockUserRepo_GetByID_Call) Run(run func(ctx context.Context, userID uuid.UUID)) *MockUserRepo_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_GetByID_Call) Return(_a0 *userModel.User, _a1 error) *MockUserRepo_GetByID_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepo_GetByID_Call) RunAndReturn(run func(context.Context, uuid.UUID) (*userModel.User, error)) *MockUserRepo_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasPermission provides a mock function with given fields: ctx, userID, permission
+func (_m *MockUserRepo) HasPermission(ctx context.Context, userID uuid.UUID, permission string) (bool, error) {
+	ret := _m.Called(ctx, userID, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasPermission")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) (bool, error)); ok {
+		return rf(ctx, userID, permission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) bool); ok {
+		r0 = rf(ctx, userID, permission)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, string) error); ok {
+		r1 = rf(ctx, userID, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepo_HasPermission_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasPermission'
+type MockUserRepo_HasPermission_Call struct {
+	*mock.Call
+}
+
+// HasPermission is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - permission string
+func (_e *MockUserRepo_Expecter) HasPermission(ctx interface{}, userID interface{}, permission interface{}) *MockUserRepo_HasPermission_Call {
+	return &MockUserRepo_HasPermission_Call{Call: _e.mock.On("HasPermission", ctx, userID, permission)}
+}
+
+func (_c *MockUserRepo_HasPermission_Call) Run(run func(ctx context.Context, userID uuid.UUID, permission string)) *MockUserRepo_HasPermission_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_HasPermission_Call) Return(_a0 bool, _a1 error) *MockUserRepo_HasPermission_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepo_HasPermission_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) (bool, error)) *MockUserRepo_HasPermission_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // IsAdmin provides a mock function with given fields: ctx, userID
 func (_m *MockUserRepo) IsAdmin(ctx context.Context, userID uuid.UUID) (bool, error) {
 	ret := _m.Called(ctx, userID)
@@ -140,6 +305,54 @@ func (_c *MockUserRepo_IsAdmin_Call) RunAndReturn(run func(context.Context, uuid
 	return _c
 }
 
+// RevokeRole provides a mock function with given fields: ctx, userID, role
+func (_m *MockUserRepo) RevokeRole(ctx context.Context, userID uuid.UUID, role string) error {
+	ret := _m.Called(ctx, userID, role)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeRole")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, role)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepo_RevokeRole_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeRole'
+type MockUserRepo_RevokeRole_Call struct {
+	*mock.Call
+}
+
+// RevokeRole is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - role string
+func (_e *MockUserRepo_Expecter) RevokeRole(ctx interface{}, userID interface{}, role interface{}) *MockUserRepo_RevokeRole_Call {
+	return &MockUserRepo_RevokeRole_Call{Call: _e.mock.On("RevokeRole", ctx, userID, role)}
+}
+
+func (_c *MockUserRepo_RevokeRole_Call) Run(run func(ctx context.Context, userID uuid.UUID, role string)) *MockUserRepo_RevokeRole_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_RevokeRole_Call) Return(_a0 error) *MockUserRepo_RevokeRole_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepo_RevokeRole_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockUserRepo_RevokeRole_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Save provides a mock function with given fields: ctx, user
 func (_m *MockUserRepo) Save(ctx context.Context, user userModel.User) (uuid.UUID, error) {
 	ret := _m.Called(ctx, user)
@@ -199,6 +412,113 @@ func (_c *MockUserRepo_Save_Call) RunAndReturn(run func(context.Context, userMod
 	return _c
 }
 
+// SetStatus provides a mock function with given fields: ctx, userID, from, to
+func (_m *MockUserRepo) SetStatus(ctx context.Context, userID uuid.UUID, from []userModel.Status, to userModel.Status) (userModel.Status, error) {
+	ret := _m.Called(ctx, userID, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetStatus")
+	}
+
+	var r0 userModel.Status
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) (userModel.Status, error)); ok {
+		return rf(ctx, userID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) userModel.Status); ok {
+		r0 = rf(ctx, userID, from, to)
+	} else {
+		r0 = ret.Get(0).(userModel.Status)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) error); ok {
+		r1 = rf(ctx, userID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserRepo_SetStatus_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetStatus'
+type MockUserRepo_SetStatus_Call struct {
+	*mock.Call
+}
+
+// SetStatus is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - from []userModel.Status
+//   - to userModel.Status
+func (_e *MockUserRepo_Expecter) SetStatus(ctx interface{}, userID interface{}, from interface{}, to interface{}) *MockUserRepo_SetStatus_Call {
+	return &MockUserRepo_SetStatus_Call{Call: _e.mock.On("SetStatus", ctx, userID, from, to)}
+}
+
+func (_c *MockUserRepo_SetStatus_Call) Run(run func(ctx context.Context, userID uuid.UUID, from []userModel.Status, to userModel.Status)) *MockUserRepo_SetStatus_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].([]userModel.Status), args[3].(userModel.Status))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_SetStatus_Call) Return(_a0 userModel.Status, _a1 error) *MockUserRepo_SetStatus_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserRepo_SetStatus_Call) RunAndReturn(run func(context.Context, uuid.UUID, []userModel.Status, userModel.Status) (userModel.Status, error)) *MockUserRepo_SetStatus_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// UpdatePassword provides a mock function with given fields: ctx, userID, hashedPassword
+func (_m *MockUserRepo) UpdatePassword(ctx context.Context, userID uuid.UUID, hashedPassword string) error {
+	ret := _m.Called(ctx, userID, hashedPassword)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdatePassword")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, hashedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserRepo_UpdatePassword_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdatePassword'
+type MockUserRepo_UpdatePassword_Call struct {
+	*mock.Call
+}
+
+// UpdatePassword is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - hashedPassword string
+func (_e *MockUserRepo_Expecter) UpdatePassword(ctx interface{}, userID interface{}, hashedPassword interface{}) *MockUserRepo_UpdatePassword_Call {
+	return &MockUserRepo_UpdatePassword_Call{Call: _e.mock.On("UpdatePassword", ctx, userID, hashedPassword)}
+}
+
+func (_c *MockUserRepo_UpdatePassword_Call) Run(run func(ctx context.Context, userID uuid.UUID, hashedPassword string)) *MockUserRepo_UpdatePassword_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserRepo_UpdatePassword_Call) Return(_a0 error) *MockUserRepo_UpdatePassword_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserRepo_UpdatePassword_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockUserRepo_UpdatePassword_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockUserRepo creates a new instance of MockUserRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockUserRepo(t interface {
@@ -0,0 +1,206 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	tokenModel "github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	mock "github.com/stretchr/testify/mock"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockUserTokenRepo is an autogenerated mock type for the UserTokenRepo type
+type MockUserTokenRepo struct {
+	mock.Mock
+}
+
+type MockUserTokenRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockUserTokenRepo) EXPECT() *MockUserTokenRepo_Expecter {
+	return &MockUserTokenRepo_Expecter{mock: &_m.Mock}
+}
+
+// Consume provides a mock function with given fields: ctx, purpose, tokenHash
+func (_m *MockUserTokenRepo) Consume(ctx context.Context, purpose string, tokenHash string) (*tokenModel.UserToken, error) {
+	ret := _m.Called(ctx, purpose, tokenHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Consume")
+	}
+
+	var r0 *tokenModel.UserToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*tokenModel.UserToken, error)); ok {
+		return rf(ctx, purpose, tokenHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *tokenModel.UserToken); ok {
+		r0 = rf(ctx, purpose, tokenHash)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*tokenModel.UserToken)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, purpose, tokenHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserTokenRepo_Consume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Consume'
+type MockUserTokenRepo_Consume_Call struct {
+	*mock.Call
+}
+
+// Consume is a helper method to define mock.On call
+//   - ctx context.Context
+//   - purpose string
+//   - tokenHash string
+func (_e *MockUserTokenRepo_Expecter) Consume(ctx interface{}, purpose interface{}, tokenHash interface{}) *MockUserTokenRepo_Consume_Call {
+	return &MockUserTokenRepo_Consume_Call{Call: _e.mock.On("Consume", ctx, purpose, tokenHash)}
+}
+
+func (_c *MockUserTokenRepo_Consume_Call) Run(run func(ctx context.Context, purpose string, tokenHash string)) *MockUserTokenRepo_Consume_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserTokenRepo_Consume_Call) Return(_a0 *tokenModel.UserToken, _a1 error) *MockUserTokenRepo_Consume_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserTokenRepo_Consume_Call) RunAndReturn(run func(context.Context, string, string) (*tokenModel.UserToken, error)) *MockUserTokenRepo_Consume_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// InvalidateAll provides a mock function with given fields: ctx, userID, purpose
+func (_m *MockUserTokenRepo) InvalidateAll(ctx context.Context, userID uuid.UUID, purpose string) error {
+	ret := _m.Called(ctx, userID, purpose)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InvalidateAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, userID, purpose)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockUserTokenRepo_InvalidateAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InvalidateAll'
+type MockUserTokenRepo_InvalidateAll_Call struct {
+	*mock.Call
+}
+
+// InvalidateAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - userID uuid.UUID
+//   - purpose string
+func (_e *MockUserTokenRepo_Expecter) InvalidateAll(ctx interface{}, userID interface{}, purpose interface{}) *MockUserTokenRepo_InvalidateAll_Call {
+	return &MockUserTokenRepo_InvalidateAll_Call{Call: _e.mock.On("InvalidateAll", ctx, userID, purpose)}
+}
+
+func (_c *MockUserTokenRepo_InvalidateAll_Call) Run(run func(ctx context.Context, userID uuid.UUID, purpose string)) *MockUserTokenRepo_InvalidateAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockUserTokenRepo_InvalidateAll_Call) Return(_a0 error) *MockUserTokenRepo_InvalidateAll_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockUserTokenRepo_InvalidateAll_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockUserTokenRepo_InvalidateAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Save provides a mock function with given fields: ctx, token
+func (_m *MockUserTokenRepo) Save(ctx context.Context, token tokenModel.UserToken) (uuid.UUID, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, tokenModel.UserToken) (uuid.UUID, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, tokenModel.UserToken) uuid.UUID); ok {
+		r0 = rf(ctx, token)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, tokenModel.UserToken) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockUserTokenRepo_Save_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Save'
+type MockUserTokenRepo_Save_Call struct {
+	*mock.Call
+}
+
+// Save is a helper method to define mock.On call
+//   - ctx context.Context
+//   - token tokenModel.UserToken
+func (_e *MockUserTokenRepo_Expecter) Save(ctx interface{}, token interface{}) *MockUserTokenRepo_Save_Call {
+	return &MockUserTokenRepo_Save_Call{Call: _e.mock.On("Save", ctx, token)}
+}
+
+func (_c *MockUserTokenRepo_Save_Call) Run(run func(ctx context.Context, token tokenModel.UserToken)) *MockUserTokenRepo_Save_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(tokenModel.UserToken))
+	})
+	return _c
+}
+
+func (_c *MockUserTokenRepo_Save_Call) Return(_a0 uuid.UUID, _a1 error) *MockUserTokenRepo_Save_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockUserTokenRepo_Save_Call) RunAndReturn(run func(context.Context, tokenModel.UserToken) (uuid.UUID, error)) *MockUserTokenRepo_Save_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockUserTokenRepo creates a new instance of MockUserTokenRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockUserTokenRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockUserTokenRepo {
+	mock := &MockUserTokenRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
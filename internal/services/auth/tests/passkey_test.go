@@ -0,0 +1,313 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/passkey"
+	"github.com/Tbits007/auth/internal/lib/passkey/passkeytest"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// registeredPasskey registers a passkey for user directly with the
+// relying party and returns it as stored.
+func registeredPasskey(t *testing.T, user userModel.User) (*passkeytest.Authenticator, *passkeyModel.Credential) {
+	t.Helper()
+
+	authenticator, err := passkeytest.New(testPasskeyOrigin)
+	require.NoError(t, err)
+
+	owner := passkey.User{ID: user.ID, Name: user.Email}
+
+	options, session, err := testRelyingParty.BeginRegistration(owner)
+	require.NoError(t, err)
+
+	response, err := authenticator.Register(options)
+	require.NoError(t, err)
+
+	credential, err := testRelyingParty.FinishRegistration(owner, session, response)
+	require.NoError(t, err)
+	credential.ID = uuid.New()
+
+	return authenticator, &credential
+}
+
+// storeSession returns a handler for the cache's session setters that
+// keeps the stored session, so that it can be handed back by a later take.
+func storeSession[K any](session *[]byte) func(context.Context, K, []byte, time.Duration) error {
+	return func(_ context.Context, _ K, value []byte, _ time.Duration) error {
+		*session = value
+		return nil
+	}
+}
+
+func TestPasskeyRegistration(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	authenticator, err := passkeytest.New(testPasskeyOrigin)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.passkeyRepo.EXPECT().
+		ListByUser(ctx, user.ID).
+		Return(nil, nil)
+
+	var session []byte
+	m.cacheRepo.EXPECT().
+		SetPasskeyRegistration(ctx, user.ID, mock.Anything, testConfig.PasskeyChallengeTTL).
+		RunAndReturn(storeSession[uuid.UUID](&session))
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	passkeyID := uuid.New()
+	m.passkeyRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(credential passkeyModel.Credential) bool {
+			return credential.UserID == user.ID
+		})).
+		Return(passkeyID, nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	options, err := service.BeginPasskeyRegistration(ctx, token)
+	require.NoError(t, err)
+
+	response, err := authenticator.Register(options)
+	require.NoError(t, err)
+
+	m.cacheRepo.EXPECT().
+		TakePasskeyRegistration(ctx, user.ID).
+		Return(session, nil)
+
+	credential, err := service.FinishPasskeyRegistration(ctx, token, response)
+
+	require.NoError(t, err)
+	assert.Equal(t, passkeyID, credential.ID)
+	assert.Equal(t, user.ID, credential.UserID)
+	assert.Equal(t, authenticator.CredentialID, credential.CredentialID)
+}
+
+func TestFinishPasskeyRegistration_InvalidChallenge(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.passkeyRepo.EXPECT().
+		ListByUser(ctx, user.ID).
+		Return(nil, nil)
+
+	m.cacheRepo.EXPECT().
+		TakePasskeyRegistration(ctx, user.ID).
+		Return(nil, storage.ErrKeyNotFound)
+
+	_, err = service.FinishPasskeyRegistration(ctx, token, []byte("{}"))
+
+	assert.True(t, errors.Is(err, auth.ErrInvalidPasskeyChallenge), err)
+	m.passkeyRepo.AssertNotCalled(t, "Save")
+}
+
+func TestPasskeyLogin(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+	authenticator, stored := registeredPasskey(t, user)
+
+	service, m := newTestService(t)
+
+	var (
+		storedID string
+		session  []byte
+	)
+	m.cacheRepo.EXPECT().
+		SetPasskeyLogin(ctx, mock.AnythingOfType("string"), mock.Anything, testConfig.PasskeyChallengeTTL).
+		RunAndReturn(func(ctx context.Context, challengeID string, value []byte, ttl time.Duration) error {
+			storedID = challengeID
+			return storeSession[string](&session)(ctx, challengeID, value, ttl)
+		})
+
+	m.passkeyRepo.EXPECT().
+		GetByCredentialID(ctx, authenticator.CredentialID).
+		Return(stored, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.passkeyRepo.EXPECT().
+		UpdateSignCount(ctx, authenticator.CredentialID, uint32(1)).
+		Return(nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("sessionModel.Session")).
+		Return(uuid.New(), nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.RefreshToken")).
+		Return(uuid.New(), nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	challengeID, options, err := service.BeginPasskeyLogin(ctx)
+	require.NoError(t, err)
+	assert.NotEmpty(t, challengeID)
+	assert.Equal(t, challengeID, storedID)
+
+	response, err := authenticator.Assert(options)
+	require.NoError(t, err)
+
+	m.cacheRepo.EXPECT().
+		TakePasskeyLogin(ctx, challengeID).
+		Return(session, nil)
+
+	tokens, err := service.FinishPasskeyLogin(ctx, challengeID, response)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+}
+
+func TestFinishPasskeyLogin_Rejects(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+
+	tests := []struct {
+		name         string
+		findErr      error
+		signCountErr error
+	}{
+		{name: "unknown passkey", findErr: storage.ErrPasskeyNotFound},
+		{name: "sign count conflict", signCountErr: storage.ErrPasskeyNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authenticator, stored := registeredPasskey(t, user)
+
+			service, m := newTestService(t)
+
+			var session []byte
+			m.cacheRepo.EXPECT().
+				SetPasskeyLogin(ctx, mock.AnythingOfType("string"), mock.Anything, testConfig.PasskeyChallengeTTL).
+				RunAndReturn(storeSession[string](&session))
+
+			if tt.findErr != nil {
+				m.passkeyRepo.EXPECT().
+					GetByCredentialID(ctx, authenticator.CredentialID).
+					Return(nil, tt.findErr)
+			} else {
+				m.passkeyRepo.EXPECT().
+					GetByCredentialID(ctx, authenticator.CredentialID).
+					Return(stored, nil)
+
+				m.userRepo.EXPECT().
+					GetByID(ctx, user.ID).
+					Return(&user, nil)
+
+				m.passkeyRepo.EXPECT().
+					UpdateSignCount(ctx, authenticator.CredentialID, uint32(1)).
+					Return(tt.signCountErr)
+			}
+
+			challengeID, options, err := service.BeginPasskeyLogin(ctx)
+			require.NoError(t, err)
+
+			response, err := authenticator.Assert(options)
+			require.NoError(t, err)
+
+			m.cacheRepo.EXPECT().
+				TakePasskeyLogin(ctx, challengeID).
+				Return(session, nil)
+
+			_, err = service.FinishPasskeyLogin(ctx, challengeID, response)
+
+			assert.True(t, errors.Is(err, auth.ErrInvalidPasskey), err)
+			m.refreshTokenRepo.AssertNotCalled(t, "Save")
+		})
+	}
+}
+
+func TestFinishPasskeyLogin_InvalidChallenge(t *testing.T) {
+	ctx := context.Background()
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		TakePasskeyLogin(ctx, "challenge").
+		Return(nil, storage.ErrKeyNotFound)
+
+	_, err := service.FinishPasskeyLogin(ctx, "challenge", []byte("{}"))
+
+	assert.True(t, errors.Is(err, auth.ErrInvalidPasskeyChallenge), err)
+	m.passkeyRepo.AssertNotCalled(t, "GetByCredentialID")
+}
@@ -0,0 +1,497 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestRequestPasswordReset_Success(t *testing.T) {
+	ctx := context.Background()
+	user := &userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+
+	service, m := newTestService(t)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, user.Email).
+		Return(user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		InvalidateAll(ctx, user.ID, tokenModel.PurposePasswordReset).
+		Return(nil)
+
+	var saved tokenModel.UserToken
+	m.userTokenRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(token tokenModel.UserToken) bool {
+			return token.UserID == user.ID &&
+				token.Purpose == tokenModel.PurposePasswordReset &&
+				token.ExpiresAt.After(time.Now())
+		})).
+		RunAndReturn(func(_ context.Context, token tokenModel.UserToken) (uuid.UUID, error) {
+			saved = token
+			return uuid.New(), nil
+		})
+
+	var published eventModel.Event
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserPasswordResetRequestedV1
+		})).
+		RunAndReturn(func(_ context.Context, e eventModel.Event) (uuid.UUID, error) {
+			published = e
+			return e.ID, nil
+		})
+
+	err := service.RequestPasswordReset(ctx, user.Email)
+
+	require.NoError(t, err)
+
+	var envelope eventModel.Envelope
+	require.NoError(t, json.Unmarshal(published.Payload, &envelope))
+	var data eventModel.UserPasswordResetRequested
+	require.NoError(t, json.Unmarshal(envelope.Data, &data))
+
+	token, err := testTokenBox.Open(data.EncryptedToken, user.ID[:])
+	require.NoError(t, err)
+	assert.Equal(t, saved.TokenHash, opaque.Hash(string(token)))
+	assert.NotContains(t, string(published.Payload), string(token))
+}
+
+func TestRequestPasswordReset_SucceedsSilently(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    *userModel.User
+		repoErr error
+	}{
+		{
+			name:    "unknown email",
+			repoErr: storage.ErrUserNotFound,
+		},
+		{
+			name: "disabled account",
+			user: &userModel.User{ID: uuid.New(), Status: userModel.StatusDisabled},
+		},
+		{
+			name: "deleted account",
+			user: &userModel.User{ID: uuid.New(), Status: userModel.StatusDeleted},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			service, m := newTestService(t)
+
+			m.userRepo.EXPECT().
+				GetByEmail(ctx, "test@example.com").
+				Return(tt.user, tt.repoErr)
+
+			err := service.RequestPasswordReset(ctx, "test@example.com")
+
+			require.NoError(t, err)
+			m.userTokenRepo.AssertNotCalled(t, "Save")
+			m.eventRepo.AssertNotCalled(t, "Save")
+		})
+	}
+}
+
+func TestResetPassword_Success(t *testing.T) {
+	ctx := context.Background()
+	token := "reset-token"
+	newPassword := "new_password"
+	user := &userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+
+	service, m := newTestService(t)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		Consume(ctx, tokenModel.PurposePasswordReset, opaque.Hash(token)).
+		Return(&tokenModel.UserToken{UserID: user.ID}, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(user, nil)
+
+	m.userRepo.EXPECT().
+		UpdatePassword(ctx, user.ID, mock.MatchedBy(func(hash string) bool {
+			return bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil
+		})).
+		Return(nil)
+
+	m.userTokenRepo.EXPECT().
+		InvalidateAll(ctx, user.ID, tokenModel.PurposePasswordReset).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserPasswordResetV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.sessionRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeUserTokens(ctx, user.ID, mock.AnythingOfType("time.Time"), uuid.Nil, testConfig.TokenTTL).
+		Return(nil)
+
+	err := service.ResetPassword(ctx, token, newPassword)
+
+	require.NoError(t, err)
+}
+
+func TestResetPassword_InvalidToken(t *testing.T) {
+	ctx := context.Background()
+
+	service, m := newTestService(t)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		Consume(ctx, tokenModel.PurposePasswordReset, mock.AnythingOfType("string")).
+		Return(nil, storage.ErrTokenNotFound)
+
+	err := service.ResetPassword(ctx, "unknown", "new_password")
+
+	require.ErrorIs(t, err, auth.ErrInvalidResetToken)
+	m.userRepo.AssertNotCalled(t, "UpdatePassword")
+}
+
+func TestResetPassword_DisabledAccount(t *testing.T) {
+	ctx := context.Background()
+	user := &userModel.User{ID: uuid.New(), Status: userModel.StatusDisabled}
+
+	service, m := newTestService(t)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		Consume(ctx, tokenModel.PurposePasswordReset, mock.AnythingOfType("string")).
+		Return(&tokenModel.UserToken{UserID: user.ID}, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(user, nil)
+
+	err := service.ResetPassword(ctx, "token", "new_password")
+
+	require.ErrorIs(t, err, auth.ErrAccountDisabled)
+	m.userRepo.AssertNotCalled(t, "UpdatePassword")
+}
+
+func TestResetPassword_RevocationFailureRollsBack(t *testing.T) {
+	ctx := context.Background()
+	expectedErr := errors.New("redis down")
+	user := &userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+
+	service, m := newTestService(t)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		Consume(ctx, tokenModel.PurposePasswordReset, mock.AnythingOfType("string")).
+		Return(&tokenModel.UserToken{UserID: user.ID}, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(user, nil)
+
+	m.userRepo.EXPECT().
+		UpdatePassword(ctx, user.ID, mock.AnythingOfType("string")).
+		Return(nil)
+
+	m.userTokenRepo.EXPECT().
+		InvalidateAll(ctx, user.ID, tokenModel.PurposePasswordReset).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	m.sessionRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeUserTokens(ctx, user.ID, mock.AnythingOfType("time.Time"), uuid.Nil, mock.Anything).
+		Return(expectedErr)
+
+	err := service.ResetPassword(ctx, "token", "new_password")
+
+	require.ErrorIs(t, err, expectedErr)
+	assert.NotErrorIs(t, err, auth.ErrInvalidResetToken)
+}
+
+func TestChangePassword_KeepSessions(t *testing.T) {
+	ctx := context.Background()
+	oldPassword := "old_password"
+	newPassword := "new_password"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(oldPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := &userModel.User{
+		ID:             uuid.New(),
+		Email:          "test@example.com",
+		HashedPassword: string(hash),
+		Status:         userModel.StatusActive,
+	}
+
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, *user, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userRepo.EXPECT().
+		UpdatePassword(ctx, user.ID, mock.MatchedBy(func(hash string) bool {
+			return bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil
+		})).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserPasswordChangedV1
+		})).
+		Return(uuid.New(), nil)
+
+	tokens, err := service.ChangePassword(ctx, token, oldPassword, newPassword, false)
+
+	require.NoError(t, err)
+	assert.Empty(t, tokens.AccessToken)
+	m.sessionRepo.AssertNotCalled(t, "RevokeAllForUser")
+	m.refreshTokenRepo.AssertNotCalled(t, "RevokeAllForUser")
+	m.cacheRepo.AssertNotCalled(t, "RevokeUserTokens")
+}
+
+func TestChangePassword_RevokeOtherSessions(t *testing.T) {
+	ctx := context.Background()
+	oldPassword := "old_password"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(oldPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	user := &userModel.User{
+		ID:             uuid.New(),
+		Email:          "test@example.com",
+		HashedPassword: string(hash),
+		Status:         userModel.StatusActive,
+	}
+
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, *user, time.Hour)
+	require.NoError(t, err)
+
+	sessionID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userRepo.EXPECT().
+		UpdatePassword(ctx, user.ID, mock.AnythingOfType("string")).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	m.sessionRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeUserTokens(ctx, user.ID, mock.AnythingOfType("time.Time"), sessionID, testConfig.TokenTTL).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeToken(ctx, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).
+		Return(nil)
+
+	m.sessionRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(session sessionModel.Session) bool {
+			return session.UserID == user.ID
+		})).
+		Return(sessionID, nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(token tokenModel.RefreshToken) bool {
+			return token.UserID == user.ID
+		})).
+		Return(uuid.New(), nil)
+
+	tokens, err := service.ChangePassword(ctx, token, oldPassword, "new_password", true)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.RefreshToken)
+
+	// The new session is the one spared by the user-wide revocation.
+	claims, err := testutils.NewIssuer("secret").ParseToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, claims.SessionID)
+}
+
+func TestChangePassword_Rejected(t *testing.T) {
+	oldPassword := "old_password"
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(oldPassword), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		oldPassword string
+		newPassword string
+		expectedErr error
+	}{
+		{
+			name:        "wrong old password",
+			oldPassword: "wrong_password",
+			newPassword: "new_password",
+			expectedErr: auth.ErrInvalidCredentials,
+		},
+		{
+			name:        "too short",
+			oldPassword: oldPassword,
+			newPassword: "short",
+			expectedErr: auth.ErrInvalidPassword,
+		},
+		{
+			name:        "beyond bcrypt limit",
+			oldPassword: oldPassword,
+			newPassword: strings.Repeat("a", 73),
+			expectedErr: auth.ErrInvalidPassword,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			user := &userModel.User{
+				ID:             uuid.New(),
+				Email:          "test@example.com",
+				HashedPassword: string(hash),
+				Status:         userModel.StatusActive,
+			}
+
+			token, err := testutils.NewIssuer("secret").NewToken(ctx, *user, time.Hour)
+			require.NoError(t, err)
+
+			service, m := newTestService(t)
+
+			m.cacheRepo.EXPECT().
+				IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, mock.Anything, mock.Anything).
+				Return(false, nil)
+
+			m.cacheRepo.EXPECT().
+				GetUserStatus(ctx, user.ID).
+				Return(userModel.StatusActive, 0, nil)
+
+			m.userRepo.EXPECT().
+				GetByID(ctx, user.ID).
+				Return(user, nil)
+
+			_, err = service.ChangePassword(ctx, token, tt.oldPassword, tt.newPassword, false)
+
+			require.ErrorIs(t, err, tt.expectedErr)
+			m.userRepo.AssertNotCalled(t, "UpdatePassword")
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHasPermission_Success(t *testing.T) {
+	ctx := context.Background()
+	testUserID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.userRepo.EXPECT().
+		HasPermission(ctx, testUserID, roleModel.PermUsersRead).
+		Return(true, nil)
+
+	allowed, err := service.HasPermission(ctx, testUserID, roleModel.PermUsersRead)
+
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestHasPermission_UserNotFound(t *testing.T) {
+	ctx := context.Background()
+	testUserID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.userRepo.EXPECT().
+		HasPermission(ctx, testUserID, roleModel.PermUsersRead).
+		Return(false, storage.ErrUserNotFound)
+
+	_, err := service.HasPermission(ctx, testUserID, roleModel.PermUsersRead)
+
+	assert.ErrorIs(t, err, auth.ErrUserNotFound)
+}
+
+func TestAssignRole_Success(t *testing.T) {
+	ctx := context.Background()
+	actor := userModel.User{ID: uuid.New(), Email: "admin@example.com"}
+	testUserID := uuid.New()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, actor, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, actor.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		HasPermission(ctx, actor.ID, roleModel.PermRolesAssign).
+		Return(true, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userRepo.EXPECT().
+		AssignRole(ctx, testUserID, roleModel.RoleSupport).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserRoleAssignedV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.cacheRepo.EXPECT().
+		InvalidateUser(ctx, testUserID).
+		Return(nil)
+
+	err = service.AssignRole(ctx, token, testUserID, roleModel.RoleSupport)
+
+	require.NoError(t, err)
+}
+
+func TestAssignRole_PermissionDenied(t *testing.T) {
+	ctx := context.Background()
+	actor := userModel.User{ID: uuid.New(), Email: "user@example.com"}
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, actor, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, actor.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		HasPermission(ctx, actor.ID, roleModel.PermRolesAssign).
+		Return(false, nil)
+
+	err = service.AssignRole(ctx, token, uuid.New(), roleModel.RoleAdmin)
+
+	assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+	m.userRepo.AssertNotCalled(t, "AssignRole")
+}
+
+func TestRevokeRole_UnknownRole(t *testing.T) {
+	ctx := context.Background()
+	actor := userModel.User{ID: uuid.New(), Email: "admin@example.com"}
+	testUserID := uuid.New()
+	token, err := testutils.NewIssuer("secret").NewToken(ctx, actor, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), mock.Anything, mock.Anything, mock.Anything).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, actor.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.userRepo.EXPECT().
+		HasPermission(ctx, actor.ID, roleModel.PermRolesAssign).
+		Return(true, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userRepo.EXPECT().
+		RevokeRole(ctx, testUserID, "nonexistent").
+		Return(storage.ErrRoleNotFound)
+
+	err = service.RevokeRole(ctx, token, testUserID, "nonexistent")
+
+	assert.ErrorIs(t, err, auth.ErrUnknownRole)
+	m.eventRepo.AssertNotCalled(t, "Save")
+}
+
+func TestAssignRole_InvalidToken(t *testing.T) {
+	ctx := context.Background()
+
+	service, _ := newTestService(t)
+
+	err := service.AssignRole(ctx, "not-a-token", uuid.New(), roleModel.RoleAdmin)
+
+	assert.ErrorIs(t, err, auth.ErrInvalidToken)
+}
@@ -0,0 +1,254 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefresh_Success(t *testing.T) {
+	ctx := context.Background()
+	testRefreshToken := "refresh_token"
+	user := userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+	stored := tokenModel.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: opaque.Hash(testRefreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+	sessionID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(&stored, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.refreshTokenRepo.EXPECT().
+		MarkUsed(ctx, stored.ID).
+		Return(nil)
+
+	m.sessionRepo.EXPECT().
+		Touch(ctx, stored.FamilyID, mock.AnythingOfType("time.Time")).
+		Return(sessionID, nil)
+
+	m.refreshTokenRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(token tokenModel.RefreshToken) bool {
+			return token.UserID == user.ID &&
+				token.FamilyID == stored.FamilyID &&
+				token.TokenHash != stored.TokenHash
+		})).
+		Return(uuid.New(), nil)
+
+	issuer := testutils.NewIssuer("secret")
+
+	tokens, err := service.Refresh(ctx, testRefreshToken)
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, tokens.AccessToken)
+	assert.NotEmpty(t, tokens.RefreshToken)
+	assert.NotEqual(t, testRefreshToken, tokens.RefreshToken)
+
+	claims, err := issuer.ParseToken(tokens.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, sessionID, claims.SessionID)
+
+	m.refreshTokenRepo.AssertNotCalled(t, "RevokeFamily")
+}
+
+func TestRefresh_TokenNotFound(t *testing.T) {
+	ctx := context.Background()
+	testRefreshToken := "unknown_token"
+
+	service, m := newTestService(t)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(nil, storage.ErrTokenNotFound)
+
+	tokens, err := service.Refresh(ctx, testRefreshToken)
+
+	require.Error(t, err)
+	assert.Empty(t, tokens)
+	assert.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+}
+
+func TestRefresh_Expired(t *testing.T) {
+	ctx := context.Background()
+	testRefreshToken := "expired_token"
+	stored := tokenModel.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: opaque.Hash(testRefreshToken),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	}
+
+	service, m := newTestService(t)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(&stored, nil)
+
+	tokens, err := service.Refresh(ctx, testRefreshToken)
+
+	require.Error(t, err)
+	assert.Empty(t, tokens)
+	assert.ErrorIs(t, err, auth.ErrInvalidRefreshToken)
+
+	m.userRepo.AssertNotCalled(t, "GetByID")
+}
+
+func TestRefresh_AdminWithoutMFA(t *testing.T) {
+	ctx := context.Background()
+	testRefreshToken := "refresh_token"
+	user := userModel.User{
+		ID:      uuid.New(),
+		Email:   "admin@example.com",
+		Status:  userModel.StatusActive,
+		IsAdmin: true,
+	}
+	stored := tokenModel.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: opaque.Hash(testRefreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	service, m := newTestService(t)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(&stored, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	tokens, err := service.Refresh(ctx, testRefreshToken)
+
+	assert.Empty(t, tokens)
+	assert.ErrorIs(t, err, auth.ErrMFAEnrollmentRequired)
+	m.refreshTokenRepo.AssertNotCalled(t, "MarkUsed")
+}
+
+func TestRefresh_ReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	testRefreshToken := "used_token"
+	usedAt := time.Now().Add(-time.Minute)
+	stored := tokenModel.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		FamilyID:  uuid.New(),
+		TokenHash: opaque.Hash(testRefreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+		UsedAt:    &usedAt,
+	}
+	sessionID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(&stored, nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeFamily(ctx, stored.FamilyID).
+		Return(nil)
+
+	m.sessionRepo.EXPECT().
+		RevokeByFamily(ctx, stored.FamilyID).
+		Return(sessionID, nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeSession(ctx, sessionID, testConfig.TokenTTL).
+		Return(nil)
+
+	tokens, err := service.Refresh(ctx, testRefreshToken)
+
+	require.Error(t, err)
+	assert.Empty(t, tokens)
+	assert.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+
+	m.refreshTokenRepo.AssertNotCalled(t, "Save")
+}
+
+func TestRefresh_ConcurrentReuseRevokesFamily(t *testing.T) {
+	ctx := context.Background()
+	testRefreshToken := "raced_token"
+	user := userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusActive,
+	}
+	stored := tokenModel.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: opaque.Hash(testRefreshToken),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	service, m := newTestService(t)
+
+	m.refreshTokenRepo.EXPECT().
+		GetByHash(ctx, opaque.Hash(testRefreshToken)).
+		Return(&stored, nil)
+
+	m.userRepo.EXPECT().
+		GetByID(ctx, user.ID).
+		Return(&user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.refreshTokenRepo.EXPECT().
+		MarkUsed(ctx, stored.ID).
+		Return(storage.ErrTokenNotFound)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeFamily(ctx, stored.FamilyID).
+		Return(nil)
+
+	m.sessionRepo.EXPECT().
+		RevokeByFamily(ctx, stored.FamilyID).
+		Return(uuid.Nil, storage.ErrSessionNotFound)
+
+	tokens, err := service.Refresh(ctx, testRefreshToken)
+
+	require.Error(t, err)
+	assert.Empty(t, tokens)
+	assert.ErrorIs(t, err, auth.ErrRefreshTokenReused)
+
+	m.refreshTokenRepo.AssertNotCalled(t, "Save")
+}
@@ -4,13 +4,12 @@ import (
 	"context"
 	"errors"
 	"testing"
-	"time"
 
 	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
 	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/password"
 	"github.com/Tbits007/auth/internal/services/auth"
-	"github.com/Tbits007/auth/internal/services/auth/tests/mocks"
-	"github.com/Tbits007/auth/internal/services/testutils"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -24,12 +23,9 @@ func TestRegister_Success(t *testing.T) {
     testPassword := "password123"
 	testUserID := uuid.New()
 
-    mockTxManager := mocks.NewMockTxManager(t)
-    mockUserRepo := mocks.NewMockUserRepo(t)
-    mockEventRepo := mocks.NewMockEventRepo(t)
-    mockCacheRepo := mocks.NewMockCacheRepo(t)
+    service, m := newTestService(t)
 
-    mockTxManager.EXPECT().
+    m.txManager.EXPECT().
         WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
 		Run(func(ctx context.Context, fn func(context.Context) error) {
 			err := fn(ctx)
@@ -37,31 +33,38 @@ func TestRegister_Success(t *testing.T) {
 		}).
         Return(nil)
 
-    mockUserRepo.EXPECT().
+    m.userRepo.EXPECT().
         Save(ctx, mock.MatchedBy(func(user userModel.User) bool {
             err := bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(testPassword))
-            return user.Email == testEmail && err == nil
+            return user.Email == testEmail &&
+				user.Status == userModel.StatusPendingVerification &&
+				err == nil
         })).
         Return(testUserID, nil)
 
-    mockEventRepo.EXPECT().
+    m.eventRepo.EXPECT().
 		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
-			return event.Status == eventModel.PENDING
+			return event.Status == eventModel.PENDING &&
+				event.EventType == eventModel.UserRegisteredV1
 		})).
         Return(uuid.New(), nil)
 
+	m.userTokenRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(token tokenModel.UserToken) bool {
+			return token.UserID == testUserID &&
+				token.Purpose == tokenModel.PurposeEmailVerification &&
+				token.TokenHash != ""
+		})).
+		Return(uuid.New(), nil)
 
-    service := auth.NewAuthService(
-        testutils.Log,
-        mockTxManager,
-        mockUserRepo,
-        mockEventRepo,
-        mockCacheRepo,
-        time.Hour,
-        "secret",
-    )
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserVerificationRequestedV1
+		})).
+		Return(uuid.New(), nil)
 
-    userID, err := service.Register(ctx, testEmail, testPassword)
+    // The account is stored under the normalized address.
+    userID, err := service.Register(ctx, " Test@Example.COM", testPassword)
 
     require.NoError(t, err)
     assert.Equal(t, testUserID, userID)
@@ -73,48 +76,31 @@ func TestRegister_TransactionError(t *testing.T) {
 	testPassword := "password123"
 	expectedErr := errors.New("transaction error")
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockTxManager.EXPECT().
+	m.txManager.EXPECT().
 		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
 		Return(expectedErr)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
 	userID, err := service.Register(ctx, testEmail, testPassword)
 
 	require.Error(t, err)
 	assert.Equal(t, uuid.Nil, userID)
 	assert.Contains(t, err.Error(), expectedErr.Error())
 
-	mockUserRepo.AssertNotCalled(t, "Save")
-	mockEventRepo.AssertNotCalled(t, "Save")
+	m.userRepo.AssertNotCalled(t, "Save")
+	m.eventRepo.AssertNotCalled(t, "Save")
 }
 
-
 func TestRegister_UserSaveError(t *testing.T) {
 	ctx := context.Background()
 	testEmail := "test@example.com"
 	testPassword := "password123"
 	expectedErr := errors.New("user save error")
 
-	mockTxManager := mocks.NewMockTxManager(t)
-	mockUserRepo := mocks.NewMockUserRepo(t)
-	mockEventRepo := mocks.NewMockEventRepo(t)
-	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	service, m := newTestService(t)
 
-	mockTxManager.EXPECT().
+	m.txManager.EXPECT().
 		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
 		Run(func(ctx context.Context, fn func(context.Context) error) {
 			err := fn(ctx)
@@ -122,25 +108,31 @@ func TestRegister_UserSaveError(t *testing.T) {
 		}).
 		Return(expectedErr)
 
-	mockUserRepo.EXPECT().
+	m.userRepo.EXPECT().
 		Save(ctx, mock.AnythingOfType("userModel.User")).
 		Return(uuid.Nil, expectedErr)
 
-	service := auth.NewAuthService(
-		testutils.Log,
-		mockTxManager,
-		mockUserRepo,
-		mockEventRepo,
-		mockCacheRepo,
-		time.Hour,
-		"secret",
-	)
-
 	userID, err := service.Register(ctx, testEmail, testPassword)
 
 	require.Error(t, err)
 	assert.Equal(t, uuid.Nil, userID)
 	assert.Contains(t, err.Error(), expectedErr.Error())
 
-	mockEventRepo.AssertNotCalled(t, "Save")
-}
\ No newline at end of file
+	m.eventRepo.AssertNotCalled(t, "Save")
+}
+func TestRegister_PasswordPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	service, m := newTestService(t)
+
+	userID, err := service.Register(ctx, "test@example.com", "a")
+
+	require.ErrorIs(t, err, auth.ErrInvalidPassword)
+	assert.Equal(t, uuid.Nil, userID)
+
+	var policyErr *password.PolicyError
+	require.ErrorAs(t, err, &policyErr)
+	assert.Equal(t, password.RuleMinLength, policyErr.Violations[0].Rule)
+
+	m.txManager.AssertNotCalled(t, "WithTransaction")
+}
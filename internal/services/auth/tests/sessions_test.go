@@ -0,0 +1,233 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListSessions_ReturnsCurrent(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	sessionID := uuid.New()
+	token, err := testutils.NewIssuer("secret").NewSessionToken(ctx, user, sessionID, time.Hour)
+	require.NoError(t, err)
+	sessions := []sessionModel.Session{
+		{ID: sessionID, UserID: user.ID, UserAgent: "curl/8.0"},
+		{ID: uuid.New(), UserID: user.ID, UserAgent: "Mozilla/5.0"},
+	}
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, sessionID, mock.AnythingOfType("time.Time")).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.sessionRepo.EXPECT().
+		ListActive(ctx, user.ID).
+		Return(sessions, nil)
+
+	got, current, err := service.ListSessions(ctx, token)
+
+	require.NoError(t, err)
+	assert.Equal(t, sessions, got)
+	assert.Equal(t, sessionID, current)
+}
+
+func TestRevokeSession_Success(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	sessionID := uuid.New()
+	token, err := testutils.NewIssuer("secret").NewSessionToken(ctx, user, sessionID, time.Hour)
+	require.NoError(t, err)
+	otherID := uuid.New()
+	familyID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, sessionID, mock.AnythingOfType("time.Time")).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Revoke(ctx, user.ID, otherID).
+		Return(&sessionModel.Session{ID: otherID, UserID: user.ID, FamilyID: familyID}, nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeFamily(ctx, familyID).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeSession(ctx, otherID, testConfig.TokenTTL).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserSessionsRevokedV1
+		})).
+		Return(uuid.New(), nil)
+
+	err = service.RevokeSession(ctx, token, otherID)
+
+	require.NoError(t, err)
+}
+
+func TestRevokeSession_NotFound(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	sessionID := uuid.New()
+	token, err := testutils.NewIssuer("secret").NewSessionToken(ctx, user, sessionID, time.Hour)
+	require.NoError(t, err)
+	otherID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, sessionID, mock.AnythingOfType("time.Time")).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	// Rolled back along with the transaction.
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("eventModel.Event")).
+		Return(uuid.New(), nil)
+
+	m.sessionRepo.EXPECT().
+		Revoke(ctx, user.ID, otherID).
+		Return(nil, storage.ErrSessionNotFound)
+
+	err = service.RevokeSession(ctx, token, otherID)
+
+	assert.ErrorIs(t, err, auth.ErrSessionNotFound)
+	m.refreshTokenRepo.AssertNotCalled(t, "RevokeFamily")
+	m.cacheRepo.AssertNotCalled(t, "RevokeSession")
+}
+
+func TestRevokeAllSessions_Success(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	sessionID := uuid.New()
+	token, err := testutils.NewIssuer("secret").NewSessionToken(ctx, user, sessionID, time.Hour)
+	require.NoError(t, err)
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, sessionID, mock.AnythingOfType("time.Time")).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		GetUserStatus(ctx, user.ID).
+		Return(userModel.StatusActive, 0, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserSessionsRevokedV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.sessionRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeAllForUser(ctx, user.ID).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeUserTokens(ctx, user.ID, mock.AnythingOfType("time.Time"), uuid.Nil, testConfig.TokenTTL).
+		Return(nil)
+
+	err = service.RevokeAllSessions(ctx, token)
+
+	require.NoError(t, err)
+}
+
+func TestLogout_EndsSession(t *testing.T) {
+	ctx := context.Background()
+	user := userModel.User{ID: uuid.New(), Email: "test@example.com"}
+	sessionID := uuid.New()
+	token, err := testutils.NewIssuer("secret").NewSessionToken(ctx, user, sessionID, time.Hour)
+	require.NoError(t, err)
+	familyID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.cacheRepo.EXPECT().
+		IsTokenRevoked(ctx, mock.AnythingOfType("string"), user.ID, sessionID, mock.AnythingOfType("time.Time")).
+		Return(false, nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeToken(ctx, mock.AnythingOfType("string"), mock.AnythingOfType("time.Duration")).
+		Return(nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserLoggedOutV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.sessionRepo.EXPECT().
+		Revoke(ctx, user.ID, sessionID).
+		Return(&sessionModel.Session{ID: sessionID, UserID: user.ID, FamilyID: familyID}, nil)
+
+	m.refreshTokenRepo.EXPECT().
+		RevokeFamily(ctx, familyID).
+		Return(nil)
+
+	m.cacheRepo.EXPECT().
+		RevokeSession(ctx, sessionID, testConfig.TokenTTL).
+		Return(nil)
+
+	// The refresh token is not needed to find the session.
+	err = service.Logout(ctx, token, "")
+
+	require.NoError(t, err)
+	m.refreshTokenRepo.AssertNotCalled(t, "GetByHash")
+}
@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/lib/ratelimiter"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyEmail_Success(t *testing.T) {
+	ctx := context.Background()
+	token := "verification-token"
+	userID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		Consume(ctx, tokenModel.PurposeEmailVerification, opaque.Hash(token)).
+		Return(&tokenModel.UserToken{UserID: userID}, nil)
+
+	m.userRepo.EXPECT().
+		SetStatus(ctx, userID, []userModel.Status{userModel.StatusPendingVerification}, userModel.StatusActive).
+		Return(userModel.StatusPendingVerification, nil)
+
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserStatusChangedV1
+		})).
+		Return(uuid.New(), nil)
+
+	m.cacheRepo.EXPECT().
+		InvalidateUser(ctx, userID).
+		Return(nil)
+
+	err := service.VerifyEmail(ctx, token)
+
+	require.NoError(t, err)
+}
+
+func TestVerifyEmail_InvalidToken(t *testing.T) {
+	ctx := context.Background()
+
+	service, m := newTestService(t)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		Consume(ctx, tokenModel.PurposeEmailVerification, mock.AnythingOfType("string")).
+		Return(nil, storage.ErrTokenNotFound)
+
+	err := service.VerifyEmail(ctx, "unknown")
+
+	require.ErrorIs(t, err, auth.ErrInvalidVerificationToken)
+	m.userRepo.AssertNotCalled(t, "SetStatus")
+}
+
+func TestVerifyEmail_NotPending(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+
+	service, m := newTestService(t)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		Consume(ctx, tokenModel.PurposeEmailVerification, mock.AnythingOfType("string")).
+		Return(&tokenModel.UserToken{UserID: userID}, nil)
+
+	m.userRepo.EXPECT().
+		SetStatus(ctx, userID, mock.Anything, userModel.StatusActive).
+		Return(userModel.StatusDisabled, storage.ErrStatusConflict)
+
+	err := service.VerifyEmail(ctx, "token")
+
+	require.ErrorIs(t, err, auth.ErrNotPendingVerification)
+	m.eventRepo.AssertNotCalled(t, "Save")
+}
+
+func TestResendVerification_Success(t *testing.T) {
+	ctx := context.Background()
+	user := &userModel.User{
+		ID:     uuid.New(),
+		Email:  "test@example.com",
+		Status: userModel.StatusPendingVerification,
+	}
+
+	service, m := newTestService(t)
+
+	m.rateLimiter.EXPECT().
+		AllowVerificationResend(ctx, user.Email, testConfig.ResendVerificationLimit, testConfig.ResendVerificationPeriod).
+		Return(nil)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, user.Email).
+		Return(user, nil)
+
+	m.txManager.EXPECT().
+		WithTransaction(ctx, mock.AnythingOfType("func(context.Context) error")).
+		RunAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		})
+
+	m.userTokenRepo.EXPECT().
+		InvalidateAll(ctx, user.ID, tokenModel.PurposeEmailVerification).
+		Return(nil)
+
+	var saved tokenModel.UserToken
+	m.userTokenRepo.EXPECT().
+		Save(ctx, mock.AnythingOfType("tokenModel.UserToken")).
+		RunAndReturn(func(_ context.Context, token tokenModel.UserToken) (uuid.UUID, error) {
+			saved = token
+			return uuid.New(), nil
+		})
+
+	var published eventModel.Event
+	m.eventRepo.EXPECT().
+		Save(ctx, mock.MatchedBy(func(event eventModel.Event) bool {
+			return event.EventType == eventModel.UserVerificationRequestedV1
+		})).
+		RunAndReturn(func(_ context.Context, e eventModel.Event) (uuid.UUID, error) {
+			published = e
+			return e.ID, nil
+		})
+
+	err := service.ResendVerification(ctx, user.Email)
+
+	require.NoError(t, err)
+
+	var envelope eventModel.Envelope
+	require.NoError(t, json.Unmarshal(published.Payload, &envelope))
+	var data eventModel.UserVerificationRequested
+	require.NoError(t, json.Unmarshal(envelope.Data, &data))
+
+	token, err := testTokenBox.Open(data.EncryptedToken, user.ID[:])
+	require.NoError(t, err)
+	assert.Equal(t, saved.TokenHash, opaque.Hash(string(token)))
+	assert.NotContains(t, string(published.Payload), string(token))
+}
+
+func TestResendVerification_UnknownEmailSucceedsSilently(t *testing.T) {
+	ctx := context.Background()
+
+	service, m := newTestService(t)
+
+	m.rateLimiter.EXPECT().
+		AllowVerificationResend(ctx, "unknown@example.com", mock.Anything, mock.Anything).
+		Return(nil)
+
+	m.userRepo.EXPECT().
+		GetByEmail(ctx, "unknown@example.com").
+		Return(nil, storage.ErrUserNotFound)
+
+	err := service.ResendVerification(ctx, "unknown@example.com")
+
+	require.NoError(t, err)
+	m.userTokenRepo.AssertNotCalled(t, "Save")
+}
+
+func TestResendVerification_RateLimited(t *testing.T) {
+	ctx := context.Background()
+
+	service, m := newTestService(t)
+
+	m.rateLimiter.EXPECT().
+		AllowVerificationResend(ctx, "test@example.com", mock.Anything, mock.Anything).
+		Return(ratelimiter.ErrRateLimited)
+
+	err := service.ResendVerification(ctx, "test@example.com")
+
+	require.ErrorIs(t, err, auth.ErrRateLimited)
+	m.userRepo.AssertNotCalled(t, "GetByEmail")
+}
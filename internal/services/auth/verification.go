@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+	"github.com/Tbits007/auth/internal/lib/ratelimiter"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
+)
+
+// VerifyEmail consumes a verification token and activates its pending
+// account.
+func (au *AuthService) VerifyEmail(
+	ctx   context.Context,
+	token string,
+) error {
+	const op = "AuthService.VerifyEmail"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	var userID uuid.UUID
+
+	err := au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		stored, err := au.userTokenRepo.Consume(ctx, tokenModel.PurposeEmailVerification, opaque.Hash(token))
+		if err != nil {
+			return err
+		}
+		userID = stored.UserID
+
+		return au.changeStatus(
+			ctx,
+			userID,
+			[]userModel.Status{userModel.StatusPendingVerification},
+			userModel.StatusActive,
+			nil,
+		)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrTokenNotFound):
+			log.Info("verification token not found")
+			return fmt.Errorf("%s: %w", op, ErrInvalidVerificationToken)
+		case errors.Is(err, storage.ErrStatusConflict):
+			log.Info("account is not pending verification", sl.Err(err))
+			return fmt.Errorf("%s: %w", op, ErrNotPendingVerification)
+		}
+		log.Error("transaction failed", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	au.evictUser(ctx, log, userID)
+
+	log.Info("email verified", slog.String("user_id", userID.String()))
+
+	return nil
+}
+
+// ResendVerification mails a fresh verification token to a pending
+// account and invalidates the previous ones. To avoid revealing which
+// emails are registered it succeeds silently for unknown and already
+// verified accounts; only the rate limit is reported.
+func (au *AuthService) ResendVerification(
+	ctx   context.Context,
+	email string,
+) error {
+	const op = "AuthService.ResendVerification"
+
+	log := au.log.With(
+		slog.String("op", op),
+	)
+
+	email = userModel.NormalizeEmail(email)
+
+	err := au.rateLimiter.AllowVerificationResend(ctx, email, au.cfg.ResendVerificationLimit, au.cfg.ResendVerificationPeriod)
+	if err != nil {
+		if errors.Is(err, ratelimiter.ErrRateLimited) {
+			log.Info("resend verification rate limited")
+			return fmt.Errorf("%s: %w", op, ErrRateLimited)
+		}
+		log.Error("failed to check rate limit", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	user, err := au.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			log.Info("user not found")
+			return nil
+		}
+		log.Error("failed to get user", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if user.Status != userModel.StatusPendingVerification {
+		log.Info("account is not pending verification", slog.String("status", string(user.Status)))
+		return nil
+	}
+
+	err = au.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := au.userTokenRepo.InvalidateAll(ctx, user.ID, tokenModel.PurposeEmailVerification); err != nil {
+			return err
+		}
+		return au.requestVerification(ctx, user.ID, user.Email)
+	})
+	if err != nil {
+		log.Error("transaction failed", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// requestVerification stores a new verification token and writes the
+// event that carries it to the mailer. It must run inside a transaction.
+func (au *AuthService) requestVerification(
+	ctx    context.Context,
+	userID uuid.UUID,
+	email  string,
+) error {
+	token, expiresAt, err := au.issueUserToken(ctx, userID, tokenModel.PurposeEmailVerification, au.cfg.VerificationTokenTTL)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := au.sealUserToken(userID, token)
+	if err != nil {
+		return err
+	}
+
+	event, err := au.newEvent(ctx, eventModel.UserVerificationRequestedV1, userID, eventModel.UserVerificationRequested{
+		UserID:         userID,
+		Email:          email,
+		EncryptedToken: sealed,
+		ExpiresAt:      expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = au.eventRepo.Save(ctx, event)
+	return err
+}
@@ -0,0 +1,201 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/Tbits007/auth/internal/storage"
+)
+
+var (
+	ErrInvalidTuple = errors.New("invalid relation tuple")
+)
+
+type RelationRepo interface {
+	Write(
+		ctx context.Context,
+		tuple relationModel.Tuple,
+	) error
+
+	Delete(
+		ctx context.Context,
+		tuple relationModel.Tuple,
+	) error
+
+	HasAny(
+		ctx context.Context,
+		object string,
+		subject string,
+		relations []string,
+	) (bool, error)
+}
+
+type CacheRepo interface {
+	GetCheck(
+		ctx   context.Context,
+		tuple relationModel.Tuple,
+	) (bool, int64, error)
+
+	SetCheck(
+		ctx        context.Context,
+		tuple      relationModel.Tuple,
+		allowed    bool,
+		version    int64,
+		expiration time.Duration,
+	) error
+
+	DeleteCheck(
+		ctx   context.Context,
+		tuple relationModel.Tuple,
+	) error
+}
+
+// Authorizer checks that an access token carries a global permission.
+// It is implemented by auth.AuthService.
+type Authorizer interface {
+	Authorize(
+		ctx         context.Context,
+		accessToken string,
+		permission  string,
+	) (*jwt.Claims, error)
+}
+
+type AuthzService struct {
+	log          *slog.Logger
+	relationRepo RelationRepo
+	cacheRepo    CacheRepo
+	authorizer   Authorizer
+	cacheTTL     time.Duration
+}
+
+func NewAuthzService(
+	log          *slog.Logger,
+	relationRepo RelationRepo,
+	cacheRepo    CacheRepo,
+	authorizer   Authorizer,
+	cacheTTL     time.Duration,
+) *AuthzService {
+	return &AuthzService{
+		log:          log,
+		relationRepo: relationRepo,
+		cacheRepo:    cacheRepo,
+		authorizer:   authorizer,
+		cacheTTL:     cacheTTL,
+	}
+}
+
+// Check reports whether subject has relation to object, directly or
+// through a relation that implies it.
+func (az *AuthzService) Check(
+	ctx   context.Context,
+	tuple relationModel.Tuple,
+) (bool, error) {
+	const op = "AuthzService.Check"
+
+	log := az.log.With(
+		slog.String("op", op),
+	)
+
+	if !tuple.Valid() {
+		return false, fmt.Errorf("%s: %w", op, ErrInvalidTuple)
+	}
+
+	cached, version, err := az.cacheRepo.GetCheck(ctx, tuple)
+	if err == nil {
+		log.Debug("cache hit")
+		return cached, nil
+	}
+	if !errors.Is(err, storage.ErrKeyNotFound) {
+		log.Debug("cache error", sl.Err(err))
+	}
+
+	allowed, err := az.relationRepo.HasAny(ctx, tuple.Object, tuple.Subject, relationModel.Granting(tuple.Relation))
+	if err != nil {
+		log.Error("failed to check relation", sl.Err(err))
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// A relation written while HasAny ran bumps the version, so its result
+	// is not cached.
+	if err := az.cacheRepo.SetCheck(ctx, tuple, allowed, version, az.cacheTTL); err != nil {
+		log.Warn("failed to cache check result", sl.Err(err))
+	}
+
+	return allowed, nil
+}
+
+func (az *AuthzService) WriteRelation(
+	ctx         context.Context,
+	accessToken string,
+	tuple       relationModel.Tuple,
+) error {
+	const op = "AuthzService.WriteRelation"
+
+	return az.change(ctx, op, accessToken, tuple, az.relationRepo.Write)
+}
+
+func (az *AuthzService) DeleteRelation(
+	ctx         context.Context,
+	accessToken string,
+	tuple       relationModel.Tuple,
+) error {
+	const op = "AuthzService.DeleteRelation"
+
+	return az.change(ctx, op, accessToken, tuple, az.relationRepo.Delete)
+}
+
+func (az *AuthzService) change(
+	ctx         context.Context,
+	op          string,
+	accessToken string,
+	tuple       relationModel.Tuple,
+	apply       func(ctx context.Context, tuple relationModel.Tuple) error,
+) error {
+	log := az.log.With(
+		slog.String("op", op),
+		slog.String("object", tuple.Object),
+		slog.String("relation", tuple.Relation),
+		slog.String("subject", tuple.Subject),
+	)
+
+	if !tuple.Valid() {
+		return fmt.Errorf("%s: %w", op, ErrInvalidTuple)
+	}
+
+	if _, err := az.authorizer.Authorize(ctx, accessToken, roleModel.PermRelationsWrite); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := apply(ctx, tuple); err != nil {
+		log.Error("failed to change relation", sl.Err(err))
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	az.invalidate(ctx, log, tuple)
+
+	return nil
+}
+
+// invalidate drops cached checks that the tuple can affect: the tuple's
+// own relation and every relation it implies, for the same object and
+// subject.
+func (az *AuthzService) invalidate(
+	ctx   context.Context,
+	log   *slog.Logger,
+	tuple relationModel.Tuple,
+) {
+	for _, relation := range relationModel.Implied(tuple.Relation) {
+		affected := tuple
+		affected.Relation = relation
+		if err := az.cacheRepo.DeleteCheck(ctx, affected); err != nil {
+			log.Warn("failed to evict cached check", sl.Err(err))
+		}
+	}
+}
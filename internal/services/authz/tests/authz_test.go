@@ -0,0 +1,179 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
+	"github.com/Tbits007/auth/internal/lib/jwt"
+	"github.com/Tbits007/auth/internal/services/auth"
+	"github.com/Tbits007/auth/internal/services/authz"
+	"github.com/Tbits007/auth/internal/services/authz/tests/mocks"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testTuple = relationModel.Tuple{
+	Object:   "organization:42",
+	Relation: relationModel.RelationViewer,
+	Subject:  "user:1",
+}
+
+func TestCheck_CacheHit(t *testing.T) {
+	ctx := context.Background()
+
+	mockRelationRepo := mocks.NewMockRelationRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+
+	mockCacheRepo.EXPECT().
+		GetCheck(ctx, testTuple).
+		Return(true, 0, nil)
+
+	service := authz.NewAuthzService(testutils.Log, mockRelationRepo, mockCacheRepo, mockAuthorizer, time.Minute)
+
+	allowed, err := service.Check(ctx, testTuple)
+
+	require.NoError(t, err)
+	assert.True(t, allowed)
+	mockRelationRepo.AssertNotCalled(t, "HasAny")
+}
+
+func TestCheck_CacheMissUsesRewrites(t *testing.T) {
+	ctx := context.Background()
+
+	mockRelationRepo := mocks.NewMockRelationRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+
+	mockCacheRepo.EXPECT().
+		GetCheck(ctx, testTuple).
+		Return(false, 2, storage.ErrKeyNotFound)
+
+	mockRelationRepo.EXPECT().
+		HasAny(ctx, testTuple.Object, testTuple.Subject, []string{
+			relationModel.RelationViewer,
+			relationModel.RelationEditor,
+			relationModel.RelationOwner,
+		}).
+		Return(false, nil)
+
+	mockCacheRepo.EXPECT().
+		SetCheck(ctx, testTuple, false, int64(2), time.Minute).
+		Return(nil)
+
+	service := authz.NewAuthzService(testutils.Log, mockRelationRepo, mockCacheRepo, mockAuthorizer, time.Minute)
+
+	allowed, err := service.Check(ctx, testTuple)
+
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestCheck_InvalidTuple(t *testing.T) {
+	ctx := context.Background()
+
+	mockRelationRepo := mocks.NewMockRelationRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+
+	service := authz.NewAuthzService(testutils.Log, mockRelationRepo, mockCacheRepo, mockAuthorizer, time.Minute)
+
+	for _, tuple := range []relationModel.Tuple{
+		{Object: "organization:42", Relation: "viewer"},
+		{Object: "organization:42", Relation: "member", Subject: "user:1"},
+		// Separators inside a reference would make cache keys ambiguous.
+		{Object: "organization:42#viewer@user:1", Relation: "viewer", Subject: "user:1"},
+	} {
+		_, err := service.Check(ctx, tuple)
+
+		assert.ErrorIs(t, err, authz.ErrInvalidTuple, tuple)
+	}
+}
+
+func TestWriteRelation_InvalidatesImpliedChecks(t *testing.T) {
+	ctx := context.Background()
+	tuple := relationModel.Tuple{
+		Object:   "organization:42",
+		Relation: relationModel.RelationOwner,
+		Subject:  "user:1",
+	}
+
+	mockRelationRepo := mocks.NewMockRelationRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermRelationsWrite).
+		Return(&jwt.Claims{}, nil)
+
+	mockRelationRepo.EXPECT().
+		Write(ctx, tuple).
+		Return(nil)
+
+	for _, relation := range []string{
+		relationModel.RelationOwner,
+		relationModel.RelationEditor,
+		relationModel.RelationViewer,
+	} {
+		affected := tuple
+		affected.Relation = relation
+		mockCacheRepo.EXPECT().
+			DeleteCheck(ctx, affected).
+			Return(nil)
+	}
+
+	service := authz.NewAuthzService(testutils.Log, mockRelationRepo, mockCacheRepo, mockAuthorizer, time.Minute)
+
+	err := service.WriteRelation(ctx, "token", tuple)
+
+	require.NoError(t, err)
+}
+
+func TestDeleteRelation_PermissionDenied(t *testing.T) {
+	ctx := context.Background()
+
+	mockRelationRepo := mocks.NewMockRelationRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermRelationsWrite).
+		Return(nil, auth.ErrPermissionDenied)
+
+	service := authz.NewAuthzService(testutils.Log, mockRelationRepo, mockCacheRepo, mockAuthorizer, time.Minute)
+
+	err := service.DeleteRelation(ctx, "token", testTuple)
+
+	assert.ErrorIs(t, err, auth.ErrPermissionDenied)
+	mockRelationRepo.AssertNotCalled(t, "Delete")
+}
+
+func TestWriteRelation_RepoError(t *testing.T) {
+	ctx := context.Background()
+	repoErr := errors.New("db error")
+
+	mockRelationRepo := mocks.NewMockRelationRepo(t)
+	mockCacheRepo := mocks.NewMockCacheRepo(t)
+	mockAuthorizer := mocks.NewMockAuthorizer(t)
+
+	mockAuthorizer.EXPECT().
+		Authorize(ctx, "token", roleModel.PermRelationsWrite).
+		Return(&jwt.Claims{}, nil)
+
+	mockRelationRepo.EXPECT().
+		Write(ctx, testTuple).
+		Return(repoErr)
+
+	service := authz.NewAuthzService(testutils.Log, mockRelationRepo, mockCacheRepo, mockAuthorizer, time.Minute)
+
+	err := service.WriteRelation(ctx, "token", testTuple)
+
+	assert.ErrorIs(t, err, repoErr)
+	mockCacheRepo.AssertNotCalled(t, "DeleteCheck")
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	jwt "github.com/Tbits007/auth/internal/lib/jwt"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockAuthorizer is an autogenerated mock type for the Authorizer type
+type MockAuthorizer struct {
+	mock.Mock
+}
+
+type MockAuthorizer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuthorizer) EXPECT() *MockAuthorizer_Expecter {
+	return &MockAuthorizer_Expecter{mock: &_m.Mock}
+}
+
+// Authorize provides a mock function with given fields: ctx, accessToken, permission
+func (_m *MockAuthorizer) Authorize(ctx context.Context, accessToken string, permission string) (*jwt.Claims, error) {
+	ret := _m.Called(ctx, accessToken, permission)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Authorize")
+	}
+
+	var r0 *jwt.Claims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (*jwt.Claims, error)); ok {
+		return rf(ctx, accessToken, permission)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) *jwt.Claims); ok {
+		r0 = rf(ctx, accessToken, permission)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*jwt.Claims)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, accessToken, permission)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockAuthorizer_Authorize_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Authorize'
+type MockAuthorizer_Authorize_Call struct {
+	*mock.Call
+}
+
+// Authorize is a helper method to define mock.On call
+//   - ctx context.Context
+//   - accessToken string
+//   - permission string
+func (_e *MockAuthorizer_Expecter) Authorize(ctx interface{}, accessToken interface{}, permission interface{}) *MockAuthorizer_Authorize_Call {
+	return &MockAuthorizer_Authorize_Call{Call: _e.mock.On("Authorize", ctx, accessToken, permission)}
+}
+
+func (_c *MockAuthorizer_Authorize_Call) Run(run func(ctx context.Context, accessToken string, permission string)) *MockAuthorizer_Authorize_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockAuthorizer_Authorize_Call) Return(_a0 *jwt.Claims, _a1 error) *MockAuthorizer_Authorize_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockAuthorizer_Authorize_Call) RunAndReturn(run func(context.Context, string, string) (*jwt.Claims, error)) *MockAuthorizer_Authorize_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockAuthorizer creates a new instance of MockAuthorizer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuthorizer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuthorizer {
+	mock := &MockAuthorizer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
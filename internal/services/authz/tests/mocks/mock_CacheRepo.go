@@ -0,0 +1,200 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	relationModel "github.com/Tbits007/auth/internal/domain/models/relationModel"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// MockCacheRepo is an autogenerated mock type for the CacheRepo type
+type MockCacheRepo struct {
+	mock.Mock
+}
+
+type MockCacheRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCacheRepo) EXPECT() *MockCacheRepo_Expecter {
+	return &MockCacheRepo_Expecter{mock: &_m.Mock}
+}
+
+// DeleteCheck provides a mock function with given fields: ctx, tuple
+func (_m *MockCacheRepo) DeleteCheck(ctx context.Context, tuple relationModel.Tuple) error {
+	ret := _m.Called(ctx, tuple)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, relationModel.Tuple) error); ok {
+		r0 = rf(ctx, tuple)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_DeleteCheck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCheck'
+type MockCacheRepo_DeleteCheck_Call struct {
+	*mock.Call
+}
+
+// DeleteCheck is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tuple relationModel.Tuple
+func (_e *MockCacheRepo_Expecter) DeleteCheck(ctx interface{}, tuple interface{}) *MockCacheRepo_DeleteCheck_Call {
+	return &MockCacheRepo_DeleteCheck_Call{Call: _e.mock.On("DeleteCheck", ctx, tuple)}
+}
+
+func (_c *MockCacheRepo_DeleteCheck_Call) Run(run func(ctx context.Context, tuple relationModel.Tuple)) *MockCacheRepo_DeleteCheck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(relationModel.Tuple))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_DeleteCheck_Call) Return(_a0 error) *MockCacheRepo_DeleteCheck_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_DeleteCheck_Call) RunAndReturn(run func(context.Context, relationModel.Tuple) error) *MockCacheRepo_DeleteCheck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetCheck provides a mock function with given fields: ctx, tuple
+func (_m *MockCacheRepo) GetCheck(ctx context.Context, tuple relationModel.Tuple) (bool, int64, error) {
+	ret := _m.Called(ctx, tuple)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCheck")
+	}
+
+	var r0 bool
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, relationModel.Tuple) (bool, int64, error)); ok {
+		return rf(ctx, tuple)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, relationModel.Tuple) bool); ok {
+		r0 = rf(ctx, tuple)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, relationModel.Tuple) int64); ok {
+		r1 = rf(ctx, tuple)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, relationModel.Tuple) error); ok {
+		r2 = rf(ctx, tuple)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// MockCacheRepo_GetCheck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetCheck'
+type MockCacheRepo_GetCheck_Call struct {
+	*mock.Call
+}
+
+// GetCheck is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tuple relationModel.Tuple
+func (_e *MockCacheRepo_Expecter) GetCheck(ctx interface{}, tuple interface{}) *MockCacheRepo_GetCheck_Call {
+	return &MockCacheRepo_GetCheck_Call{Call: _e.mock.On("GetCheck", ctx, tuple)}
+}
+
+func (_c *MockCacheRepo_GetCheck_Call) Run(run func(ctx context.Context, tuple relationModel.Tuple)) *MockCacheRepo_GetCheck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(relationModel.Tuple))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_GetCheck_Call) Return(_a0 bool, _a1 int64, _a2 error) *MockCacheRepo_GetCheck_Call {
+	_c.Call.Return(_a0, _a1, _a2)
+	return _c
+}
+
+func (_c *MockCacheRepo_GetCheck_Call) RunAndReturn(run func(context.Context, relationModel.Tuple) (bool, int64, error)) *MockCacheRepo_GetCheck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SetCheck provides a mock function with given fields: ctx, tuple, allowed, version, expiration
+func (_m *MockCacheRepo) SetCheck(ctx context.Context, tuple relationModel.Tuple, allowed bool, version int64, expiration time.Duration) error {
+	ret := _m.Called(ctx, tuple, allowed, version, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetCheck")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, relationModel.Tuple, bool, int64, time.Duration) error); ok {
+		r0 = rf(ctx, tuple, allowed, version, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockCacheRepo_SetCheck_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetCheck'
+type MockCacheRepo_SetCheck_Call struct {
+	*mock.Call
+}
+
+// SetCheck is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tuple relationModel.Tuple
+//   - allowed bool
+//   - version int64
+//   - expiration time.Duration
+func (_e *MockCacheRepo_Expecter) SetCheck(ctx interface{}, tuple interface{}, allowed interface{}, version interface{}, expiration interface{}) *MockCacheRepo_SetCheck_Call {
+	return &MockCacheRepo_SetCheck_Call{Call: _e.mock.On("SetCheck", ctx, tuple, allowed, version, expiration)}
+}
+
+func (_c *MockCacheRepo_SetCheck_Call) Run(run func(ctx context.Context, tuple relationModel.Tuple, allowed bool, version int64, expiration time.Duration)) *MockCacheRepo_SetCheck_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(relationModel.Tuple), args[2].(bool), args[3].(int64), args[4].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockCacheRepo_SetCheck_Call) Return(_a0 error) *MockCacheRepo_SetCheck_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockCacheRepo_SetCheck_Call) RunAndReturn(run func(context.Context, relationModel.Tuple, bool, int64, time.Duration) error) *MockCacheRepo_SetCheck_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCacheRepo creates a new instance of MockCacheRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCacheRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCacheRepo {
+	mock := &MockCacheRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
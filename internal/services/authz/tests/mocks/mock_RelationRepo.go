@@ -0,0 +1,190 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	relationModel "github.com/Tbits007/auth/internal/domain/models/relationModel"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockRelationRepo is an autogenerated mock type for the RelationRepo type
+type MockRelationRepo struct {
+	mock.Mock
+}
+
+type MockRelationRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockRelationRepo) EXPECT() *MockRelationRepo_Expecter {
+	return &MockRelationRepo_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function with given fields: ctx, tuple
+func (_m *MockRelationRepo) Delete(ctx context.Context, tuple relationModel.Tuple) error {
+	ret := _m.Called(ctx, tuple)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, relationModel.Tuple) error); ok {
+		r0 = rf(ctx, tuple)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRelationRepo_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockRelationRepo_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tuple relationModel.Tuple
+func (_e *MockRelationRepo_Expecter) Delete(ctx interface{}, tuple interface{}) *MockRelationRepo_Delete_Call {
+	return &MockRelationRepo_Delete_Call{Call: _e.mock.On("Delete", ctx, tuple)}
+}
+
+func (_c *MockRelationRepo_Delete_Call) Run(run func(ctx context.Context, tuple relationModel.Tuple)) *MockRelationRepo_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(relationModel.Tuple))
+	})
+	return _c
+}
+
+func (_c *MockRelationRepo_Delete_Call) Return(_a0 error) *MockRelationRepo_Delete_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRelationRepo_Delete_Call) RunAndReturn(run func(context.Context, relationModel.Tuple) error) *MockRelationRepo_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// HasAny provides a mock function with given fields: ctx, object, subject, relations
+func (_m *MockRelationRepo) HasAny(ctx context.Context, object string, subject string, relations []string) (bool, error) {
+	ret := _m.Called(ctx, object, subject, relations)
+
+	if len(ret) == 0 {
+		panic("no return value specified for HasAny")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) (bool, error)); ok {
+		return rf(ctx, object, subject, relations)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []string) bool); ok {
+		r0 = rf(ctx, object, subject, relations)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []string) error); ok {
+		r1 = rf(ctx, object, subject, relations)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockRelationRepo_HasAny_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'HasAny'
+type MockRelationRepo_HasAny_Call struct {
+	*mock.Call
+}
+
+// HasAny is a helper method to define mock.On call
+//   - ctx context.Context
+//   - object string
+//   - subject string
+//   - relations []string
+func (_e *MockRelationRepo_Expecter) HasAny(ctx interface{}, object interface{}, subject interface{}, relations interface{}) *MockRelationRepo_HasAny_Call {
+	return &MockRelationRepo_HasAny_Call{Call: _e.mock.On("HasAny", ctx, object, subject, relations)}
+}
+
+func (_c *MockRelationRepo_HasAny_Call) Run(run func(ctx context.Context, object string, subject string, relations []string)) *MockRelationRepo_HasAny_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(string), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *MockRelationRepo_HasAny_Call) Return(_a0 bool, _a1 error) *MockRelationRepo_HasAny_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockRelationRepo_HasAny_Call) RunAndReturn(run func(context.Context, string, string, []string) (bool, error)) *MockRelationRepo_HasAny_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Write provides a mock function with given fields: ctx, tuple
+func (_m *MockRelationRepo) Write(ctx context.Context, tuple relationModel.Tuple) error {
+	ret := _m.Called(ctx, tuple)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Write")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, relationModel.Tuple) error); ok {
+		r0 = rf(ctx, tuple)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockRelationRepo_Write_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Write'
+type MockRelationRepo_Write_Call struct {
+	*mock.Call
+}
+
+// Write is a helper method to define mock.On call
+//   - ctx context.Context
+//   - tuple relationModel.Tuple
+func (_e *MockRelationRepo_Expecter) Write(ctx interface{}, tuple interface{}) *MockRelationRepo_Write_Call {
+	return &MockRelationRepo_Write_Call{Call: _e.mock.On("Write", ctx, tuple)}
+}
+
+func (_c *MockRelationRepo_Write_Call) Run(run func(ctx context.Context, tuple relationModel.Tuple)) *MockRelationRepo_Write_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(relationModel.Tuple))
+	})
+	return _c
+}
+
+func (_c *MockRelationRepo_Write_Call) Return(_a0 error) *MockRelationRepo_Write_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockRelationRepo_Write_Call) RunAndReturn(run func(context.Context, relationModel.Tuple) error) *MockRelationRepo_Write_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockRelationRepo creates a new instance of MockRelationRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockRelationRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockRelationRepo {
+	mock := &MockRelationRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,237 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/lib/logger/sl"
+	"github.com/google/uuid"
+)
+
+type Publisher interface {
+	Publish(ctx context.Context, event eventModel.Event) error
+}
+
+type EventRepo interface {
+	ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]eventModel.Event, error)
+	MarkProcessed(ctx context.Context, eventID uuid.UUID) error
+	MarkRetry(ctx context.Context, eventID uuid.UUID, nextAttemptAt time.Time, lastError string) error
+	MarkFailed(ctx context.Context, eventID uuid.UUID, lastError string) error
+	DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type Config struct {
+	BatchSize    int
+	PollInterval time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	// ClaimTTL is how long claimed events are hidden from other relays.
+	// Events whose outcome is not recorded by then are claimed again, so
+	// it should exceed the time a batch takes to publish.
+	ClaimTTL     time.Duration
+
+	// Retention is how long processed events are kept; zero keeps them
+	// forever.
+	Retention        time.Duration
+	CleanupInterval  time.Duration
+	CleanupBatchSize int
+}
+
+// Relay moves pending outbox events to a Publisher. Delivery is
+// at-least-once: an event is marked processed only after Publish succeeds.
+type Relay struct {
+	log       *slog.Logger
+	eventRepo EventRepo
+	publisher Publisher
+	cfg       Config
+	now       func() time.Time
+
+	// ctx is passed to in-flight work and is cancelled only when Stop
+	// runs out of time; stop ends the polling loop between batches.
+	ctx    context.Context
+	cancel context.CancelFunc
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+func NewRelay(
+	log *slog.Logger,
+	eventRepo EventRepo,
+	publisher Publisher,
+	cfg Config,
+) *Relay {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Relay{
+		log:       log,
+		eventRepo: eventRepo,
+		publisher: publisher,
+		cfg:       cfg,
+		now:       time.Now,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+}
+
+// Run polls the outbox until Stop is called. A full batch is followed
+// immediately by the next one so that a backlog drains without waiting
+// for the ticker. Expired processed events are pruned every
+// CleanupInterval. A batch that is in flight when Stop is called runs to
+// completion.
+func (r *Relay) Run() {
+	const op = "outbox.Relay.Run"
+
+	log := r.log.With(slog.String("op", op))
+	defer close(r.done)
+
+	log.Info("outbox relay starting")
+
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var lastCleanup time.Time
+
+	for {
+		if r.cfg.Retention > 0 && r.now().Sub(lastCleanup) >= r.cfg.CleanupInterval {
+			lastCleanup = r.now()
+			if _, err := r.Cleanup(r.ctx); err != nil && r.ctx.Err() == nil {
+				log.Error("failed to clean up outbox", sl.Err(err))
+			}
+		}
+
+		n, err := r.ProcessBatch(r.ctx)
+		if err != nil && r.ctx.Err() == nil {
+			log.Error("failed to process outbox batch", sl.Err(err))
+		}
+
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		if err == nil && n == r.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-r.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Stop waits for the in-flight batch to finish. If shutdownCtx expires
+// first, the batch is cancelled and its claimed events are retried by the
+// next relay. Stop returns only after Run has returned.
+func (r *Relay) Stop(shutdownCtx context.Context) {
+	const op = "outbox.Relay.Stop"
+
+	log := r.log.With(slog.String("op", op))
+	log.Info("stopping outbox relay")
+
+	close(r.stop)
+
+	select {
+	case <-r.done:
+		log.Info("outbox relay stopped")
+	case <-shutdownCtx.Done():
+		log.Info("forcing outbox relay stop")
+		r.cancel()
+		<-r.done
+	}
+
+	r.cancel()
+}
+
+// ProcessBatch claims one batch of due events and publishes them. Claiming
+// leases the events for ClaimTTL, which keeps concurrent relays away from
+// them without holding row locks while publishing. It returns the number
+// of claimed events.
+func (r *Relay) ProcessBatch(ctx context.Context) (int, error) {
+	const op = "outbox.Relay.ProcessBatch"
+
+	log := r.log.With(slog.String("op", op))
+
+	events, err := r.eventRepo.ClaimPending(ctx, r.cfg.BatchSize, r.cfg.ClaimTTL)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	for _, event := range events {
+		if err := r.deliver(ctx, log, event); err != nil {
+			return len(events), fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	return len(events), nil
+}
+
+// Cleanup deletes processed events older than Retention in batches of
+// CleanupBatchSize and returns the number of deleted events.
+func (r *Relay) Cleanup(ctx context.Context) (int64, error) {
+	const op = "outbox.Relay.Cleanup"
+
+	before := r.now().Add(-r.cfg.Retention)
+
+	var total int64
+	for {
+		n, err := r.eventRepo.DeleteProcessedBefore(ctx, before, r.cfg.CleanupBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("%s: %w", op, err)
+		}
+		total += n
+
+		if n < int64(r.cfg.CleanupBatchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		r.log.Info("outbox cleaned up", slog.String("op", op), slog.Int64("deleted", total))
+	}
+
+	return total, nil
+}
+
+func (r *Relay) deliver(ctx context.Context, log *slog.Logger, event eventModel.Event) error {
+	log = log.With(
+		slog.String("event_id", event.ID.String()),
+		slog.String("event_type", event.EventType),
+	)
+
+	pubErr := r.publisher.Publish(ctx, event)
+	if pubErr == nil {
+		return r.eventRepo.MarkProcessed(ctx, event.ID)
+	}
+
+	attempts := event.Attempts + 1
+	if attempts >= r.cfg.MaxAttempts {
+		log.Error("outbox event failed permanently", slog.Int("attempts", attempts), sl.Err(pubErr))
+		return r.eventRepo.MarkFailed(ctx, event.ID, pubErr.Error())
+	}
+
+	log.Warn("failed to publish outbox event", slog.Int("attempts", attempts), sl.Err(pubErr))
+	return r.eventRepo.MarkRetry(ctx, event.ID, r.now().Add(r.backoff(attempts)), pubErr.Error())
+}
+
+// backoff doubles the delay for every failed attempt, starting at
+// BaseBackoff and capped at MaxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= r.cfg.MaxBackoff {
+			return r.cfg.MaxBackoff
+		}
+	}
+
+	return min(delay, r.cfg.MaxBackoff)
+}
@@ -0,0 +1,303 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	eventModel "github.com/Tbits007/auth/internal/domain/models/eventModel"
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	uuid "github.com/google/uuid"
+)
+
+// MockEventRepo is an autogenerated mock type for the EventRepo type
+type MockEventRepo struct {
+	mock.Mock
+}
+
+type MockEventRepo_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockEventRepo) EXPECT() *MockEventRepo_Expecter {
+	return &MockEventRepo_Expecter{mock: &_m.Mock}
+}
+
+// ClaimPending provides a mock function with given fields: ctx, limit, lease
+func (_m *MockEventRepo) ClaimPending(ctx context.Context, limit int, lease time.Duration) ([]eventModel.Event, error) {
+	ret := _m.Called(ctx, limit, lease)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClaimPending")
+	}
+
+	var r0 []eventModel.Event
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) ([]eventModel.Event, error)); ok {
+		return rf(ctx, limit, lease)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int, time.Duration) []eventModel.Event); ok {
+		r0 = rf(ctx, limit, lease)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]eventModel.Event)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int, time.Duration) error); ok {
+		r1 = rf(ctx, limit, lease)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventRepo_ClaimPending_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ClaimPending'
+type MockEventRepo_ClaimPending_Call struct {
+	*mock.Call
+}
+
+// ClaimPending is a helper method to define mock.On call
+//   - ctx context.Context
+//   - limit int
+//   - lease time.Duration
+func (_e *MockEventRepo_Expecter) ClaimPending(ctx interface{}, limit interface{}, lease interface{}) *MockEventRepo_ClaimPending_Call {
+	return &MockEventRepo_ClaimPending_Call{Call: _e.mock.On("ClaimPending", ctx, limit, lease)}
+}
+
+func (_c *MockEventRepo_ClaimPending_Call) Run(run func(ctx context.Context, limit int, lease time.Duration)) *MockEventRepo_ClaimPending_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int), args[2].(time.Duration))
+	})
+	return _c
+}
+
+func (_c *MockEventRepo_ClaimPending_Call) Return(_a0 []eventModel.Event, _a1 error) *MockEventRepo_ClaimPending_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventRepo_ClaimPending_Call) RunAndReturn(run func(context.Context, int, time.Duration) ([]eventModel.Event, error)) *MockEventRepo_ClaimPending_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteProcessedBefore provides a mock function with given fields: ctx, before, limit
+func (_m *MockEventRepo) DeleteProcessedBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	ret := _m.Called(ctx, before, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteProcessedBefore")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) (int64, error)); ok {
+		return rf(ctx, before, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) int64); ok {
+		r0 = rf(ctx, before, limit)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockEventRepo_DeleteProcessedBefore_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteProcessedBefore'
+type MockEventRepo_DeleteProcessedBefore_Call struct {
+	*mock.Call
+}
+
+// DeleteProcessedBefore is a helper method to define mock.On call
+//   - ctx context.Context
+//   - before time.Time
+//   - limit int
+func (_e *MockEventRepo_Expecter) DeleteProcessedBefore(ctx interface{}, before interface{}, limit interface{}) *MockEventRepo_DeleteProcessedBefore_Call {
+	return &MockEventRepo_DeleteProcessedBefore_Call{Call: _e.mock.On("DeleteProcessedBefore", ctx, before, limit)}
+}
+
+func (_c *MockEventRepo_DeleteProcessedBefore_Call) Run(run func(ctx context.Context, before time.Time, limit int)) *MockEventRepo_DeleteProcessedBefore_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(time.Time), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *MockEventRepo_DeleteProcessedBefore_Call) Return(_a0 int64, _a1 error) *MockEventRepo_DeleteProcessedBefore_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockEventRepo_DeleteProcessedBefore_Call) RunAndReturn(run func(context.Context, time.Time, int) (int64, error)) *MockEventRepo_DeleteProcessedBefore_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkFailed provides a mock function with given fields: ctx, eventID, lastError
+func (_m *MockEventRepo) MarkFailed(ctx context.Context, eventID uuid.UUID, lastError string) error {
+	ret := _m.Called(ctx, eventID, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkFailed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, string) error); ok {
+		r0 = rf(ctx, eventID, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockEventRepo_MarkFailed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkFailed'
+type MockEventRepo_MarkFailed_Call struct {
+	*mock.Call
+}
+
+// MarkFailed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID uuid.UUID
+//   - lastError string
+func (_e *MockEventRepo_Expecter) MarkFailed(ctx interface{}, eventID interface{}, lastError interface{}) *MockEventRepo_MarkFailed_Call {
+	return &MockEventRepo_MarkFailed_Call{Call: _e.mock.On("MarkFailed", ctx, eventID, lastError)}
+}
+
+func (_c *MockEventRepo_MarkFailed_Call) Run(run func(ctx context.Context, eventID uuid.UUID, lastError string)) *MockEventRepo_MarkFailed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(string))
+	})
+	return _c
+}
+
+func (_c *MockEventRepo_MarkFailed_Call) Return(_a0 error) *MockEventRepo_MarkFailed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockEventRepo_MarkFailed_Call) RunAndReturn(run func(context.Context, uuid.UUID, string) error) *MockEventRepo_MarkFailed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkProcessed provides a mock function with given fields: ctx, eventID
+func (_m *MockEventRepo) MarkProcessed(ctx context.Context, eventID uuid.UUID) error {
+	ret := _m.Called(ctx, eventID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkProcessed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockEventRepo_MarkProcessed_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkProcessed'
+type MockEventRepo_MarkProcessed_Call struct {
+	*mock.Call
+}
+
+// MarkProcessed is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID uuid.UUID
+func (_e *MockEventRepo_Expecter) MarkProcessed(ctx interface{}, eventID interface{}) *MockEventRepo_MarkProcessed_Call {
+	return &MockEventRepo_MarkProcessed_Call{Call: _e.mock.On("MarkProcessed", ctx, eventID)}
+}
+
+func (_c *MockEventRepo_MarkProcessed_Call) Run(run func(ctx context.Context, eventID uuid.UUID)) *MockEventRepo_MarkProcessed_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID))
+	})
+	return _c
+}
+
+func (_c *MockEventRepo_MarkProcessed_Call) Return(_a0 error) *MockEventRepo_MarkProcessed_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockEventRepo_MarkProcessed_Call) RunAndReturn(run func(context.Context, uuid.UUID) error) *MockEventRepo_MarkProcessed_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MarkRetry provides a mock function with given fields: ctx, eventID, nextAttemptAt, lastError
+func (_m *MockEventRepo) MarkRetry(ctx context.Context, eventID uuid.UUID, nextAttemptAt time.Time, lastError string) error {
+	ret := _m.Called(ctx, eventID, nextAttemptAt, lastError)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkRetry")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time, string) error); ok {
+		r0 = rf(ctx, eventID, nextAttemptAt, lastError)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockEventRepo_MarkRetry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MarkRetry'
+type MockEventRepo_MarkRetry_Call struct {
+	*mock.Call
+}
+
+// MarkRetry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - eventID uuid.UUID
+//   - nextAttemptAt time.Time
+//   - lastError string
+func (_e *MockEventRepo_Expecter) MarkRetry(ctx interface{}, eventID interface{}, nextAttemptAt interface{}, lastError interface{}) *MockEventRepo_MarkRetry_Call {
+	return &MockEventRepo_MarkRetry_Call{Call: _e.mock.On("MarkRetry", ctx, eventID, nextAttemptAt, lastError)}
+}
+
+func (_c *MockEventRepo_MarkRetry_Call) Run(run func(ctx context.Context, eventID uuid.UUID, nextAttemptAt time.Time, lastError string)) *MockEventRepo_MarkRetry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(uuid.UUID), args[2].(time.Time), args[3].(string))
+	})
+	return _c
+}
+
+func (_c *MockEventRepo_MarkRetry_Call) Return(_a0 error) *MockEventRepo_MarkRetry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockEventRepo_MarkRetry_Call) RunAndReturn(run func(context.Context, uuid.UUID, time.Time, string) error) *MockEventRepo_MarkRetry_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockEventRepo creates a new instance of MockEventRepo. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockEventRepo(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockEventRepo {
+	mock := &MockEventRepo{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
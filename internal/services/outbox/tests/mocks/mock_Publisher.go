@@ -0,0 +1,84 @@
+// Code generated by mockery. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	eventModel "github.com/Tbits007/auth/internal/domain/models/eventModel"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MockPublisher is an autogenerated mock type for the Publisher type
+type MockPublisher struct {
+	mock.Mock
+}
+
+type MockPublisher_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockPublisher) EXPECT() *MockPublisher_Expecter {
+	return &MockPublisher_Expecter{mock: &_m.Mock}
+}
+
+// Publish provides a mock function with given fields: ctx, event
+func (_m *MockPublisher) Publish(ctx context.Context, event eventModel.Event) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, eventModel.Event) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockPublisher_Publish_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Publish'
+type MockPublisher_Publish_Call struct {
+	*mock.Call
+}
+
+// Publish is a helper method to define mock.On call
+//   - ctx context.Context
+//   - event eventModel.Event
+func (_e *MockPublisher_Expecter) Publish(ctx interface{}, event interface{}) *MockPublisher_Publish_Call {
+	return &MockPublisher_Publish_Call{Call: _e.mock.On("Publish", ctx, event)}
+}
+
+func (_c *MockPublisher_Publish_Call) Run(run func(ctx context.Context, event eventModel.Event)) *MockPublisher_Publish_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(eventModel.Event))
+	})
+	return _c
+}
+
+func (_c *MockPublisher_Publish_Call) Return(_a0 error) *MockPublisher_Publish_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *MockPublisher_Publish_Call) RunAndReturn(run func(context.Context, eventModel.Event) error) *MockPublisher_Publish_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockPublisher creates a new instance of MockPublisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockPublisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockPublisher {
+	mock := &MockPublisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,258 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/services/outbox"
+	"github.com/Tbits007/auth/internal/services/outbox/tests/mocks"
+	"github.com/Tbits007/auth/internal/services/testutils"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testConfig = outbox.Config{
+	BatchSize:    10,
+	PollInterval: time.Second,
+	MaxAttempts:  3,
+	BaseBackoff:  time.Second,
+	MaxBackoff:   time.Minute,
+	ClaimTTL:     time.Minute,
+}
+
+func TestProcessBatch_Success(t *testing.T) {
+	ctx := context.Background()
+	events := []eventModel.Event{
+		{ID: uuid.New(), EventType: "UserCreated", Status: eventModel.PENDING},
+		{ID: uuid.New(), EventType: "UserLoggedIn", Status: eventModel.PENDING},
+	}
+
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	mockEventRepo.EXPECT().
+		ClaimPending(ctx, testConfig.BatchSize, testConfig.ClaimTTL).
+		Return(events, nil)
+
+	for _, event := range events {
+		mockPublisher.EXPECT().
+			Publish(ctx, event).
+			Return(nil)
+
+		mockEventRepo.EXPECT().
+			MarkProcessed(ctx, event.ID).
+			Return(nil)
+	}
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, testConfig)
+
+	n, err := relay.ProcessBatch(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, len(events), n)
+}
+
+func TestProcessBatch_PublishErrorSchedulesRetry(t *testing.T) {
+	ctx := context.Background()
+	event := eventModel.Event{ID: uuid.New(), EventType: "UserCreated", Attempts: 1}
+	publishErr := errors.New("broker unavailable")
+
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	mockEventRepo.EXPECT().
+		ClaimPending(ctx, testConfig.BatchSize, testConfig.ClaimTTL).
+		Return([]eventModel.Event{event}, nil)
+
+	mockPublisher.EXPECT().
+		Publish(ctx, event).
+		Return(publishErr)
+
+	before := time.Now()
+	mockEventRepo.EXPECT().
+		MarkRetry(ctx, event.ID, mock.MatchedBy(func(next time.Time) bool {
+			// second attempt: base backoff doubled once
+			delay := next.Sub(before)
+			return delay >= 2*time.Second && delay < 3*time.Second
+		}), publishErr.Error()).
+		Return(nil)
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, testConfig)
+
+	n, err := relay.ProcessBatch(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestProcessBatch_MaxAttemptsMarksFailed(t *testing.T) {
+	ctx := context.Background()
+	event := eventModel.Event{ID: uuid.New(), EventType: "UserCreated", Attempts: testConfig.MaxAttempts - 1}
+	publishErr := errors.New("broker unavailable")
+
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	mockEventRepo.EXPECT().
+		ClaimPending(ctx, testConfig.BatchSize, testConfig.ClaimTTL).
+		Return([]eventModel.Event{event}, nil)
+
+	mockPublisher.EXPECT().
+		Publish(ctx, event).
+		Return(publishErr)
+
+	mockEventRepo.EXPECT().
+		MarkFailed(ctx, event.ID, publishErr.Error()).
+		Return(nil)
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, testConfig)
+
+	_, err := relay.ProcessBatch(ctx)
+
+	require.NoError(t, err)
+}
+
+func TestProcessBatch_ClaimError(t *testing.T) {
+	ctx := context.Background()
+	claimErr := errors.New("db error")
+
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	mockEventRepo.EXPECT().
+		ClaimPending(ctx, testConfig.BatchSize, testConfig.ClaimTTL).
+		Return(nil, claimErr)
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, testConfig)
+
+	n, err := relay.ProcessBatch(ctx)
+
+	assert.ErrorIs(t, err, claimErr)
+	assert.Zero(t, n)
+}
+
+func TestRelay_Stop(t *testing.T) {
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	mockEventRepo.EXPECT().
+		ClaimPending(mock.Anything, testConfig.BatchSize, testConfig.ClaimTTL).
+		Return(nil, nil).
+		Maybe()
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, testConfig)
+
+	go relay.Run()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	relay.Stop(ctx)
+
+	assert.NoError(t, ctx.Err())
+}
+
+func TestRelay_StopWaitsForInFlightBatch(t *testing.T) {
+	event := eventModel.Event{ID: uuid.New(), EventType: "UserCreated", Status: eventModel.PENDING}
+	claimed := make(chan struct{})
+	release := make(chan struct{})
+
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	mockEventRepo.EXPECT().
+		ClaimPending(mock.Anything, testConfig.BatchSize, testConfig.ClaimTTL).
+		Return([]eventModel.Event{event}, nil).
+		Once()
+
+	mockPublisher.EXPECT().
+		Publish(mock.Anything, event).
+		RunAndReturn(func(ctx context.Context, _ eventModel.Event) error {
+			close(claimed)
+			<-release
+			return ctx.Err()
+		})
+
+	mockEventRepo.EXPECT().
+		MarkProcessed(mock.Anything, event.ID).
+		Return(nil)
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, testConfig)
+
+	go relay.Run()
+	<-claimed
+
+	stopped := make(chan struct{})
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		relay.Stop(ctx)
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		t.Fatal("Stop returned before the in-flight batch finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	<-stopped
+}
+
+func TestCleanup_DeletesInBatches(t *testing.T) {
+	ctx := context.Background()
+	cfg := testConfig
+	cfg.Retention = 24 * time.Hour
+	cfg.CleanupBatchSize = 2
+
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	before := time.Now().Add(-cfg.Retention)
+	cutoff := mock.MatchedBy(func(t time.Time) bool {
+		return !t.Before(before) && t.Before(before.Add(time.Minute))
+	})
+
+	mockEventRepo.EXPECT().
+		DeleteProcessedBefore(ctx, cutoff, cfg.CleanupBatchSize).
+		Return(2, nil).
+		Once()
+
+	mockEventRepo.EXPECT().
+		DeleteProcessedBefore(ctx, cutoff, cfg.CleanupBatchSize).
+		Return(1, nil).
+		Once()
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, cfg)
+
+	n, err := relay.Cleanup(ctx)
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, n)
+}
+
+func TestCleanup_Error(t *testing.T) {
+	ctx := context.Background()
+	cfg := testConfig
+	cfg.Retention = 24 * time.Hour
+	cfg.CleanupBatchSize = 2
+	deleteErr := errors.New("db error")
+
+	mockEventRepo := mocks.NewMockEventRepo(t)
+	mockPublisher := mocks.NewMockPublisher(t)
+
+	mockEventRepo.EXPECT().
+		DeleteProcessedBefore(ctx, mock.AnythingOfType("time.Time"), cfg.CleanupBatchSize).
+		Return(0, deleteErr)
+
+	relay := outbox.NewRelay(testutils.Log, mockEventRepo, mockPublisher, cfg)
+
+	_, err := relay.Cleanup(ctx)
+
+	assert.ErrorIs(t, err, deleteErr)
+}
@@ -0,0 +1,12 @@
+package testutils
+
+import "github.com/Tbits007/auth/internal/lib/jwt"
+
+func NewIssuer(secret string) *jwt.Issuer {
+	keyring, err := jwt.NewKeyring(jwt.NewHMACKey("test", secret))
+	if err != nil {
+		panic(err)
+	}
+
+	return jwt.NewIssuer(keyring, "auth", []string{"test"})
+}
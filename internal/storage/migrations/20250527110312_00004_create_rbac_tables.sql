@@ -0,0 +1,70 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+CREATE TABLE role_permissions (
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    permission VARCHAR(100) NOT NULL REFERENCES permissions(name) ON DELETE CASCADE,
+    PRIMARY KEY (role, permission)
+);
+
+CREATE TABLE user_roles (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(50) NOT NULL REFERENCES roles(name) ON DELETE CASCADE,
+    granted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id, role)
+);
+
+CREATE INDEX idx_user_roles_role ON user_roles(role);
+
+INSERT INTO roles (name, description) VALUES
+    ('admin', 'Full access'),
+    ('support', 'Customer support'),
+    ('billing', 'Billing operations');
+
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'View user accounts'),
+    ('users:write', 'Modify user accounts'),
+    ('users:delete', 'Delete user accounts'),
+    ('roles:assign', 'Assign and revoke roles'),
+    ('billing:read', 'View billing data'),
+    ('billing:write', 'Modify billing data');
+
+INSERT INTO role_permissions (role, permission)
+SELECT 'admin', name FROM permissions;
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('support', 'users:read'),
+    ('support', 'users:write'),
+    ('billing', 'users:read'),
+    ('billing', 'billing:read'),
+    ('billing', 'billing:write');
+
+INSERT INTO user_roles (user_id, role)
+SELECT id, 'admin' FROM users WHERE is_admin;
+
+ALTER TABLE users DROP COLUMN is_admin;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE users SET is_admin = true
+WHERE id IN (SELECT user_id FROM user_roles WHERE role = 'admin');
+
+DROP INDEX IF EXISTS idx_user_roles_role;
+
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS role_permissions;
+DROP TABLE IF EXISTS permissions;
+DROP TABLE IF EXISTS roles;
+-- +goose StatementEnd
@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE relation_tuples (
+    object VARCHAR(255) NOT NULL,
+    relation VARCHAR(64) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (object, subject, relation)
+);
+
+CREATE INDEX idx_relation_tuples_subject ON relation_tuples(subject);
+
+INSERT INTO permissions (name, description) VALUES
+    ('relations:write', 'Create and delete relation tuples');
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('admin', 'relations:write');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM permissions WHERE name = 'relations:write';
+
+DROP INDEX IF EXISTS idx_relation_tuples_subject;
+
+DROP TABLE IF EXISTS relation_tuples;
+-- +goose StatementEnd
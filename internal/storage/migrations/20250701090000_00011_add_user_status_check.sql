@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD CONSTRAINT users_status_check
+    CHECK (status IN ('pending_verification', 'active', 'locked', 'disabled', 'deleted'));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE users
+    DROP CONSTRAINT IF EXISTS users_status_check;
+-- +goose StatementEnd
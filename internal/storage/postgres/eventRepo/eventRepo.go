@@ -4,15 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/Tbits007/auth/internal/domain/models/eventModel"
 	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
 	"github.com/Tbits007/auth/internal/storage"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+const eventColumns = `id, event_type, payload, status, attempts, COALESCE(last_error, ''), created_at`
+
 type EventRepo struct {
 	db *pgxpool.Pool
 }
@@ -30,17 +35,25 @@ func (u *EventRepo) Save(
 	const op = "postgres.eventRepo.Save"
 
 	query := `
-	INSERT INTO outbox (event_type, payload, status)
-	VALUES ($1, $2, $3)
+	INSERT INTO outbox (id, event_type, payload, status)
+	VALUES (COALESCE($1, uuid_generate_v4()), $2, $3, $4)
 	RETURNING id
 	`
 
+	// Events built by eventModel.NewEvent carry the envelope ID, which
+	// must match the row ID; anything else gets one from the database.
+	var eventID *uuid.UUID
+	if Event.ID != uuid.Nil {
+		eventID = &Event.ID
+	}
+
 	var id uuid.UUID
     var err error
 
     querier := txManager.GetQuerier(ctx, u.db)
 
     err = querier.QueryRow(ctx, query,
+        eventID,
         Event.EventType,
 		Event.Payload,
 		Event.Status,
@@ -56,5 +69,267 @@ func (u *EventRepo) Save(
     }
 
 	return id, nil
-} 
+}
+
+// ClaimPending leases up to limit due pending events by moving their next
+// attempt lease into the future, so that concurrent relays skip them until
+// the lease ends. The claim is a single statement and holds no locks once
+// it returns. Events are returned oldest first.
+func (u *EventRepo) ClaimPending(
+	ctx   context.Context,
+	limit int,
+	lease time.Duration,
+) ([]eventModel.Event, error) {
+	const op = "postgres.eventRepo.ClaimPending"
+
+	query := `
+	UPDATE outbox
+	SET next_attempt_at = now() + make_interval(secs => $3)
+	WHERE id IN (
+		SELECT id
+		FROM outbox
+		WHERE status = $1 AND next_attempt_at <= now()
+		ORDER BY created_at
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING ` + eventColumns
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    rows, err := querier.Query(ctx, query, eventModel.PENDING, limit, lease.Seconds())
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to claim events: %w", op, err)
+    }
+    defer rows.Close()
+
+    var events []eventModel.Event
+    for rows.Next() {
+        event, err := scanEvent(rows)
+        if err != nil {
+            return nil, fmt.Errorf("%s: failed to scan event: %w", op, err)
+        }
+        events = append(events, event)
+    }
+
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("%s: failed to read events: %w", op, err)
+    }
+
+	slices.SortFunc(events, func(a, b eventModel.Event) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return events, nil
+}
+
+func (u *EventRepo) MarkProcessed(
+	ctx context.Context,
+	eventID uuid.UUID,
+) error {
+	const op = "postgres.eventRepo.MarkProcessed"
+
+	query := `
+	UPDATE outbox
+	SET status = $2, processed_at = now(), attempts = attempts + 1, last_error = NULL
+	WHERE id = $1
+	`
+
+	return u.update(ctx, op, query, eventID, eventModel.PROCESSED)
+}
+
+func (u *EventRepo) MarkRetry(
+	ctx context.Context,
+	eventID uuid.UUID,
+	nextAttemptAt time.Time,
+	lastError string,
+) error {
+	const op = "postgres.eventRepo.MarkRetry"
+
+	query := `
+	UPDATE outbox
+	SET attempts = attempts + 1, next_attempt_at = $2, last_error = $3
+	WHERE id = $1
+	`
+
+	return u.update(ctx, op, query, eventID, nextAttemptAt, lastError)
+}
+
+func (u *EventRepo) MarkFailed(
+	ctx context.Context,
+	eventID uuid.UUID,
+	lastError string,
+) error {
+	const op = "postgres.eventRepo.MarkFailed"
+
+	query := `
+	UPDATE outbox
+	SET status = $2, attempts = attempts + 1, last_error = $3
+	WHERE id = $1
+	`
+
+	return u.update(ctx, op, query, eventID, eventModel.FAILED, lastError)
+}
+
+func (u *EventRepo) GetByID(
+	ctx context.Context,
+	eventID uuid.UUID,
+) (*eventModel.Event, error) {
+	const op = "postgres.eventRepo.GetByID"
+
+	query := `SELECT ` + eventColumns + ` FROM outbox WHERE id = $1`
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    event, err := scanEvent(querier.QueryRow(ctx, query, eventID))
+    if err != nil {
+        if errors.Is(err, pgx.ErrNoRows) {
+            return nil, fmt.Errorf("%s: %w", op, storage.ErrEventNotFound)
+        }
+        return nil, fmt.Errorf("%s: failed to get event: %w", op, err)
+    }
+
+	return &event, nil
+}
+
+// ListFailed returns up to limit dead-lettered events, oldest first.
+func (u *EventRepo) ListFailed(
+	ctx context.Context,
+	limit int,
+) ([]eventModel.Event, error) {
+	const op = "postgres.eventRepo.ListFailed"
+
+	query := `
+	SELECT ` + eventColumns + `
+	FROM outbox
+	WHERE status = $1
+	ORDER BY created_at
+	LIMIT $2
+	`
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    rows, err := querier.Query(ctx, query, eventModel.FAILED, limit)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to list events: %w", op, err)
+    }
+    defer rows.Close()
+
+    var events []eventModel.Event
+    for rows.Next() {
+        event, err := scanEvent(rows)
+        if err != nil {
+            return nil, fmt.Errorf("%s: failed to scan event: %w", op, err)
+        }
+        events = append(events, event)
+    }
+
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("%s: failed to read events: %w", op, err)
+    }
+
+	return events, nil
+}
+
+// Requeue moves a failed event back to pending with a fresh attempt
+// budget. The last error is kept until the next attempt overwrites it.
+func (u *EventRepo) Requeue(
+	ctx context.Context,
+	eventID uuid.UUID,
+) error {
+	const op = "postgres.eventRepo.Requeue"
+
+	query := `
+	UPDATE outbox
+	SET status = $2, attempts = 0, next_attempt_at = now()
+	WHERE id = $1 AND status = $3
+	`
+
+	return u.update(ctx, op, query, eventID, eventModel.PENDING, eventModel.FAILED)
+}
+
+func (u *EventRepo) RequeueAllFailed(
+	ctx context.Context,
+) (int64, error) {
+	const op = "postgres.eventRepo.RequeueAllFailed"
+
+	query := `
+	UPDATE outbox
+	SET status = $1, attempts = 0, next_attempt_at = now()
+	WHERE status = $2
+	`
+
+    querier := txManager.GetQuerier(ctx, u.db)
 
+    tag, err := querier.Exec(ctx, query, eventModel.PENDING, eventModel.FAILED)
+    if err != nil {
+        return 0, fmt.Errorf("%s: failed to requeue events: %w", op, err)
+    }
+
+	return tag.RowsAffected(), nil
+}
+
+// DeleteProcessedBefore removes up to limit processed events older than
+// before and reports how many were deleted. Callers repeat it until fewer
+// than limit rows are removed, which keeps each statement short. The
+// status is spelled out in the query so that the planner can use the
+// partial index idx_outbox_processed.
+func (u *EventRepo) DeleteProcessedBefore(
+	ctx context.Context,
+	before time.Time,
+	limit int,
+) (int64, error) {
+	const op = "postgres.eventRepo.DeleteProcessedBefore"
+
+	query := `
+	DELETE FROM outbox
+	WHERE id IN (
+		SELECT id FROM outbox
+		WHERE status = 'processed' AND processed_at < $1
+		LIMIT $2
+	)
+	`
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    tag, err := querier.Exec(ctx, query, before, limit)
+    if err != nil {
+        return 0, fmt.Errorf("%s: failed to delete events: %w", op, err)
+    }
+
+	return tag.RowsAffected(), nil
+}
+
+func scanEvent(row pgx.Row) (eventModel.Event, error) {
+	var event eventModel.Event
+	err := row.Scan(
+		&event.ID,
+		&event.EventType,
+		&event.Payload,
+		&event.Status,
+		&event.Attempts,
+		&event.LastError,
+		&event.CreatedAt,
+	)
+
+	return event, err
+}
+
+func (u *EventRepo) update(
+	ctx   context.Context,
+	op    string,
+	query string,
+	args  ...any,
+) error {
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    tag, err := querier.Exec(ctx, query, args...)
+    if err != nil {
+        return fmt.Errorf("%s: failed to update event: %w", op, err)
+    }
+    if tag.RowsAffected() == 0 {
+        return fmt.Errorf("%s: %w", op, storage.ErrEventNotFound)
+    }
+
+	return nil
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/storage"
 	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -66,6 +68,193 @@ func TestSave_InvalidData(t *testing.T) {
     assert.Contains(t, err.Error(), "failed to save event")
 }
 
+func TestClaimPending_LeasesEvents(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	first := saveEvent(t, repo)
+	second := saveEvent(t, repo)
+	notDue := saveEvent(t, repo)
+	require.NoError(t, repo.MarkRetry(ctx, notDue, time.Now().Add(time.Hour), "boom"))
+
+	events, err := repo.ClaimPending(ctx, 1, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, first, events[0].ID)
+
+	events, err = repo.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, second, events[0].ID)
+
+	events, err = repo.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+func TestClaimPending_ReclaimsExpiredLease(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	id := saveEvent(t, repo)
+
+	events, err := repo.ClaimPending(ctx, 10, 0)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	events, err = repo.ClaimPending(ctx, 10, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, id, events[0].ID)
+}
+
+func TestMarkRetry_IncrementsAttempts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	id := saveEvent(t, repo)
+	require.NoError(t, repo.MarkRetry(ctx, id, time.Now(), "boom"))
+
+	var attempts int
+	var lastError string
+	err := testDB.QueryRow(ctx, "SELECT attempts, last_error FROM outbox WHERE id = $1", id).
+		Scan(&attempts, &lastError)
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts)
+	assert.Equal(t, "boom", lastError)
+}
+
+func TestMarkProcessed_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	id := saveEvent(t, repo)
+	require.NoError(t, repo.MarkProcessed(ctx, id))
+	assertEventStatus(t, id, eventModel.PROCESSED)
+}
+
+func TestMarkFailed_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	id := saveEvent(t, repo)
+	require.NoError(t, repo.MarkFailed(ctx, id, "boom"))
+	assertEventStatus(t, id, eventModel.FAILED)
+}
+
+func TestMarkProcessed_NotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	err := repo.MarkProcessed(ctx, uuid.New())
+	assert.ErrorIs(t, err, storage.ErrEventNotFound)
+}
+
+func TestListFailedAndRequeue(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	failed := saveEvent(t, repo)
+	saveEvent(t, repo)
+	require.NoError(t, repo.MarkFailed(ctx, failed, "boom"))
+
+	events, err := repo.ListFailed(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, failed, events[0].ID)
+	assert.Equal(t, "boom", events[0].LastError)
+
+	require.NoError(t, repo.Requeue(ctx, failed))
+	assertEventStatus(t, failed, eventModel.PENDING)
+
+	event, err := repo.GetByID(ctx, failed)
+	require.NoError(t, err)
+	assert.Zero(t, event.Attempts)
+
+	err = repo.Requeue(ctx, failed)
+	assert.ErrorIs(t, err, storage.ErrEventNotFound)
+}
+
+func TestDeleteProcessedBefore(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewEventRepo(testDB)
+	cleanTable(t)
+
+	processed := saveEvent(t, repo)
+	pending := saveEvent(t, repo)
+	require.NoError(t, repo.MarkProcessed(ctx, processed))
+
+	n, err := repo.DeleteProcessedBefore(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, n)
+
+	_, err = repo.GetByID(ctx, processed)
+	assert.ErrorIs(t, err, storage.ErrEventNotFound)
+	assertEventExists(t, pending)
+}
+
+func saveEvent(t *testing.T, repo *EventRepo) uuid.UUID {
+	id, err := repo.Save(context.Background(), eventModel.Event{
+		EventType: "user_created",
+		Payload:   []byte(`{"user_email":"test@test"}`),
+		Status:    eventModel.PENDING,
+	})
+	require.NoError(t, err)
+
+	return id
+}
+
+func assertEventStatus(t *testing.T, id uuid.UUID, status eventModel.EventStatus) {
+	var got string
+	err := testDB.QueryRow(
+		context.Background(),
+		"SELECT status FROM outbox WHERE id = $1",
+		id,
+	).Scan(&got)
+
+	require.NoError(t, err)
+	assert.Equal(t, string(status), got)
+}
+
 func cleanTable(t *testing.T) {
 	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE outbox CASCADE")
 	require.NoError(t, err)
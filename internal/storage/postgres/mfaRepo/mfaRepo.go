@@ -0,0 +1,199 @@
+package mfaRepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Tbits007/auth/internal/domain/models/mfaModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type MFARepo struct {
+	db *pgxpool.Pool
+}
+
+func NewMFARepo(db *pgxpool.Pool) *MFARepo {
+	return &MFARepo{
+		db: db,
+	}
+}
+
+// SaveTOTP stores a new unconfirmed TOTP secret for the user, replacing
+// any earlier unconfirmed one. It fails with storage.ErrMFAExists once
+// an enrollment was confirmed.
+func (r *MFARepo) SaveTOTP(
+	ctx             context.Context,
+	userID          uuid.UUID,
+	encryptedSecret []byte,
+) error {
+	const op = "postgres.mfaRepo.SaveTOTP"
+
+	query := `
+	INSERT INTO user_totp (user_id, encrypted_secret)
+	VALUES ($1, $2)
+	ON CONFLICT (user_id) DO UPDATE
+	SET encrypted_secret = EXCLUDED.encrypted_secret, last_used_step = 0, created_at = now()
+	WHERE user_totp.confirmed_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	tag, err := querier.Exec(ctx, query, userID, encryptedSecret)
+	if err != nil {
+		return fmt.Errorf("%s: failed to save totp: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMFAExists)
+	}
+
+	return nil
+}
+
+func (r *MFARepo) GetTOTP(
+	ctx    context.Context,
+	userID uuid.UUID,
+) (*mfaModel.TOTP, error) {
+	const op = "postgres.mfaRepo.GetTOTP"
+
+	query := `
+	SELECT user_id, encrypted_secret, last_used_step, created_at, confirmed_at
+	FROM user_totp
+	WHERE user_id = $1
+	`
+
+	var totp mfaModel.TOTP
+	querier := txManager.GetQuerier(ctx, r.db)
+	err := querier.QueryRow(ctx, query, userID).Scan(
+		&totp.UserID,
+		&totp.EncryptedSecret,
+		&totp.LastUsedStep,
+		&totp.CreatedAt,
+		&totp.ConfirmedAt,
+	)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrMFANotFound)
+	case err != nil:
+		return nil, fmt.Errorf("%s: failed to get totp: %w", op, err)
+	default:
+		return &totp, nil
+	}
+}
+
+// ConfirmTOTP activates the unconfirmed enrollment of the user, recording
+// step as used.
+func (r *MFARepo) ConfirmTOTP(
+	ctx    context.Context,
+	userID uuid.UUID,
+	step   int64,
+) error {
+	const op = "postgres.mfaRepo.ConfirmTOTP"
+
+	query := `
+	UPDATE user_totp
+	SET confirmed_at = now(), last_used_step = $2
+	WHERE user_id = $1 AND confirmed_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	tag, err := querier.Exec(ctx, query, userID, step)
+	if err != nil {
+		return fmt.Errorf("%s: failed to confirm totp: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrMFANotFound)
+	}
+
+	return nil
+}
+
+// UseTOTPStep records step as used. It fails with storage.ErrCodeNotFound
+// if a code of that or a later step was already accepted, so concurrent
+// requests cannot replay a code.
+func (r *MFARepo) UseTOTPStep(
+	ctx    context.Context,
+	userID uuid.UUID,
+	step   int64,
+) error {
+	const op = "postgres.mfaRepo.UseTOTPStep"
+
+	query := `
+	UPDATE user_totp
+	SET last_used_step = $2
+	WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	tag, err := querier.Exec(ctx, query, userID, step)
+	if err != nil {
+		return fmt.Errorf("%s: failed to use totp step: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: step already used: %w", op, storage.ErrCodeNotFound)
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes swaps every recovery code of the user, used or
+// not, for the given hashes.
+func (r *MFARepo) ReplaceRecoveryCodes(
+	ctx        context.Context,
+	userID     uuid.UUID,
+	codeHashes []string,
+) error {
+	const op = "postgres.mfaRepo.ReplaceRecoveryCodes"
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	if _, err := querier.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("%s: failed to delete recovery codes: %w", op, err)
+	}
+
+	query := `
+	INSERT INTO mfa_recovery_codes (user_id, code_hash)
+	SELECT $1, unnest($2::text[])
+	`
+
+	if _, err := querier.Exec(ctx, query, userID, codeHashes); err != nil {
+		return fmt.Errorf("%s: failed to save recovery codes: %w", op, err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks an unused recovery code of the user as used. Any
+// other code is reported as storage.ErrCodeNotFound.
+func (r *MFARepo) UseRecoveryCode(
+	ctx      context.Context,
+	userID   uuid.UUID,
+	codeHash string,
+) error {
+	const op = "postgres.mfaRepo.UseRecoveryCode"
+
+	query := `
+	UPDATE mfa_recovery_codes
+	SET used_at = now()
+	WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	tag, err := querier.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("%s: failed to use recovery code: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrCodeNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package mfaRepo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDB *pgxpool.Pool
+)
+
+func TestMain(m *testing.M) {
+	testDB = testutils.GetTestDB()
+	defer testDB.Close()
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestTOTP_EnrollConfirmAndReplay(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewMFARepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+
+	require.NoError(t, repo.SaveTOTP(ctx, userID, []byte("first")))
+	require.NoError(t, repo.SaveTOTP(ctx, userID, []byte("second")))
+
+	totp, err := repo.GetTOTP(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("second"), totp.EncryptedSecret)
+	assert.Nil(t, totp.ConfirmedAt)
+
+	assert.ErrorIs(t, repo.UseTOTPStep(ctx, userID, 100), storage.ErrCodeNotFound, "unconfirmed")
+
+	require.NoError(t, repo.ConfirmTOTP(ctx, userID, 100))
+	assert.ErrorIs(t, repo.ConfirmTOTP(ctx, userID, 101), storage.ErrMFANotFound)
+	assert.ErrorIs(t, repo.SaveTOTP(ctx, userID, []byte("third")), storage.ErrMFAExists)
+
+	assert.ErrorIs(t, repo.UseTOTPStep(ctx, userID, 100), storage.ErrCodeNotFound)
+	require.NoError(t, repo.UseTOTPStep(ctx, userID, 101))
+	assert.ErrorIs(t, repo.UseTOTPStep(ctx, userID, 101), storage.ErrCodeNotFound)
+
+	var mfaEnabled bool
+	err = testDB.QueryRow(ctx, `SELECT confirmed_at IS NOT NULL FROM user_totp WHERE user_id = $1`, userID).Scan(&mfaEnabled)
+	require.NoError(t, err)
+	assert.True(t, mfaEnabled)
+
+	_, err = repo.GetTOTP(ctx, uuid.New())
+	assert.ErrorIs(t, err, storage.ErrMFANotFound)
+}
+
+func TestRecoveryCodes_SingleUse(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewMFARepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, userID, []string{"hash_a", "hash_b"}))
+
+	require.NoError(t, repo.UseRecoveryCode(ctx, userID, "hash_a"))
+	assert.ErrorIs(t, repo.UseRecoveryCode(ctx, userID, "hash_a"), storage.ErrCodeNotFound)
+	assert.ErrorIs(t, repo.UseRecoveryCode(ctx, uuid.New(), "hash_b"), storage.ErrCodeNotFound)
+
+	require.NoError(t, repo.ReplaceRecoveryCodes(ctx, userID, []string{"hash_c"}))
+	assert.ErrorIs(t, repo.UseRecoveryCode(ctx, userID, "hash_b"), storage.ErrCodeNotFound)
+	require.NoError(t, repo.UseRecoveryCode(ctx, userID, "hash_c"))
+}
+
+func cleanTables(t *testing.T) {
+	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE users CASCADE")
+	require.NoError(t, err)
+}
+
+func createUser(t *testing.T) uuid.UUID {
+	var id uuid.UUID
+	err := testDB.QueryRow(
+		context.Background(),
+		"INSERT INTO users (email, hashed_password, status) VALUES ($1, 'hash', 'active') RETURNING id",
+		t.Name()+"@example.com",
+	).Scan(&id)
+	require.NoError(t, err)
+
+	return id
+}
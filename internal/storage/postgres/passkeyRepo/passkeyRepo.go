@@ -0,0 +1,181 @@
+package passkeyRepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const credentialColumns = `
+	id, user_id, credential_id, public_key, attestation_type, aaguid,
+	sign_count, transports, created_at, last_used_at
+`
+
+type PasskeyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPasskeyRepo(db *pgxpool.Pool) *PasskeyRepo {
+	return &PasskeyRepo{
+		db: db,
+	}
+}
+
+func (r *PasskeyRepo) Save(
+	ctx        context.Context,
+	credential passkeyModel.Credential,
+) (uuid.UUID, error) {
+	const op = "postgres.passkeyRepo.Save"
+
+	query := `
+	INSERT INTO webauthn_credentials
+		(user_id, credential_id, public_key, attestation_type, aaguid, sign_count, transports)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	RETURNING id
+	`
+
+	transports := credential.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+
+	var id uuid.UUID
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	err := querier.QueryRow(ctx, query,
+		credential.UserID,
+		credential.CredentialID,
+		credential.PublicKey,
+		credential.AttestationType,
+		credential.AAGUID,
+		int64(credential.SignCount),
+		transports,
+	).Scan(&id)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return uuid.Nil, fmt.Errorf("%s: passkey already exists: %w", op, storage.ErrPasskeyExists)
+		}
+		return uuid.Nil, fmt.Errorf("%s: failed to save passkey: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *PasskeyRepo) GetByCredentialID(
+	ctx          context.Context,
+	credentialID []byte,
+) (*passkeyModel.Credential, error) {
+	const op = "postgres.passkeyRepo.GetByCredentialID"
+
+	query := `SELECT ` + credentialColumns + ` FROM webauthn_credentials WHERE credential_id = $1`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	credential, err := scanCredential(querier.QueryRow(ctx, query, credentialID))
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrPasskeyNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("%s: failed to get passkey: %w", op, err)
+	default:
+		return credential, nil
+	}
+}
+
+func (r *PasskeyRepo) ListByUser(
+	ctx    context.Context,
+	userID uuid.UUID,
+) ([]passkeyModel.Credential, error) {
+	const op = "postgres.passkeyRepo.ListByUser"
+
+	query := `SELECT ` + credentialColumns + ` FROM webauthn_credentials WHERE user_id = $1 ORDER BY created_at`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	rows, err := querier.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list passkeys: %w", op, err)
+	}
+	defer rows.Close()
+
+	var credentials []passkeyModel.Credential
+	for rows.Next() {
+		credential, err := scanCredential(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan passkey: %w", op, err)
+		}
+		credentials = append(credentials, *credential)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to list passkeys: %w", op, err)
+	}
+
+	return credentials, nil
+}
+
+// UpdateSignCount records a login with the credential. The counter only
+// moves forward: it fails with storage.ErrPasskeyNotFound if a concurrent
+// login already stored this or a later counter, unless the authenticator
+// keeps no counter and always reports zero.
+func (r *PasskeyRepo) UpdateSignCount(
+	ctx          context.Context,
+	credentialID []byte,
+	signCount    uint32,
+) error {
+	const op = "postgres.passkeyRepo.UpdateSignCount"
+
+	query := `
+	UPDATE webauthn_credentials
+	SET sign_count = $2, last_used_at = now()
+	WHERE credential_id = $1 AND (sign_count < $2 OR (sign_count = 0 AND $2 = 0))
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	tag, err := querier.Exec(ctx, query, credentialID, int64(signCount))
+	if err != nil {
+		return fmt.Errorf("%s: failed to update sign count: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrPasskeyNotFound)
+	}
+
+	return nil
+}
+
+func scanCredential(row pgx.Row) (*passkeyModel.Credential, error) {
+	var (
+		credential passkeyModel.Credential
+		signCount  int64
+	)
+
+	err := row.Scan(
+		&credential.ID,
+		&credential.UserID,
+		&credential.CredentialID,
+		&credential.PublicKey,
+		&credential.AttestationType,
+		&credential.AAGUID,
+		&signCount,
+		&credential.Transports,
+		&credential.CreatedAt,
+		&credential.LastUsedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	credential.SignCount = uint32(signCount)
+
+	return &credential, nil
+}
@@ -0,0 +1,134 @@
+package passkeyRepo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Tbits007/auth/internal/domain/models/passkeyModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDB *pgxpool.Pool
+)
+
+func TestMain(m *testing.M) {
+	testDB = testutils.GetTestDB()
+	defer testDB.Close()
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestPasskeyRepo_SaveAndGet(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewPasskeyRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+	credential := passkeyModel.Credential{
+		UserID:          userID,
+		CredentialID:    []byte("credential"),
+		PublicKey:       []byte("public key"),
+		AttestationType: "none",
+		AAGUID:          make([]byte, 16),
+		SignCount:       3,
+		Transports:      []string{"internal", "hybrid"},
+	}
+
+	id, err := repo.Save(ctx, credential)
+	require.NoError(t, err)
+
+	_, err = repo.Save(ctx, credential)
+	assert.ErrorIs(t, err, storage.ErrPasskeyExists)
+
+	got, err := repo.GetByCredentialID(ctx, credential.CredentialID)
+	require.NoError(t, err)
+	assert.Equal(t, id, got.ID)
+	assert.Equal(t, userID, got.UserID)
+	assert.Equal(t, credential.PublicKey, got.PublicKey)
+	assert.Equal(t, uint32(3), got.SignCount)
+	assert.Equal(t, credential.Transports, got.Transports)
+	assert.Nil(t, got.LastUsedAt)
+
+	list, err := repo.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, id, list[0].ID)
+
+	_, err = repo.GetByCredentialID(ctx, []byte("unknown"))
+	assert.ErrorIs(t, err, storage.ErrPasskeyNotFound)
+}
+
+func TestPasskeyRepo_UpdateSignCount(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewPasskeyRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+
+	counted := []byte("counted")
+	_, err := repo.Save(ctx, passkeyModel.Credential{
+		UserID:          userID,
+		CredentialID:    counted,
+		PublicKey:       []byte("public key"),
+		AttestationType: "none",
+		AAGUID:          make([]byte, 16),
+		SignCount:       3,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateSignCount(ctx, counted, 4))
+	assert.ErrorIs(t, repo.UpdateSignCount(ctx, counted, 4), storage.ErrPasskeyNotFound)
+
+	got, err := repo.GetByCredentialID(ctx, counted)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(4), got.SignCount)
+	assert.NotNil(t, got.LastUsedAt)
+
+	uncounted := []byte("uncounted")
+	_, err = repo.Save(ctx, passkeyModel.Credential{
+		UserID:          userID,
+		CredentialID:    uncounted,
+		PublicKey:       []byte("public key"),
+		AttestationType: "none",
+		AAGUID:          make([]byte, 16),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateSignCount(ctx, uncounted, 0))
+	require.NoError(t, repo.UpdateSignCount(ctx, uncounted, 0))
+
+	assert.ErrorIs(t, repo.UpdateSignCount(ctx, []byte("unknown"), 1), storage.ErrPasskeyNotFound)
+}
+
+func cleanTables(t *testing.T) {
+	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE users CASCADE")
+	require.NoError(t, err)
+}
+
+func createUser(t *testing.T) uuid.UUID {
+	var id uuid.UUID
+	err := testDB.QueryRow(
+		context.Background(),
+		"INSERT INTO users (email, hashed_password, status) VALUES ($1, 'hash', 'active') RETURNING id",
+		t.Name()+"@example.com",
+	).Scan(&id)
+	require.NoError(t, err)
+
+	return id
+}
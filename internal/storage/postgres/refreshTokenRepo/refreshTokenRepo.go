@@ -0,0 +1,162 @@
+package refreshTokenRepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RefreshTokenRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRefreshTokenRepo(db *pgxpool.Pool) *RefreshTokenRepo {
+	return &RefreshTokenRepo{
+		db: db,
+	}
+}
+
+func (r *RefreshTokenRepo) Save(
+	ctx context.Context,
+	token tokenModel.RefreshToken,
+) (uuid.UUID, error) {
+	const op = "postgres.refreshTokenRepo.Save"
+
+	query := `
+	INSERT INTO refresh_tokens (user_id, family_id, token_hash, expires_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id
+	`
+
+	var id uuid.UUID
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	err := querier.QueryRow(ctx, query,
+		token.UserID,
+		token.FamilyID,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&id)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return uuid.Nil, fmt.Errorf("%s: token already exists: %w", op, storage.ErrTokenExists)
+		}
+		return uuid.Nil, fmt.Errorf("%s: failed to save token: %w", op, err)
+	}
+
+	return id, nil
+}
+
+func (r *RefreshTokenRepo) GetByHash(
+	ctx context.Context,
+	tokenHash string,
+) (*tokenModel.RefreshToken, error) {
+	const op = "postgres.refreshTokenRepo.GetByHash"
+
+	query := `
+	SELECT id, user_id, family_id, token_hash, expires_at, created_at, used_at, revoked_at
+	FROM refresh_tokens
+	WHERE token_hash = $1
+	`
+
+	var token tokenModel.RefreshToken
+	querier := txManager.GetQuerier(ctx, r.db)
+	err := querier.QueryRow(ctx, query, tokenHash).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.FamilyID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+		&token.RevokedAt,
+	)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%s: token not found: %w", op, storage.ErrTokenNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("%s: failed to get token by hash: %w", op, err)
+	default:
+		return &token, nil
+	}
+}
+
+// MarkUsed flags an active token as consumed. It reports ErrTokenNotFound
+// when the token was already used or revoked, which lets concurrent
+// rotations of the same token be detected as reuse.
+func (r *RefreshTokenRepo) MarkUsed(
+	ctx context.Context,
+	tokenID uuid.UUID,
+) error {
+	const op = "postgres.refreshTokenRepo.MarkUsed"
+
+	query := `
+	UPDATE refresh_tokens
+	SET used_at = now()
+	WHERE id = $1 AND used_at IS NULL AND revoked_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+	tag, err := querier.Exec(ctx, query, tokenID)
+	if err != nil {
+		return fmt.Errorf("%s: failed to mark token used: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: active token not found: %w", op, storage.ErrTokenNotFound)
+	}
+
+	return nil
+}
+
+func (r *RefreshTokenRepo) RevokeFamily(
+	ctx context.Context,
+	familyID uuid.UUID,
+) error {
+	const op = "postgres.refreshTokenRepo.RevokeFamily"
+
+	query := `
+	UPDATE refresh_tokens
+	SET revoked_at = now()
+	WHERE family_id = $1 AND revoked_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+	if _, err := querier.Exec(ctx, query, familyID); err != nil {
+		return fmt.Errorf("%s: failed to revoke token family: %w", op, err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every active refresh token of the user, across
+// all families.
+func (r *RefreshTokenRepo) RevokeAllForUser(
+	ctx    context.Context,
+	userID uuid.UUID,
+) error {
+	const op = "postgres.refreshTokenRepo.RevokeAllForUser"
+
+	query := `
+	UPDATE refresh_tokens
+	SET revoked_at = now()
+	WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+	if _, err := querier.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("%s: failed to revoke user tokens: %w", op, err)
+	}
+
+	return nil
+}
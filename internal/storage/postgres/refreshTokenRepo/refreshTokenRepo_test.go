@@ -0,0 +1,190 @@
+package refreshTokenRepo
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDB *pgxpool.Pool
+)
+
+func TestMain(m *testing.M) {
+	testDB = testutils.GetTestDB()
+	defer testDB.Close()
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestSaveAndGetByHash_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewRefreshTokenRepo(testDB)
+	cleanTables(t)
+
+	token := tokenModel.RefreshToken{
+		UserID:    createUser(t),
+		FamilyID:  uuid.New(),
+		TokenHash: "hash_" + t.Name(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	id, err := repo.Save(ctx, token)
+	require.NoError(t, err)
+	assert.NotEqual(t, uuid.Nil, id)
+
+	stored, err := repo.GetByHash(ctx, token.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, id, stored.ID)
+	assert.Equal(t, token.UserID, stored.UserID)
+	assert.Equal(t, token.FamilyID, stored.FamilyID)
+	assert.Nil(t, stored.UsedAt)
+	assert.Nil(t, stored.RevokedAt)
+}
+
+func TestGetByHash_NotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewRefreshTokenRepo(testDB)
+	cleanTables(t)
+
+	_, err := repo.GetByHash(ctx, "missing")
+
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+}
+
+func TestMarkUsed_OnlyOnce(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewRefreshTokenRepo(testDB)
+	cleanTables(t)
+
+	id, err := repo.Save(ctx, tokenModel.RefreshToken{
+		UserID:    createUser(t),
+		FamilyID:  uuid.New(),
+		TokenHash: "hash_" + t.Name(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.MarkUsed(ctx, id))
+
+	err = repo.MarkUsed(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+}
+
+func TestRevokeFamily_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewRefreshTokenRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+	familyID := uuid.New()
+
+	for _, hash := range []string{"first", "second"} {
+		_, err := repo.Save(ctx, tokenModel.RefreshToken{
+			UserID:    userID,
+			FamilyID:  familyID,
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, repo.RevokeFamily(ctx, familyID))
+
+	for _, hash := range []string{"first", "second"} {
+		stored, err := repo.GetByHash(ctx, hash)
+		require.NoError(t, err)
+		assert.NotNil(t, stored.RevokedAt)
+	}
+}
+
+func TestRevokeAllForUser_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewRefreshTokenRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+
+	var otherID uuid.UUID
+	err := testDB.QueryRow(ctx,
+		"INSERT INTO users (email, hashed_password, status) VALUES ('other@example.com', 'hash', 'active') RETURNING id",
+	).Scan(&otherID)
+	require.NoError(t, err)
+
+	for _, hash := range []string{"first", "second"} {
+		_, err := repo.Save(ctx, tokenModel.RefreshToken{
+			UserID:    userID,
+			FamilyID:  uuid.New(),
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	_, err = repo.Save(ctx, tokenModel.RefreshToken{
+		UserID:    otherID,
+		FamilyID:  uuid.New(),
+		TokenHash: "other",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RevokeAllForUser(ctx, userID))
+
+	for _, hash := range []string{"first", "second"} {
+		stored, err := repo.GetByHash(ctx, hash)
+		require.NoError(t, err)
+		assert.NotNil(t, stored.RevokedAt)
+	}
+
+	other, err := repo.GetByHash(ctx, "other")
+	require.NoError(t, err)
+	assert.Nil(t, other.RevokedAt)
+}
+
+func cleanTables(t *testing.T) {
+	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE users CASCADE")
+	require.NoError(t, err)
+}
+
+func createUser(t *testing.T) uuid.UUID {
+	var id uuid.UUID
+	err := testDB.QueryRow(
+		context.Background(),
+		"INSERT INTO users (email, hashed_password, status) VALUES ($1, 'hash', 'active') RETURNING id",
+		t.Name()+"@example.com",
+	).Scan(&id)
+	require.NoError(t, err)
+
+	return id
+}
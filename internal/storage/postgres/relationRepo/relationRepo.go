@@ -0,0 +1,89 @@
+package relationRepo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RelationRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewRelationRepo(db *pgxpool.Pool) *RelationRepo {
+	return &RelationRepo{
+		db: db,
+	}
+}
+
+// Write stores the tuple. Writing an existing tuple is a no-op.
+func (r *RelationRepo) Write(
+	ctx   context.Context,
+	tuple relationModel.Tuple,
+) error {
+	const op = "postgres.relationRepo.Write"
+
+	query := `
+	INSERT INTO relation_tuples (object, relation, subject)
+	VALUES ($1, $2, $3)
+	ON CONFLICT DO NOTHING
+	`
+
+    querier := txManager.GetQuerier(ctx, r.db)
+
+    if _, err := querier.Exec(ctx, query, tuple.Object, tuple.Relation, tuple.Subject); err != nil {
+        return fmt.Errorf("%s: failed to write tuple: %w", op, err)
+    }
+
+	return nil
+}
+
+// Delete removes the tuple. Deleting a missing tuple is a no-op.
+func (r *RelationRepo) Delete(
+	ctx   context.Context,
+	tuple relationModel.Tuple,
+) error {
+	const op = "postgres.relationRepo.Delete"
+
+	query := `
+	DELETE FROM relation_tuples
+	WHERE object = $1 AND relation = $2 AND subject = $3
+	`
+
+    querier := txManager.GetQuerier(ctx, r.db)
+
+    if _, err := querier.Exec(ctx, query, tuple.Object, tuple.Relation, tuple.Subject); err != nil {
+        return fmt.Errorf("%s: failed to delete tuple: %w", op, err)
+    }
+
+	return nil
+}
+
+// HasAny reports whether subject holds any of relations on object.
+func (r *RelationRepo) HasAny(
+	ctx       context.Context,
+	object    string,
+	subject   string,
+	relations []string,
+) (bool, error) {
+	const op = "postgres.relationRepo.HasAny"
+
+	query := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM relation_tuples
+		WHERE object = $1 AND subject = $2 AND relation = ANY($3)
+	)
+	`
+
+    var found bool
+    querier := txManager.GetQuerier(ctx, r.db)
+    if err := querier.QueryRow(ctx, query, object, subject, relations).Scan(&found); err != nil {
+        return false, fmt.Errorf("%s: failed to check tuple: %w", op, err)
+    }
+
+	return found, nil
+}
@@ -0,0 +1,80 @@
+package relationRepo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+    testDB *pgxpool.Pool
+)
+
+func TestMain(m *testing.M) {
+    testDB = testutils.GetTestDB()
+    defer testDB.Close()
+
+    code := m.Run()
+    os.Exit(code)
+}
+
+func TestWriteAndHasAny(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewRelationRepo(testDB)
+	cleanTable(t)
+
+	tuple := relationModel.Tuple{
+		Object:   "organization:42",
+		Relation: relationModel.RelationOwner,
+		Subject:  "user:1",
+	}
+
+	require.NoError(t, repo.Write(ctx, tuple))
+	require.NoError(t, repo.Write(ctx, tuple))
+
+	found, err := repo.HasAny(ctx, tuple.Object, tuple.Subject, relationModel.Granting(relationModel.RelationViewer))
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	found, err = repo.HasAny(ctx, tuple.Object, "user:2", relationModel.Granting(relationModel.RelationViewer))
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewRelationRepo(testDB)
+	cleanTable(t)
+
+	tuple := relationModel.Tuple{
+		Object:   "organization:42",
+		Relation: relationModel.RelationEditor,
+		Subject:  "user:1",
+	}
+
+	require.NoError(t, repo.Write(ctx, tuple))
+	require.NoError(t, repo.Delete(ctx, tuple))
+
+	found, err := repo.HasAny(ctx, tuple.Object, tuple.Subject, []string{tuple.Relation})
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func cleanTable(t *testing.T) {
+	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE relation_tuples")
+	require.NoError(t, err)
+}
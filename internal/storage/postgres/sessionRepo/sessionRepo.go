@@ -0,0 +1,229 @@
+package sessionRepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const sessionColumns = `
+	id, user_id, family_id, user_agent, ip, created_at, last_seen_at, expires_at, revoked_at
+`
+
+type SessionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepo(db *pgxpool.Pool) *SessionRepo {
+	return &SessionRepo{
+		db: db,
+	}
+}
+
+func (r *SessionRepo) Save(
+	ctx     context.Context,
+	session sessionModel.Session,
+) (uuid.UUID, error) {
+	const op = "postgres.sessionRepo.Save"
+
+	query := `
+	INSERT INTO sessions (user_id, family_id, user_agent, ip, expires_at)
+	VALUES ($1, $2, $3, $4, $5)
+	RETURNING id
+	`
+
+	var id uuid.UUID
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	err := querier.QueryRow(ctx, query,
+		session.UserID,
+		session.FamilyID,
+		session.UserAgent,
+		session.IP,
+		session.ExpiresAt,
+	).Scan(&id)
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%s: failed to save session: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Touch records a refresh of the session of the refresh token family
+// familyID and returns the session's ID. It fails with
+// storage.ErrSessionNotFound if the family has no session that is still
+// active.
+func (r *SessionRepo) Touch(
+	ctx       context.Context,
+	familyID  uuid.UUID,
+	expiresAt time.Time,
+) (uuid.UUID, error) {
+	const op = "postgres.sessionRepo.Touch"
+
+	query := `
+	UPDATE sessions
+	SET last_seen_at = now(), expires_at = $2
+	WHERE family_id = $1 AND revoked_at IS NULL
+	RETURNING id
+	`
+
+	var id uuid.UUID
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	err := querier.QueryRow(ctx, query, familyID, expiresAt).Scan(&id)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	case err != nil:
+		return uuid.Nil, fmt.Errorf("%s: failed to touch session: %w", op, err)
+	default:
+		return id, nil
+	}
+}
+
+// ListActive returns the sessions of userID that are neither revoked nor
+// expired, most recently seen first.
+func (r *SessionRepo) ListActive(
+	ctx    context.Context,
+	userID uuid.UUID,
+) ([]sessionModel.Session, error) {
+	const op = "postgres.sessionRepo.ListActive"
+
+	query := `SELECT ` + sessionColumns + ` FROM sessions
+	WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()
+	ORDER BY last_seen_at DESC`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	rows, err := querier.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to list sessions: %w", op, err)
+	}
+	defer rows.Close()
+
+	var sessions []sessionModel.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("%s: failed to scan session: %w", op, err)
+		}
+		sessions = append(sessions, *session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("%s: failed to list sessions: %w", op, err)
+	}
+
+	return sessions, nil
+}
+
+// Revoke revokes the session sessionID of userID and returns it. It fails
+// with storage.ErrSessionNotFound if userID has no such active session.
+func (r *SessionRepo) Revoke(
+	ctx       context.Context,
+	userID    uuid.UUID,
+	sessionID uuid.UUID,
+) (*sessionModel.Session, error) {
+	const op = "postgres.sessionRepo.Revoke"
+
+	query := `
+	UPDATE sessions
+	SET revoked_at = now()
+	WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+	RETURNING ` + sessionColumns
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	session, err := scanSession(querier.QueryRow(ctx, query, sessionID, userID))
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("%s: failed to revoke session: %w", op, err)
+	default:
+		return session, nil
+	}
+}
+
+// RevokeByFamily revokes the session of the refresh token family familyID
+// and returns its ID. It fails with storage.ErrSessionNotFound if the
+// family has no active session.
+func (r *SessionRepo) RevokeByFamily(
+	ctx      context.Context,
+	familyID uuid.UUID,
+) (uuid.UUID, error) {
+	const op = "postgres.sessionRepo.RevokeByFamily"
+
+	query := `
+	UPDATE sessions
+	SET revoked_at = now()
+	WHERE family_id = $1 AND revoked_at IS NULL
+	RETURNING id
+	`
+
+	var id uuid.UUID
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	err := querier.QueryRow(ctx, query, familyID).Scan(&id)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return uuid.Nil, fmt.Errorf("%s: %w", op, storage.ErrSessionNotFound)
+	case err != nil:
+		return uuid.Nil, fmt.Errorf("%s: failed to revoke session: %w", op, err)
+	default:
+		return id, nil
+	}
+}
+
+func (r *SessionRepo) RevokeAllForUser(
+	ctx    context.Context,
+	userID uuid.UUID,
+) error {
+	const op = "postgres.sessionRepo.RevokeAllForUser"
+
+	query := `
+	UPDATE sessions
+	SET revoked_at = now()
+	WHERE user_id = $1 AND revoked_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	if _, err := querier.Exec(ctx, query, userID); err != nil {
+		return fmt.Errorf("%s: failed to revoke sessions: %w", op, err)
+	}
+
+	return nil
+}
+
+func scanSession(row pgx.Row) (*sessionModel.Session, error) {
+	var session sessionModel.Session
+
+	err := row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.FamilyID,
+		&session.UserAgent,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+		&session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &session, nil
+}
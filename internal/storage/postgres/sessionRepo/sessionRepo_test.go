@@ -0,0 +1,145 @@
+package sessionRepo
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/sessionModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDB *pgxpool.Pool
+)
+
+func TestMain(m *testing.M) {
+	testDB = testutils.GetTestDB()
+	defer testDB.Close()
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestSessionRepo_SaveTouchAndList(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewSessionRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+	familyID := uuid.New()
+
+	id, err := repo.Save(ctx, sessionModel.Session{
+		UserID:    userID,
+		FamilyID:  familyID,
+		UserAgent: "Mozilla/5.0",
+		IP:        "192.0.2.10",
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Save(ctx, sessionModel.Session{
+		UserID:    userID,
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	expiresAt := time.Now().Add(2 * time.Hour)
+	touched, err := repo.Touch(ctx, familyID, expiresAt)
+	require.NoError(t, err)
+	assert.Equal(t, id, touched)
+
+	_, err = repo.Touch(ctx, uuid.New(), expiresAt)
+	assert.ErrorIs(t, err, storage.ErrSessionNotFound)
+
+	sessions, err := repo.ListActive(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, sessions, 1, "expired sessions are not listed")
+	assert.Equal(t, id, sessions[0].ID)
+	assert.Equal(t, familyID, sessions[0].FamilyID)
+	assert.Equal(t, "Mozilla/5.0", sessions[0].UserAgent)
+	assert.Equal(t, "192.0.2.10", sessions[0].IP)
+	assert.WithinDuration(t, expiresAt, sessions[0].ExpiresAt, time.Second)
+	assert.Nil(t, sessions[0].RevokedAt)
+}
+
+func TestSessionRepo_Revoke(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewSessionRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+	save := func() (uuid.UUID, uuid.UUID) {
+		familyID := uuid.New()
+		id, err := repo.Save(ctx, sessionModel.Session{
+			UserID:    userID,
+			FamilyID:  familyID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		return id, familyID
+	}
+
+	first, firstFamily := save()
+	second, secondFamily := save()
+	save()
+
+	_, err := repo.Revoke(ctx, uuid.New(), first)
+	assert.ErrorIs(t, err, storage.ErrSessionNotFound, "other user's session")
+
+	revoked, err := repo.Revoke(ctx, userID, first)
+	require.NoError(t, err)
+	assert.Equal(t, firstFamily, revoked.FamilyID)
+	assert.NotNil(t, revoked.RevokedAt)
+
+	_, err = repo.Revoke(ctx, userID, first)
+	assert.ErrorIs(t, err, storage.ErrSessionNotFound)
+
+	_, err = repo.Touch(ctx, firstFamily, time.Now().Add(time.Hour))
+	assert.ErrorIs(t, err, storage.ErrSessionNotFound, "revoked session")
+
+	id, err := repo.RevokeByFamily(ctx, secondFamily)
+	require.NoError(t, err)
+	assert.Equal(t, second, id)
+
+	_, err = repo.RevokeByFamily(ctx, secondFamily)
+	assert.ErrorIs(t, err, storage.ErrSessionNotFound)
+
+	require.NoError(t, repo.RevokeAllForUser(ctx, userID))
+
+	sessions, err := repo.ListActive(ctx, userID)
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+}
+
+func cleanTables(t *testing.T) {
+	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE users CASCADE")
+	require.NoError(t, err)
+}
+
+func createUser(t *testing.T) uuid.UUID {
+	var id uuid.UUID
+	err := testDB.QueryRow(
+		context.Background(),
+		"INSERT INTO users (email, hashed_password, status) VALUES ($1, 'hash', 'active') RETURNING id",
+		t.Name()+"@example.com",
+	).Scan(&id)
+	require.NoError(t, err)
+
+	return id
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
 	"github.com/Tbits007/auth/internal/domain/models/userModel"
     "github.com/Tbits007/auth/internal/storage/postgres/txManager"
 	"github.com/Tbits007/auth/internal/storage"
@@ -14,6 +16,20 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// isAdminColumn derives the legacy admin flag from role assignments.
+const isAdminColumn = `EXISTS (
+		SELECT 1 FROM user_roles ur
+		WHERE ur.user_id = users.id AND ur.role = '` + roleModel.RoleAdmin + `'
+	)`
+
+// mfaEnabledColumn is set once the user confirmed a TOTP enrollment.
+const mfaEnabledColumn = `EXISTS (
+		SELECT 1 FROM user_totp t
+		WHERE t.user_id = users.id AND t.confirmed_at IS NOT NULL
+	)`
+
+const userColumns = `id, email, hashed_password, ` + isAdminColumn + `, ` + mfaEnabledColumn + `, status, created_at`
+
 type UserRepo struct {
 	db *pgxpool.Pool
 }
@@ -24,15 +40,21 @@ func NewUserRepo(db *pgxpool.Pool) *UserRepo {
 	}
 }
 
+// Save inserts user. Its status must be set; there is no default, so that
+// a new account never becomes active by accident.
 func (u *UserRepo) Save(
 	ctx context.Context,
 	user userModel.User,
 ) (uuid.UUID, error) {
 	const op = "postgres.userRepo.Save"
 
+	if !user.Status.Valid() {
+		return uuid.Nil, fmt.Errorf("%s: invalid status %q", op, user.Status)
+	}
+
 	query := `
-	INSERT INTO users (email, hashed_password)
-	VALUES ($1, $2)
+	INSERT INTO users (email, hashed_password, status)
+	VALUES ($1, $2, $3)
 	RETURNING id
 	`
 
@@ -44,6 +66,7 @@ func (u *UserRepo) Save(
     err = querier.QueryRow(ctx, query,
         user.Email,
         user.HashedPassword,
+        user.Status,
     ).Scan(&id)
 
 
@@ -59,6 +82,8 @@ func (u *UserRepo) Save(
 } 
 
 
+// GetByEmail matches email case-insensitively, so that accounts stored
+// before emails were normalized are still found.
 func (u *UserRepo) GetByEmail(
 	ctx context.Context,
 	email string,
@@ -66,18 +91,13 @@ func (u *UserRepo) GetByEmail(
 	const op = "postgres.userRepo.GetByEmail"
 
 	query := `
-	SELECT email, hashed_password, is_admin
+	SELECT ` + userColumns + `
 	FROM users
-	WHERE email = $1
+	WHERE lower(email) = lower($1)
 	`
 
-    var user userModel.User
     querier := txManager.GetQuerier(ctx, u.db)
-    err := querier.QueryRow(ctx, query, email).Scan(
-        &user.Email,
-        &user.HashedPassword,
-        &user.IsAdmin,
-    )
+    user, err := scanUser(querier.QueryRow(ctx, query, email))
 
     switch {
     case errors.Is(err,  pgx.ErrNoRows):
@@ -90,6 +110,32 @@ func (u *UserRepo) GetByEmail(
 
 }
 
+func (u *UserRepo) GetByID(
+	ctx context.Context,
+	userID uuid.UUID,
+) (*userModel.User, error) {
+	const op = "postgres.userRepo.GetByID"
+
+	query := `
+	SELECT ` + userColumns + `
+	FROM users
+	WHERE id = $1
+	`
+
+    querier := txManager.GetQuerier(ctx, u.db)
+    user, err := scanUser(querier.QueryRow(ctx, query, userID))
+
+    switch {
+    case errors.Is(err, pgx.ErrNoRows):
+        return nil, fmt.Errorf("%s: user not found: %w", op, storage.ErrUserNotFound)
+    case err != nil:
+        return nil, fmt.Errorf("%s: failed to get user by ID: %w", op, err)
+    default:
+        return &user, nil
+    }
+
+}
+
 func (u *UserRepo) IsAdmin(
 	ctx context.Context,
 	userID uuid.UUID,
@@ -97,7 +143,7 @@ func (u *UserRepo) IsAdmin(
 	const op = "postgres.userRepo.IsAdmin"
 
 	query := `
-	SELECT is_admin
+	SELECT ` + isAdminColumn + `
 	FROM users
 	WHERE id = $1
 	`
@@ -119,3 +165,280 @@ func (u *UserRepo) IsAdmin(
 
 }
 
+func (u *UserRepo) HasPermission(
+	ctx context.Context,
+	userID uuid.UUID,
+	permission string,
+) (bool, error) {
+	const op = "postgres.userRepo.HasPermission"
+
+	query := `
+	SELECT EXISTS (
+		SELECT 1
+		FROM user_roles ur
+		JOIN role_permissions rp ON rp.role = ur.role
+		WHERE ur.user_id = users.id AND rp.permission = $2
+	)
+	FROM users
+	WHERE id = $1
+	`
+
+    var allowed bool
+    querier := txManager.GetQuerier(ctx, u.db)
+    err := querier.QueryRow(ctx, query, userID, permission).Scan(
+        &allowed,
+    )
+
+    switch {
+    case errors.Is(err, pgx.ErrNoRows):
+        return false, fmt.Errorf("%s: user not found: %w", op, storage.ErrUserNotFound)
+    case err != nil:
+        return false, fmt.Errorf("%s: failed to check permission: %w", op, err)
+    default:
+        return allowed, nil
+    }
+}
+
+// AssignRole grants role to the user. Assigning a role the user already
+// has is a no-op.
+func (u *UserRepo) AssignRole(
+	ctx context.Context,
+	userID uuid.UUID,
+	role string,
+) error {
+	const op = "postgres.userRepo.AssignRole"
+
+	query := `
+	INSERT INTO user_roles (user_id, role)
+	VALUES ($1, $2)
+	ON CONFLICT DO NOTHING
+	`
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    _, err := querier.Exec(ctx, query, userID, role)
+    if err != nil {
+        var pgErr *pgconn.PgError
+        if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+            if pgErr.ConstraintName == "user_roles_role_fkey" {
+                return fmt.Errorf("%s: %w", op, storage.ErrRoleNotFound)
+            }
+            return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+        }
+        return fmt.Errorf("%s: failed to assign role: %w", op, err)
+    }
+
+	return nil
+}
+
+// RevokeRole removes role from the user. Revoking a role the user does not
+// have is a no-op.
+func (u *UserRepo) RevokeRole(
+	ctx context.Context,
+	userID uuid.UUID,
+	role string,
+) error {
+	const op = "postgres.userRepo.RevokeRole"
+
+	query := `
+	DELETE FROM user_roles
+	WHERE user_id = $1 AND role = $2
+	`
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    if _, err := querier.Exec(ctx, query, userID, role); err != nil {
+        return fmt.Errorf("%s: failed to revoke role: %w", op, err)
+    }
+
+	return nil
+}
+
+// List returns up to filter.Limit users ordered by (created_at, id),
+// starting after filter.After.
+func (u *UserRepo) List(
+	ctx    context.Context,
+	filter userModel.ListFilter,
+) ([]userModel.User, error) {
+	const op = "postgres.userRepo.List"
+
+	var (
+		conditions []string
+		args       []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	if filter.EmailPrefix != "" {
+		conditions = append(conditions, "email LIKE "+arg(escapeLike(filter.EmailPrefix)+"%"))
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+arg(filter.Status))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		conditions = append(conditions, "created_at >= "+arg(filter.CreatedAfter))
+	}
+	if !filter.CreatedBefore.IsZero() {
+		conditions = append(conditions, "created_at < "+arg(filter.CreatedBefore))
+	}
+	if filter.After != nil {
+		conditions = append(conditions, fmt.Sprintf("(created_at, id) > (%s, %s)", arg(filter.After.CreatedAt), arg(filter.After.ID)))
+	}
+
+	query := `SELECT ` + userColumns + ` FROM users`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY created_at, id LIMIT ` + arg(filter.Limit)
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    rows, err := querier.Query(ctx, query, args...)
+    if err != nil {
+        return nil, fmt.Errorf("%s: failed to list users: %w", op, err)
+    }
+    defer rows.Close()
+
+    var users []userModel.User
+    for rows.Next() {
+        user, err := scanUser(rows)
+        if err != nil {
+            return nil, fmt.Errorf("%s: failed to scan user: %w", op, err)
+        }
+        users = append(users, user)
+    }
+
+    if err := rows.Err(); err != nil {
+        return nil, fmt.Errorf("%s: failed to read users: %w", op, err)
+    }
+
+	return users, nil
+}
+
+func (u *UserRepo) UpdateEmail(
+	ctx    context.Context,
+	userID uuid.UUID,
+	email  string,
+) error {
+	const op = "postgres.userRepo.UpdateEmail"
+
+	query := `
+	UPDATE users
+	SET email = $2, updated_at = now()
+	WHERE id = $1
+	`
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    tag, err := querier.Exec(ctx, query, userID, email)
+    if err != nil {
+        var pgErr *pgconn.PgError
+        if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+            return fmt.Errorf("%s: email already exists: %w", op, storage.ErrUserExists)
+        }
+        return fmt.Errorf("%s: failed to update email: %w", op, err)
+    }
+    if tag.RowsAffected() == 0 {
+        return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+    }
+
+	return nil
+}
+
+func (u *UserRepo) UpdatePassword(
+	ctx            context.Context,
+	userID         uuid.UUID,
+	hashedPassword string,
+) error {
+	const op = "postgres.userRepo.UpdatePassword"
+
+	query := `
+	UPDATE users
+	SET hashed_password = $2, updated_at = now()
+	WHERE id = $1
+	`
+
+	querier := txManager.GetQuerier(ctx, u.db)
+
+	tag, err := querier.Exec(ctx, query, userID, hashedPassword)
+	if err != nil {
+		return fmt.Errorf("%s: failed to update password: %w", op, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+	}
+
+	return nil
+}
+
+// SetStatus moves the user to status to, provided its current status is
+// one of from, and returns the previous status. It fails with
+// storage.ErrStatusConflict if the user is in any other status.
+func (u *UserRepo) SetStatus(
+	ctx    context.Context,
+	userID uuid.UUID,
+	from   []userModel.Status,
+	to     userModel.Status,
+) (userModel.Status, error) {
+	const op = "postgres.userRepo.SetStatus"
+
+	query := `
+	WITH old AS (
+		SELECT id, status FROM users WHERE id = $1 FOR UPDATE
+	)
+	UPDATE users u
+	SET status = $3, updated_at = now()
+	FROM old
+	WHERE u.id = old.id AND old.status = ANY($2)
+	RETURNING old.status
+	`
+
+	sources := make([]string, 0, len(from))
+	for _, status := range from {
+		sources = append(sources, string(status))
+	}
+
+    querier := txManager.GetQuerier(ctx, u.db)
+
+    var previous userModel.Status
+    err := querier.QueryRow(ctx, query, userID, sources, to).Scan(&previous)
+    if err == nil {
+        return previous, nil
+    }
+    if !errors.Is(err, pgx.ErrNoRows) {
+        return "", fmt.Errorf("%s: failed to set status: %w", op, err)
+    }
+
+    var current userModel.Status
+    err = querier.QueryRow(ctx, `SELECT status FROM users WHERE id = $1`, userID).Scan(&current)
+    switch {
+    case errors.Is(err, pgx.ErrNoRows):
+        return "", fmt.Errorf("%s: %w", op, storage.ErrUserNotFound)
+    case err != nil:
+        return "", fmt.Errorf("%s: failed to get status: %w", op, err)
+    default:
+        return current, fmt.Errorf("%s: user is %s: %w", op, current, storage.ErrStatusConflict)
+    }
+}
+
+func scanUser(row pgx.Row) (userModel.User, error) {
+	var user userModel.User
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.HashedPassword,
+		&user.IsAdmin,
+		&user.MFAEnabled,
+		&user.Status,
+		&user.CreatedAt,
+	)
+
+	return user, err
+}
+
+// escapeLike escapes the LIKE wildcards in s so that it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
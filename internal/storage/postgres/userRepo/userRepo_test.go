@@ -5,7 +5,9 @@ import (
 	"os"
 	"testing"
 
+	"github.com/Tbits007/auth/internal/domain/models/roleModel"
 	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/storage"
 	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -37,6 +39,7 @@ func TestSave_Success(t *testing.T) {
 	user := userModel.User{
 		Email:          "test@example.com",
 		HashedPassword: "hashed_password_123",
+		Status:         userModel.StatusActive,
 	}
 
 	id, err := repo.Save(ctx, user)
@@ -46,6 +49,47 @@ func TestSave_Success(t *testing.T) {
 	assertUserExists(t, id, user.Email)
 }
 
+func TestSave_RequiresStatus(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	for _, status := range []userModel.Status{"", "unknown"} {
+		_, err := repo.Save(ctx, userModel.User{
+			Email:          "test@example.com",
+			HashedPassword: "hashed_password",
+			Status:         status,
+		})
+		assert.Error(t, err, status)
+	}
+
+	// The CHECK constraint catches writes that bypass the repo.
+	_, err := testDB.Exec(ctx,
+		"INSERT INTO users (email, hashed_password, status) VALUES ('raw@example.com', 'hash', 'unknown')",
+	)
+	assert.Error(t, err)
+}
+
+func TestSave_NoStatusDefault(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	cleanTable(t)
+
+	// Without a default a write that forgets the status fails instead of
+	// creating an active account.
+	_, err := testDB.Exec(ctx,
+		"INSERT INTO users (email, hashed_password) VALUES ('raw@example.com', 'hash')",
+	)
+	assert.Error(t, err)
+}
+
 func TestSave_DuplicateEmail(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -58,6 +102,7 @@ func TestSave_DuplicateEmail(t *testing.T) {
 	user := userModel.User{
 		Email:          "duplicate@test.com",
 		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
 	}
 	_, err := repo.Save(ctx, user)
 	require.NoError(t, err)
@@ -68,6 +113,262 @@ func TestSave_DuplicateEmail(t *testing.T) {
 	assert.Contains(t, err.Error(), "email already exists")
 }
 
+func TestSave_DuplicateEmailCaseInsensitive(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	id, err := repo.Save(ctx, userModel.User{
+		Email:          "Mixed@Example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Save(ctx, userModel.User{
+		Email:          "mixed@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+
+	user, err := repo.GetByEmail(ctx, "MIXED@example.COM")
+	require.NoError(t, err)
+	assert.Equal(t, id, user.ID)
+}
+
+func TestAssignRole_GrantsPermissions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	id, err := repo.Save(ctx, userModel.User{
+		Email:          "support@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	allowed, err := repo.HasPermission(ctx, id, roleModel.PermUsersRead)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	require.NoError(t, repo.AssignRole(ctx, id, roleModel.RoleSupport))
+	require.NoError(t, repo.AssignRole(ctx, id, roleModel.RoleSupport))
+
+	allowed, err = repo.HasPermission(ctx, id, roleModel.PermUsersRead)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	allowed, err = repo.HasPermission(ctx, id, roleModel.PermRolesAssign)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	isAdmin, err := repo.IsAdmin(ctx, id)
+	require.NoError(t, err)
+	assert.False(t, isAdmin)
+
+	require.NoError(t, repo.RevokeRole(ctx, id, roleModel.RoleSupport))
+
+	allowed, err = repo.HasPermission(ctx, id, roleModel.PermUsersRead)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+}
+
+func TestAssignRole_AdminIsDerived(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	id, err := repo.Save(ctx, userModel.User{
+		Email:          "admin@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.AssignRole(ctx, id, roleModel.RoleAdmin))
+
+	isAdmin, err := repo.IsAdmin(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	user, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.True(t, user.IsAdmin)
+}
+
+func TestAssignRole_UnknownRoleAndUser(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	id, err := repo.Save(ctx, userModel.User{
+		Email:          "user@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	err = repo.AssignRole(ctx, id, "nonexistent")
+	assert.ErrorIs(t, err, storage.ErrRoleNotFound)
+
+	err = repo.AssignRole(ctx, uuid.New(), roleModel.RoleSupport)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+
+	_, err = repo.HasPermission(ctx, uuid.New(), roleModel.PermUsersRead)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func TestList_PaginatesAndFilters(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	for _, email := range []string{"alice@example.com", "al_ice@example.com", "bob@example.com"} {
+		_, err := repo.Save(ctx, userModel.User{
+			Email:          email,
+			HashedPassword: "hashed_password",
+			Status:         userModel.StatusActive,
+		})
+		require.NoError(t, err)
+	}
+
+	first, err := repo.List(ctx, userModel.ListFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+
+	last := first[1]
+	rest, err := repo.List(ctx, userModel.ListFilter{
+		Limit: 2,
+		After: &userModel.Cursor{CreatedAt: last.CreatedAt, ID: last.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.NotContains(t, []uuid.UUID{first[0].ID, first[1].ID}, rest[0].ID)
+
+	// "_" is matched literally, not as a wildcard.
+	matched, err := repo.List(ctx, userModel.ListFilter{EmailPrefix: "al_", Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, matched, 1)
+	assert.Equal(t, "al_ice@example.com", matched[0].Email)
+
+	_, err = repo.SetStatus(ctx, matched[0].ID, userModel.Sources(userModel.StatusDisabled), userModel.StatusDisabled)
+	require.NoError(t, err)
+
+	disabled, err := repo.List(ctx, userModel.ListFilter{Status: userModel.StatusDisabled, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, disabled, 1)
+	assert.Equal(t, userModel.StatusDisabled, disabled[0].Status)
+}
+
+func TestUpdateEmail_DuplicateAndMissing(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	id, err := repo.Save(ctx, userModel.User{
+		Email:          "first@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Save(ctx, userModel.User{
+		Email:          "second@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdateEmail(ctx, id, "renamed@example.com"))
+	assertUserExists(t, id, "renamed@example.com")
+
+	err = repo.UpdateEmail(ctx, id, "second@example.com")
+	assert.ErrorIs(t, err, storage.ErrUserExists)
+
+	err = repo.UpdateEmail(ctx, uuid.New(), "third@example.com")
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func TestUpdatePassword_Success(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	id, err := repo.Save(ctx, userModel.User{
+		Email:          "user@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, repo.UpdatePassword(ctx, id, "new_hashed_password"))
+
+	user, err := repo.GetByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, "new_hashed_password", user.HashedPassword)
+
+	err = repo.UpdatePassword(ctx, uuid.New(), "new_hashed_password")
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
+func TestSetStatus_Transitions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserRepo(testDB)
+	cleanTable(t)
+
+	id, err := repo.Save(ctx, userModel.User{
+		Email:          "user@example.com",
+		HashedPassword: "hashed_password",
+		Status:         userModel.StatusActive,
+	})
+	require.NoError(t, err)
+
+	previous, err := repo.SetStatus(ctx, id, userModel.Sources(userModel.StatusDeleted), userModel.StatusDeleted)
+	require.NoError(t, err)
+	assert.Equal(t, userModel.StatusActive, previous)
+
+	current, err := repo.SetStatus(ctx, id, userModel.Sources(userModel.StatusActive), userModel.StatusActive)
+	assert.ErrorIs(t, err, storage.ErrStatusConflict)
+	assert.Equal(t, userModel.StatusDeleted, current)
+
+	_, err = repo.SetStatus(ctx, uuid.New(), userModel.Sources(userModel.StatusDisabled), userModel.StatusDisabled)
+	assert.ErrorIs(t, err, storage.ErrUserNotFound)
+}
+
 func cleanTable(t *testing.T) {
 	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE users CASCADE")
 	require.NoError(t, err)
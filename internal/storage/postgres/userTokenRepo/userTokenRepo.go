@@ -0,0 +1,120 @@
+package userTokenRepo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/txManager"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type UserTokenRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewUserTokenRepo(db *pgxpool.Pool) *UserTokenRepo {
+	return &UserTokenRepo{
+		db: db,
+	}
+}
+
+func (r *UserTokenRepo) Save(
+	ctx   context.Context,
+	token tokenModel.UserToken,
+) (uuid.UUID, error) {
+	const op = "postgres.userTokenRepo.Save"
+
+	query := `
+	INSERT INTO user_tokens (user_id, purpose, token_hash, expires_at)
+	VALUES ($1, $2, $3, $4)
+	RETURNING id
+	`
+
+	var id uuid.UUID
+	querier := txManager.GetQuerier(ctx, r.db)
+
+	err := querier.QueryRow(ctx, query,
+		token.UserID,
+		token.Purpose,
+		token.TokenHash,
+		token.ExpiresAt,
+	).Scan(&id)
+
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return uuid.Nil, fmt.Errorf("%s: token already exists: %w", op, storage.ErrTokenExists)
+		}
+		return uuid.Nil, fmt.Errorf("%s: failed to save token: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// Consume marks an unused, unexpired token with the given purpose as used
+// and returns it. Any other token is reported as ErrTokenNotFound, so a
+// token can be consumed at most once even under concurrent requests.
+func (r *UserTokenRepo) Consume(
+	ctx       context.Context,
+	purpose   string,
+	tokenHash string,
+) (*tokenModel.UserToken, error) {
+	const op = "postgres.userTokenRepo.Consume"
+
+	query := `
+	UPDATE user_tokens
+	SET used_at = now()
+	WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > now()
+	RETURNING id, user_id, purpose, token_hash, expires_at, created_at, used_at
+	`
+
+	var token tokenModel.UserToken
+	querier := txManager.GetQuerier(ctx, r.db)
+	err := querier.QueryRow(ctx, query, tokenHash, purpose).Scan(
+		&token.ID,
+		&token.UserID,
+		&token.Purpose,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&token.CreatedAt,
+		&token.UsedAt,
+	)
+
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		return nil, fmt.Errorf("%s: active token not found: %w", op, storage.ErrTokenNotFound)
+	case err != nil:
+		return nil, fmt.Errorf("%s: failed to consume token: %w", op, err)
+	default:
+		return &token, nil
+	}
+}
+
+// InvalidateAll marks every unused token of the user with the given purpose
+// as used.
+func (r *UserTokenRepo) InvalidateAll(
+	ctx     context.Context,
+	userID  uuid.UUID,
+	purpose string,
+) error {
+	const op = "postgres.userTokenRepo.InvalidateAll"
+
+	query := `
+	UPDATE user_tokens
+	SET used_at = now()
+	WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`
+
+	querier := txManager.GetQuerier(ctx, r.db)
+	if _, err := querier.Exec(ctx, query, userID, purpose); err != nil {
+		return fmt.Errorf("%s: failed to invalidate tokens: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,135 @@
+package userTokenRepo
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Tbits007/auth/internal/domain/models/tokenModel"
+	"github.com/Tbits007/auth/internal/storage"
+	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testDB *pgxpool.Pool
+)
+
+func TestMain(m *testing.M) {
+	testDB = testutils.GetTestDB()
+	defer testDB.Close()
+
+	code := m.Run()
+	os.Exit(code)
+}
+
+func TestConsume_SingleUse(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserTokenRepo(testDB)
+	cleanTables(t)
+
+	token := tokenModel.UserToken{
+		UserID:    createUser(t),
+		Purpose:   tokenModel.PurposeEmailVerification,
+		TokenHash: "hash_" + t.Name(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	id, err := repo.Save(ctx, token)
+	require.NoError(t, err)
+
+	consumed, err := repo.Consume(ctx, token.Purpose, token.TokenHash)
+	require.NoError(t, err)
+	assert.Equal(t, id, consumed.ID)
+	assert.Equal(t, token.UserID, consumed.UserID)
+	assert.NotNil(t, consumed.UsedAt)
+
+	_, err = repo.Consume(ctx, token.Purpose, token.TokenHash)
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+}
+
+func TestConsume_ExpiredOrWrongPurpose(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserTokenRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+
+	_, err := repo.Save(ctx, tokenModel.UserToken{
+		UserID:    userID,
+		Purpose:   tokenModel.PurposeEmailVerification,
+		TokenHash: "expired_" + t.Name(),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Consume(ctx, tokenModel.PurposeEmailVerification, "expired_"+t.Name())
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+
+	_, err = repo.Save(ctx, tokenModel.UserToken{
+		UserID:    userID,
+		Purpose:   tokenModel.PurposeEmailVerification,
+		TokenHash: "valid_" + t.Name(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+
+	_, err = repo.Consume(ctx, "other", "valid_"+t.Name())
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+}
+
+func TestInvalidateAll(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx := context.Background()
+	repo := NewUserTokenRepo(testDB)
+	cleanTables(t)
+
+	userID := createUser(t)
+
+	for _, hash := range []string{"first_" + t.Name(), "second_" + t.Name()} {
+		_, err := repo.Save(ctx, tokenModel.UserToken{
+			UserID:    userID,
+			Purpose:   tokenModel.PurposeEmailVerification,
+			TokenHash: hash,
+			ExpiresAt: time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, repo.InvalidateAll(ctx, userID, tokenModel.PurposeEmailVerification))
+
+	_, err := repo.Consume(ctx, tokenModel.PurposeEmailVerification, "second_"+t.Name())
+	assert.ErrorIs(t, err, storage.ErrTokenNotFound)
+}
+
+func cleanTables(t *testing.T) {
+	_, err := testDB.Exec(context.Background(), "TRUNCATE TABLE users CASCADE")
+	require.NoError(t, err)
+}
+
+func createUser(t *testing.T) uuid.UUID {
+	var id uuid.UUID
+	err := testDB.QueryRow(
+		context.Background(),
+		"INSERT INTO users (email, hashed_password, status) VALUES ($1, 'hash', 'active') RETURNING id",
+		t.Name()+"@example.com",
+	).Scan(&id)
+	require.NoError(t, err)
+
+	return id
+}
@@ -0,0 +1,41 @@
+package redis_
+
+import (
+	"context"
+	"time"
+
+	"github.com/Tbits007/auth/internal/lib/ratelimiter"
+)
+
+// AttemptLimiter limits repeated attempts within the service's own flows.
+// Both methods fail with ratelimiter.ErrRateLimited once more than limit
+// attempts happened within period.
+type AttemptLimiter struct {
+	limiter *ratelimiter.Limiter
+}
+
+func NewAttemptLimiter(limiter *ratelimiter.Limiter) *AttemptLimiter {
+	return &AttemptLimiter{limiter: limiter}
+}
+
+// AllowMFAAttempt counts a code tried against the MFA challenge
+// challengeID.
+func (at *AttemptLimiter) AllowMFAAttempt(
+	ctx         context.Context,
+	challengeID string,
+	limit       int,
+	period      time.Duration,
+) error {
+	return at.limiter.Allow(ctx, secretKey(kindMFAAttempts, challengeID), limit, period)
+}
+
+// AllowVerificationResend counts a verification email requested for
+// email.
+func (at *AttemptLimiter) AllowVerificationResend(
+	ctx    context.Context,
+	email  string,
+	limit  int,
+	period time.Duration,
+) error {
+	return at.limiter.Allow(ctx, emailKey(kindResendVerification, email), limit, period)
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
 	"github.com/Tbits007/auth/internal/storage"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -18,14 +23,410 @@ func NewCacheRepo(db *redis.Client) *CacheRepo {
 	return &CacheRepo{db: db}
 }
 
-func (ca *CacheRepo) Set(
-	ctx 	   context.Context,
+func (ca *CacheRepo) SetMFAChallenge(
+	ctx        context.Context,
+	id         string,
+	challenge  []byte,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.SetMFAChallenge"
+
+	return ca.set(ctx, op, secretKey(kindMFAChallenge, id), challenge, expiration)
+}
+
+// GetMFAChallenge returns the MFA challenge id, or storage.ErrKeyNotFound
+// if it expired or was taken.
+func (ca *CacheRepo) GetMFAChallenge(
+	ctx context.Context,
+	id  string,
+) ([]byte, error) {
+	const op = "redis.cacheRepo.GetMFAChallenge"
+
+	return ca.get(ctx, op, secretKey(kindMFAChallenge, id))
+}
+
+// TakeMFAChallenge returns the MFA challenge id and deletes it, so that of
+// concurrent callers only one gets it.
+func (ca *CacheRepo) TakeMFAChallenge(
+	ctx context.Context,
+	id  string,
+) ([]byte, error) {
+	const op = "redis.cacheRepo.TakeMFAChallenge"
+
+	return ca.getDel(ctx, op, secretKey(kindMFAChallenge, id))
+}
+
+func (ca *CacheRepo) DeleteMFAChallenge(
+	ctx context.Context,
+	id  string,
+) error {
+	const op = "redis.cacheRepo.DeleteMFAChallenge"
+
+	return ca.del(ctx, op, secretKey(kindMFAChallenge, id))
+}
+
+// SetPasskeyRegistration stores the WebAuthn session of a registration
+// started by userID. A newer registration replaces it.
+func (ca *CacheRepo) SetPasskeyRegistration(
+	ctx        context.Context,
+	userID     uuid.UUID,
+	session    []byte,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.SetPasskeyRegistration"
+
+	return ca.set(ctx, op, keyFor(kindPasskeyRegistration, userID.String()), session, expiration)
+}
+
+// TakePasskeyRegistration returns and deletes the registration session of
+// userID, or fails with storage.ErrKeyNotFound.
+func (ca *CacheRepo) TakePasskeyRegistration(
+	ctx    context.Context,
+	userID uuid.UUID,
+) ([]byte, error) {
+	const op = "redis.cacheRepo.TakePasskeyRegistration"
+
+	return ca.getDel(ctx, op, keyFor(kindPasskeyRegistration, userID.String()))
+}
+
+func (ca *CacheRepo) SetPasskeyLogin(
+	ctx         context.Context,
+	challengeID string,
+	session     []byte,
+	expiration  time.Duration,
+) error {
+	const op = "redis.cacheRepo.SetPasskeyLogin"
+
+	return ca.set(ctx, op, secretKey(kindPasskeyLogin, challengeID), session, expiration)
+}
+
+// TakePasskeyLogin returns and deletes the login session of challengeID,
+// or fails with storage.ErrKeyNotFound.
+func (ca *CacheRepo) TakePasskeyLogin(
+	ctx         context.Context,
+	challengeID string,
+) ([]byte, error) {
+	const op = "redis.cacheRepo.TakePasskeyLogin"
+
+	return ca.getDel(ctx, op, secretKey(kindPasskeyLogin, challengeID))
+}
+
+// GetCheck returns the cached result of a relation check, or
+// storage.ErrKeyNotFound if none is cached, along with the version to
+// pass to SetCheck.
+func (ca *CacheRepo) GetCheck(
+	ctx   context.Context,
+	tuple relationModel.Tuple,
+) (bool, int64, error) {
+	const op = "redis.cacheRepo.GetCheck"
+
+	val, version, err := ca.getVersioned(ctx, op, checkKey(kindCheck, tuple), checkKey(kindCheckVersion, tuple))
+	if err != nil {
+		return false, version, err
+	}
+
+	allowed, err := strconv.ParseBool(string(val))
+	if err != nil {
+		return false, version, fmt.Errorf("%s: invalid check result: %w", op, err)
+	}
+
+	return allowed, version, nil
+}
+
+// SetCheck caches the result of a relation check, unless DeleteCheck ran
+// for the tuple since version was read.
+func (ca *CacheRepo) SetCheck(
+	ctx        context.Context,
+	tuple      relationModel.Tuple,
+	allowed    bool,
+	version    int64,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.SetCheck"
+
+	return ca.setVersioned(ctx, op, checkKey(kindCheck, tuple), checkKey(kindCheckVersion, tuple), version, strconv.FormatBool(allowed), expiration)
+}
+
+// DeleteCheck drops the cached result of a relation check and bumps its
+// version, so that a result read before it is not cached afterwards.
+func (ca *CacheRepo) DeleteCheck(
+	ctx   context.Context,
+	tuple relationModel.Tuple,
+) error {
+	const op = "redis.cacheRepo.DeleteCheck"
+
+	return ca.invalidate(ctx, op, checkKey(kindCheckVersion, tuple), checkKey(kindCheck, tuple))
+}
+
+// GetUserStatus returns the cached account status of userID, or
+// storage.ErrKeyNotFound if none is cached. Either way it returns the
+// version of the user's cache entries, which a caller that reads the
+// status elsewhere passes to SetUserStatus.
+func (ca *CacheRepo) GetUserStatus(
+	ctx    context.Context,
+	userID uuid.UUID,
+) (userModel.Status, int64, error) {
+	const op = "redis.cacheRepo.GetUserStatus"
+
+	id := userID.String()
+	val, version, err := ca.getVersioned(ctx, op, keyFor(kindUserStatus, id), keyFor(kindUserVersion, id))
+	if err != nil {
+		return "", version, err
+	}
+
+	return userModel.Status(val), version, nil
+}
+
+// SetUserStatus caches status for userID, unless InvalidateUser ran since
+// version was read; the status may then be stale and is dropped.
+func (ca *CacheRepo) SetUserStatus(
+	ctx        context.Context,
+	userID     uuid.UUID,
+	status     userModel.Status,
+	version    int64,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.SetUserStatus"
+
+	id := userID.String()
+	return ca.setVersioned(ctx, op, keyFor(kindUserStatus, id), keyFor(kindUserVersion, id), version, string(status), expiration)
+}
+
+// GetUserAdmin returns whether userID was cached as an admin, or
+// storage.ErrKeyNotFound if nothing is cached, along with the version to
+// pass to SetUserAdmin.
+func (ca *CacheRepo) GetUserAdmin(
+	ctx    context.Context,
+	userID uuid.UUID,
+) (bool, int64, error) {
+	const op = "redis.cacheRepo.GetUserAdmin"
+
+	id := userID.String()
+	val, version, err := ca.getVersioned(ctx, op, keyFor(kindUserAdmin, id), keyFor(kindUserVersion, id))
+	if err != nil {
+		return false, version, err
+	}
+
+	isAdmin, err := strconv.ParseBool(string(val))
+	if err != nil {
+		return false, version, fmt.Errorf("%s: invalid admin flag: %w", op, err)
+	}
+
+	return isAdmin, version, nil
+}
+
+// SetUserAdmin caches the admin flag of userID, unless InvalidateUser ran
+// since version was read.
+func (ca *CacheRepo) SetUserAdmin(
+	ctx        context.Context,
+	userID     uuid.UUID,
+	isAdmin    bool,
+	version    int64,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.SetUserAdmin"
+
+	id := userID.String()
+	return ca.setVersioned(ctx, op, keyFor(kindUserAdmin, id), keyFor(kindUserVersion, id), version, strconv.FormatBool(isAdmin), expiration)
+}
+
+// InvalidateUser drops every cached attribute of userID and bumps their
+// version, so that values read before it are not cached afterwards.
+// Whatever changes a user must call it once the change is committed.
+func (ca *CacheRepo) InvalidateUser(
+	ctx    context.Context,
+	userID uuid.UUID,
+) error {
+	const op = "redis.cacheRepo.InvalidateUser"
+
+	id := userID.String()
+	return ca.invalidate(ctx, op, keyFor(kindUserVersion, id), keyFor(kindUserAdmin, id), keyFor(kindUserStatus, id))
+}
+
+func (ca *CacheRepo) RevokeToken(
+	ctx        context.Context,
+	jti        string,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.RevokeToken"
+
+	return ca.set(ctx, op, keyFor(kindRevokedToken, jti), 1, expiration)
+}
+
+// RevokeUserTokens revokes every token of userID issued up to revokedAt.
+// Token timestamps only carry seconds, so the cutoff is the second after
+// revokedAt: tokens issued in the same second are revoked as well. The
+// tokens of the session keep, if not uuid.Nil, are spared, so that a
+// session started alongside the revocation survives it. expiration
+// should cover the lifetime of the revoked tokens.
+func (ca *CacheRepo) RevokeUserTokens(
+	ctx        context.Context,
+	userID     uuid.UUID,
+	revokedAt  time.Time,
+	keep       uuid.UUID,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.RevokeUserTokens"
+
+	cutoff := strconv.FormatInt(revokedAt.Unix()+1, 10)
+	if keep != uuid.Nil {
+		cutoff += ":" + keep.String()
+	}
+
+	return ca.set(ctx, op, keyFor(kindRevokedUser, userID.String()), cutoff, expiration)
+}
+
+// RevokeSession revokes every token issued for the login session
+// sessionID. expiration should cover the lifetime of those tokens.
+func (ca *CacheRepo) RevokeSession(
+	ctx        context.Context,
+	sessionID  uuid.UUID,
+	expiration time.Duration,
+) error {
+	const op = "redis.cacheRepo.RevokeSession"
+
+	return ca.set(ctx, op, keyFor(kindRevokedSession, sessionID.String()), 1, expiration)
+}
+
+// IsTokenRevoked reports whether the token jti, issued to userID at
+// issuedAt for the session sessionID, was revoked on its own, with its
+// session or by a user-wide revocation. sessionID is uuid.Nil for tokens
+// without a session.
+func (ca *CacheRepo) IsTokenRevoked(
+	ctx       context.Context,
+	jti       string,
+	userID    uuid.UUID,
+	sessionID uuid.UUID,
+	issuedAt  time.Time,
+) (bool, error) {
+	const op = "redis.cacheRepo.IsTokenRevoked"
+
+	keys := []string{keyFor(kindRevokedToken, jti), keyFor(kindRevokedUser, userID.String())}
+	if sessionID != uuid.Nil {
+		keys = append(keys, keyFor(kindRevokedSession, sessionID.String()))
+	}
+
+	vals, err := ca.db.MGet(ctx, keys...).Result()
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if vals[0] != nil || (len(vals) > 2 && vals[2] != nil) {
+		return true, nil
+	}
+
+	raw, ok := vals[1].(string)
+	if !ok {
+		return false, nil
+	}
+
+	rawCutoff, keep, _ := strings.Cut(raw, ":")
+	if sessionID != uuid.Nil && keep == sessionID.String() {
+		return false, nil
+	}
+
+	cutoff, err := strconv.ParseInt(rawCutoff, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("%s: invalid user revocation cutoff: %w", op, err)
+	}
+
+	return issuedAt.Unix() < cutoff, nil
+}
+
+// versionTTL keeps a version key long enough to outlast any read that
+// started before the invalidation which bumped it.
+const versionTTL = 24 * time.Hour
+
+// getVersioned returns the value at key, or storage.ErrKeyNotFound, and
+// the current version of versionKey, which is 0 while it is unset.
+func (ca *CacheRepo) getVersioned(
+	ctx        context.Context,
+	op         string,
+	key        string,
+	versionKey string,
+) ([]byte, int64, error) {
+	vals, err := ca.db.MGet(ctx, key, versionKey).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	var version int64
+	if raw, ok := vals[1].(string); ok {
+		version, err = strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%s: invalid version: %w", op, err)
+		}
+	}
+
+	val, ok := vals[0].(string)
+	if !ok {
+		return nil, version, fmt.Errorf("%s: %w", op, storage.ErrKeyNotFound)
+	}
+
+	return []byte(val), version, nil
+}
+
+// setVersioned stores value at key if versionKey is still at version. A
+// value read before an invalidation may be stale, so it is silently
+// dropped once the version moved on, including while it is being set.
+func (ca *CacheRepo) setVersioned(
+	ctx        context.Context,
+	op         string,
 	key        string,
-	value 	   any, 
+	versionKey string,
+	version    int64,
+	value      any,
 	expiration time.Duration,
 ) error {
-	const op = "redis.cacheRepo.Set"
+	err := ca.db.Watch(ctx, func(tx *redis.Tx) error {
+		current, err := tx.Get(ctx, versionKey).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			return err
+		}
+		if current != version {
+			return nil
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, value, expiration)
+			return nil
+		})
+		return err
+	}, versionKey)
+	if err != nil && !errors.Is(err, redis.TxFailedErr) {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// invalidate bumps versionKey and deletes keys in one transaction.
+func (ca *CacheRepo) invalidate(
+	ctx        context.Context,
+	op         string,
+	versionKey string,
+	keys       ...string,
+) error {
+	_, err := ca.db.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, versionKey)
+		pipe.Expire(ctx, versionKey, versionTTL)
+		pipe.Del(ctx, keys...)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
 
+	return nil
+}
+
+func (ca *CacheRepo) set(
+	ctx        context.Context,
+	op         string,
+	key        string,
+	value      any,
+	expiration time.Duration,
+) error {
 	if err := ca.db.Set(ctx, key, value, expiration).Err(); err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
@@ -33,19 +434,46 @@ func (ca *CacheRepo) Set(
 	return nil
 }
 
-func (ca *CacheRepo) Get(
+func (ca *CacheRepo) get(
 	ctx context.Context,
+	op  string,
 	key string,
-) (string, error) {
-	const op = "redis.cacheRepo.Get"
+) ([]byte, error) {
+	val, err := ca.db.Get(ctx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrKeyNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
 
-	val, err := ca.db.Get(ctx, key).Result()
+	return val, nil
+}
+
+func (ca *CacheRepo) getDel(
+	ctx context.Context,
+	op  string,
+	key string,
+) ([]byte, error) {
+	val, err := ca.db.GetDel(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return "", fmt.Errorf("%s: key %s: %w", op, key, storage.ErrKeyNotFound)	
-		}		
-		return "", fmt.Errorf("%s: %w", op, err)
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrKeyNotFound)
+		}
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return val, nil
-}
\ No newline at end of file
+}
+
+func (ca *CacheRepo) del(
+	ctx context.Context,
+	op  string,
+	key string,
+) error {
+	if err := ca.db.Del(ctx, key).Err(); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
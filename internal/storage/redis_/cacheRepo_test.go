@@ -6,8 +6,12 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/domain/models/userModel"
+	"github.com/Tbits007/auth/internal/lib/opaque"
 	"github.com/Tbits007/auth/internal/storage"
 	"github.com/Tbits007/auth/internal/storage/postgres/testutils"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -25,7 +29,7 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
-func TestSetAndGet_Success(t *testing.T) {
+func TestMFAChallenge_SetAndGet(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
 	}
@@ -33,18 +37,23 @@ func TestSetAndGet_Success(t *testing.T) {
 	repo := NewCacheRepo(testRDB)
 	ctx := context.Background()
 
-	key := "test_key_" + t.Name()
-	value := "test_value"
+	id := "challenge_" + t.Name()
+	value := []byte(`{"user_id":"1"}`)
 
-	err := repo.Set(ctx, key, value, time.Minute)
+	err := repo.SetMFAChallenge(ctx, id, value, time.Minute)
 	require.NoError(t, err)
 
-	result, err := repo.Get(ctx, key)
+	result, err := repo.GetMFAChallenge(ctx, id)
 	require.NoError(t, err)
 	assert.Equal(t, value, result)
+
+	// Challenge IDs are bearer secrets and are only stored hashed.
+	exists, err := testRDB.Exists(ctx, "auth:mfa_challenge:"+opaque.Hash(id)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
 }
 
-func TestGet_KeyNotFound(t *testing.T) {
+func TestGetMFAChallenge_KeyNotFound(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
 	}
@@ -52,16 +61,14 @@ func TestGet_KeyNotFound(t *testing.T) {
 	repo := NewCacheRepo(testRDB)
 	ctx := context.Background()
 
-	key := "non_existent_key_" + t.Name()
-
-	result, err := repo.Get(ctx, key)
+	result, err := repo.GetMFAChallenge(ctx, "non_existent_"+t.Name())
 
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
 	assert.Empty(t, result)
 }
 
-func TestSet_Expiration(t *testing.T) {
+func TestMFAChallenge_Expiration(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
 	}
@@ -69,38 +76,352 @@ func TestSet_Expiration(t *testing.T) {
 	repo := NewCacheRepo(testRDB)
 	ctx := context.Background()
 
-	key := "expiring_key_" + t.Name()
-	value := "temp_value"
+	id := "expiring_" + t.Name()
 	ttl := 1 * time.Second
 
-	err := repo.Set(ctx, key, value, ttl)
+	err := repo.SetMFAChallenge(ctx, id, []byte("value"), ttl)
 	require.NoError(t, err)
 
-	result, err := repo.Get(ctx, key)
+	_, err = repo.GetMFAChallenge(ctx, id)
 	require.NoError(t, err)
-	assert.Equal(t, value, result)
 
 	time.Sleep(ttl + 100*time.Millisecond)
 
-	_, err = repo.Get(ctx, key)
+	_, err = repo.GetMFAChallenge(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
+}
+
+func TestMFAChallenge_TakeAndDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	id := "take_" + t.Name()
+	require.NoError(t, repo.SetMFAChallenge(ctx, id, []byte("value"), time.Minute))
+
+	result, err := repo.TakeMFAChallenge(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), result)
+
+	_, err = repo.TakeMFAChallenge(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	require.NoError(t, repo.SetMFAChallenge(ctx, id, []byte("value"), time.Minute))
+	require.NoError(t, repo.DeleteMFAChallenge(ctx, id))
+
+	_, err = repo.GetMFAChallenge(ctx, id)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
+}
+
+func TestCheck_SetGetDelete(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	tuple := relationModel.Tuple{
+		Object:   "organization:" + t.Name(),
+		Relation: relationModel.RelationViewer,
+		Subject:  "user:1",
+	}
+
+	_, version, err := repo.GetCheck(ctx, tuple)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	require.NoError(t, repo.SetCheck(ctx, tuple, false, version, time.Minute))
+
+	allowed, _, err := repo.GetCheck(ctx, tuple)
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	require.NoError(t, repo.SetCheck(ctx, tuple, true, version, time.Minute))
+
+	allowed, _, err = repo.GetCheck(ctx, tuple)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+
+	require.NoError(t, repo.DeleteCheck(ctx, tuple))
+
+	_, _, err = repo.GetCheck(ctx, tuple)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
+}
+
+func TestDeleteCheck_DuringRead(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	tuple := relationModel.Tuple{
+		Object:   "organization:" + t.Name(),
+		Relation: relationModel.RelationViewer,
+		Subject:  "user:1",
+	}
+
+	// A check misses and reads the relations before a grant is written.
+	_, version, err := repo.GetCheck(ctx, tuple)
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	require.NoError(t, repo.DeleteCheck(ctx, tuple))
+
+	require.NoError(t, repo.SetCheck(ctx, tuple, false, version, time.Minute))
+
+	_, _, err = repo.GetCheck(ctx, tuple)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound, "stale denial must not be cached")
+
+	// A check that starts after the eviction is cached again.
+	_, version, err = repo.GetCheck(ctx, tuple)
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	require.NoError(t, repo.SetCheck(ctx, tuple, true, version, time.Minute))
+
+	allowed, _, err := repo.GetCheck(ctx, tuple)
+	require.NoError(t, err)
+	assert.True(t, allowed)
+}
+
+func TestRevokeToken_Expiration(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	jti := "jti_" + t.Name()
+	userID := uuid.New()
+	ttl := 1 * time.Second
+
+	revoked, err := repo.IsTokenRevoked(ctx, jti, userID, uuid.Nil, time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	require.NoError(t, repo.RevokeToken(ctx, jti, ttl))
+
+	revoked, err = repo.IsTokenRevoked(ctx, jti, userID, uuid.Nil, time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	time.Sleep(ttl + 100*time.Millisecond)
+
+	revoked, err = repo.IsTokenRevoked(ctx, jti, userID, uuid.Nil, time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestRevokeUserTokens_OnlyOlderTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	revokedAt := time.Unix(time.Now().Unix(), 0)
+
+	require.NoError(t, repo.RevokeUserTokens(ctx, userID, revokedAt, uuid.Nil, time.Minute))
+
+	revoked, err := repo.IsTokenRevoked(ctx, "jti_old_"+t.Name(), userID, uuid.Nil, revokedAt.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_same_"+t.Name(), userID, uuid.Nil, revokedAt)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_new_"+t.Name(), userID, uuid.Nil, revokedAt.Add(time.Second))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_other_"+t.Name(), uuid.New(), uuid.Nil, revokedAt.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestRevokeUserTokens_KeepSession(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	keep := uuid.New()
+	revokedAt := time.Now()
+
+	require.NoError(t, repo.RevokeUserTokens(ctx, userID, revokedAt, keep, time.Minute))
+
+	revoked, err := repo.IsTokenRevoked(ctx, "jti_keep_"+t.Name(), userID, keep, revokedAt)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_other_session_"+t.Name(), userID, uuid.New(), revokedAt)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_no_session_"+t.Name(), userID, uuid.Nil, revokedAt)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	// Revoking the kept session itself still applies.
+	require.NoError(t, repo.RevokeSession(ctx, keep, time.Minute))
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_keep_"+t.Name(), userID, keep, revokedAt)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+}
+
+func TestRevokeSession_OnlySessionTokens(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	sessionID := uuid.New()
+
+	require.NoError(t, repo.RevokeSession(ctx, sessionID, time.Minute))
+
+	revoked, err := repo.IsTokenRevoked(ctx, "jti_session_"+t.Name(), userID, sessionID, time.Now())
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_other_session_"+t.Name(), userID, uuid.New(), time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	revoked, err = repo.IsTokenRevoked(ctx, "jti_no_session_"+t.Name(), userID, uuid.Nil, time.Now())
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+func TestPasskeySessions_SingleUse(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	challengeID := "challenge_" + t.Name()
+	require.NoError(t, repo.SetPasskeyRegistration(ctx, userID, []byte("registration"), time.Minute))
+	require.NoError(t, repo.SetPasskeyLogin(ctx, challengeID, []byte("login"), time.Minute))
+
+	result, err := repo.TakePasskeyRegistration(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("registration"), result)
+
+	_, err = repo.TakePasskeyRegistration(ctx, userID)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	result, err = repo.TakePasskeyLogin(ctx, challengeID)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("login"), result)
+
+	_, err = repo.TakePasskeyLogin(ctx, challengeID)
 	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
 }
 
-func TestSet_NonStringValue(t *testing.T) {
+func TestUserCache_Namespaced(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+	require.NoError(t, repo.SetUserAdmin(ctx, userID, true, 0, time.Minute))
+	require.NoError(t, repo.SetUserStatus(ctx, userID, userModel.StatusActive, 0, time.Minute))
+
+	isAdmin, _, err := repo.GetUserAdmin(ctx, userID)
+	require.NoError(t, err)
+	assert.True(t, isAdmin)
+
+	status, _, err := repo.GetUserStatus(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, userModel.StatusActive, status)
+
+	exists, err := testRDB.Exists(ctx, "auth:status:"+userID.String()).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
+
+	ttl, err := testRDB.TTL(ctx, "auth:admin:"+userID.String()).Result()
+	require.NoError(t, err)
+	assert.True(t, ttl > 0 && ttl <= time.Minute)
+}
+
+func TestInvalidateUser_DropsAllAttributes(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
 	}
-	
+
 	repo := NewCacheRepo(testRDB)
 	ctx := context.Background()
 
-	key := "int_key_" + t.Name()
-	value := 42 
+	userID := uuid.New()
+	otherID := uuid.New()
+	require.NoError(t, repo.SetUserAdmin(ctx, userID, false, 0, time.Minute))
+	require.NoError(t, repo.SetUserStatus(ctx, userID, userModel.StatusDisabled, 0, time.Minute))
+	require.NoError(t, repo.SetUserAdmin(ctx, otherID, true, 0, time.Minute))
+
+	require.NoError(t, repo.InvalidateUser(ctx, userID))
+
+	_, _, err := repo.GetUserAdmin(ctx, userID)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	_, _, err = repo.GetUserStatus(ctx, userID)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound)
 
-	err := repo.Set(ctx, key, value, time.Minute)
+	isAdmin, _, err := repo.GetUserAdmin(ctx, otherID)
 	require.NoError(t, err)
+	assert.True(t, isAdmin)
+}
+
+func TestInvalidateUser_DuringRead(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewCacheRepo(testRDB)
+	ctx := context.Background()
+
+	userID := uuid.New()
+
+	// A reader misses and goes to the database, which still says active.
+	_, version, err := repo.GetUserStatus(ctx, userID)
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	_, adminVersion, err := repo.GetUserAdmin(ctx, userID)
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+
+	// The user is disabled and evicted before the reader caches its values.
+	require.NoError(t, repo.InvalidateUser(ctx, userID))
+
+	require.NoError(t, repo.SetUserStatus(ctx, userID, userModel.StatusActive, version, time.Minute))
+	require.NoError(t, repo.SetUserAdmin(ctx, userID, true, adminVersion, time.Minute))
+
+	_, _, err = repo.GetUserStatus(ctx, userID)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound, "stale status must not be cached")
+	_, _, err = repo.GetUserAdmin(ctx, userID)
+	assert.ErrorIs(t, err, storage.ErrKeyNotFound, "stale admin flag must not be cached")
+
+	// A read that starts after the eviction is cached again.
+	_, version, err = repo.GetUserStatus(ctx, userID)
+	require.ErrorIs(t, err, storage.ErrKeyNotFound)
+	require.NoError(t, repo.SetUserStatus(ctx, userID, userModel.StatusDisabled, version, time.Minute))
 
-	result, err := repo.Get(ctx, key)
+	status, _, err := repo.GetUserStatus(ctx, userID)
 	require.NoError(t, err)
-	assert.Equal(t, "42", result)
+	assert.Equal(t, userModel.StatusDisabled, status)
 }
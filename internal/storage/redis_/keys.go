@@ -0,0 +1,52 @@
+package redis_
+
+import (
+	"strings"
+
+	"github.com/Tbits007/auth/internal/domain/models/relationModel"
+	"github.com/Tbits007/auth/internal/lib/opaque"
+)
+
+// Every key this package uses is built here as auth:<kind>:<id>, so that
+// keys of different kinds cannot collide and all of them can be told
+// apart from other data in the same database. Rate limit keys get the
+// "rate:" prefix of redis_rate in front.
+const keyNamespace = "auth:"
+
+const (
+	kindRevokedToken        = "revoked_token"
+	kindRevokedUser         = "revoked_user"
+	kindRevokedSession      = "revoked_session"
+	kindUserAdmin           = "admin"
+	kindUserStatus          = "status"
+	kindUserVersion         = "user_version"
+	kindMFAChallenge        = "mfa_challenge"
+	kindMFAAttempts         = "mfa_attempts"
+	kindPasskeyRegistration = "passkey_registration"
+	kindPasskeyLogin        = "passkey_login"
+	kindCheck               = "check"
+	kindCheckVersion        = "check_version"
+	kindResendVerification  = "resend_verification"
+	kindLoginFailures       = "login_failures"
+	kindLoginLock           = "login_lock"
+)
+
+func keyFor(kind, id string) string {
+	return keyNamespace + kind + ":" + id
+}
+
+// secretKey builds the key of an id that is a bearer secret, such as a
+// challenge ID, which must not appear in Redis in clear.
+func secretKey(kind, id string) string {
+	return keyFor(kind, opaque.Hash(id))
+}
+
+func emailKey(kind, email string) string {
+	return keyFor(kind, strings.ToLower(email))
+}
+
+// checkKey expects a valid tuple, whose parts cannot contain the '#' and
+// '@' separators, so that distinct tuples never share a key.
+func checkKey(kind string, tuple relationModel.Tuple) string {
+	return keyFor(kind, tuple.Object+"#"+tuple.Relation+"@"+tuple.Subject)
+}
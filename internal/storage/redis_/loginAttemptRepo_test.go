@@ -0,0 +1,67 @@
+package redis_
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterFailure_Counts(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewLoginAttemptRepo(testRDB)
+	ctx := context.Background()
+	key := "email:" + t.Name()
+	t.Cleanup(func() { _ = repo.Reset(ctx, key) })
+
+	for want := 1; want <= 3; want++ {
+		got, err := repo.RegisterFailure(ctx, key, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+
+	require.NoError(t, repo.Reset(ctx, key))
+
+	got, err := repo.RegisterFailure(ctx, key, time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, 1, got)
+}
+
+func TestLockedFor_LongestLock(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	repo := NewLoginAttemptRepo(testRDB)
+	ctx := context.Background()
+	emailKey := "email:" + t.Name()
+	ipKey := "ip:" + t.Name()
+	t.Cleanup(func() {
+		_ = repo.Reset(ctx, emailKey)
+		_ = repo.Reset(ctx, ipKey)
+	})
+
+	lockedFor, err := repo.LockedFor(ctx, emailKey, ipKey)
+	require.NoError(t, err)
+	assert.Zero(t, lockedFor)
+
+	require.NoError(t, repo.Lock(ctx, emailKey, time.Minute))
+	require.NoError(t, repo.Lock(ctx, ipKey, time.Hour))
+
+	lockedFor, err = repo.LockedFor(ctx, emailKey, ipKey)
+	require.NoError(t, err)
+	assert.Greater(t, lockedFor, time.Minute)
+	assert.LessOrEqual(t, lockedFor, time.Hour)
+
+	require.NoError(t, repo.Reset(ctx, ipKey))
+
+	lockedFor, err = repo.LockedFor(ctx, emailKey, ipKey)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, lockedFor, time.Minute)
+	assert.Positive(t, lockedFor)
+}
@@ -9,5 +9,21 @@ var (
     ErrEventNotFound = errors.New("event not found")
     ErrEventExists   = errors.New("event already exists")
 
+    ErrTokenNotFound = errors.New("token not found")
+    ErrTokenExists   = errors.New("token already exists")
+
     ErrKeyNotFound   = errors.New("key not found")
+
+    ErrRoleNotFound  = errors.New("role not found")
+
+    ErrStatusConflict = errors.New("user status conflict")
+
+    ErrMFANotFound   = errors.New("mfa enrollment not found")
+    ErrMFAExists     = errors.New("mfa already enabled")
+    ErrCodeNotFound  = errors.New("code not found")
+
+    ErrPasskeyNotFound = errors.New("passkey not found")
+    ErrPasskeyExists   = errors.New("passkey already exists")
+
+    ErrSessionNotFound = errors.New("session not found")
 )
\ No newline at end of file
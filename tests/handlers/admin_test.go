@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/Tbits007/auth/tests/suite"
+	au "github.com/Tbits007/contract/gen/go/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAdminService_ManageUsers(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	adminUser, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "admin@example.com",
+		Password: "admin123",
+	})
+	require.NoError(t, err)
+
+	makeAdmin(t, adminUser.GetUserId())
+	verifyEmail(ctx, t, s, adminUser.GetUserId())
+
+	for _, email := range []string{"user1@example.com", "user2@example.com"} {
+		user, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+			Email:    email,
+			Password: "user_password",
+		})
+		require.NoError(t, err)
+		verifyEmail(ctx, t, s, user.GetUserId())
+	}
+
+	adminToken, _ := loginAdmin(ctx, t, s, "admin@example.com", "admin123")
+
+	userLogin, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "user1@example.com",
+		Password: "user_password",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AdminClient.ListUsers(ctx, &au.ListUsersRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+userLogin.GetToken())
+	_, err = s.AdminClient.ListUsers(userCtx, &au.ListUsersRequest{})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	adminCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+adminToken)
+
+	page, err := s.AdminClient.ListUsers(adminCtx, &au.ListUsersRequest{
+		EmailPrefix: "user",
+		PageSize:    1,
+	})
+	require.NoError(t, err)
+	require.Len(t, page.GetUsers(), 1)
+	require.NotEmpty(t, page.GetNextPageToken())
+
+	page, err = s.AdminClient.ListUsers(adminCtx, &au.ListUsersRequest{
+		EmailPrefix: "user",
+		PageSize:    1,
+		PageToken:   page.GetNextPageToken(),
+	})
+	require.NoError(t, err)
+	require.Len(t, page.GetUsers(), 1)
+	assert.Empty(t, page.GetNextPageToken())
+
+	target := page.GetUsers()[0]
+	newEmail := "renamed@example.com"
+
+	updated, err := s.AdminClient.UpdateUser(adminCtx, &au.UpdateUserRequest{
+		UserId: target.GetUserId(),
+		Email:  &newEmail,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, newEmail, updated.GetUser().GetEmail())
+
+	isAdmin, err := s.AuthClient.IsAdmin(ctx, &au.IsAdminRequest{UserId: target.GetUserId()})
+	require.NoError(t, err)
+	require.False(t, isAdmin.GetIsAdmin())
+
+	promote := true
+	_, err = s.AdminClient.UpdateUser(adminCtx, &au.UpdateUserRequest{
+		UserId:  target.GetUserId(),
+		IsAdmin: &promote,
+	})
+	require.NoError(t, err)
+
+	isAdmin, err = s.AuthClient.IsAdmin(ctx, &au.IsAdminRequest{UserId: target.GetUserId()})
+	require.NoError(t, err)
+	assert.True(t, isAdmin.GetIsAdmin(), "updating a user must evict its cached admin flag")
+
+	_, err = s.AdminClient.DisableUser(adminCtx, &au.DisableUserRequest{UserId: target.GetUserId()})
+	require.NoError(t, err)
+
+	got, err := s.AdminClient.GetUser(adminCtx, &au.GetUserRequest{UserId: target.GetUserId()})
+	require.NoError(t, err)
+	assert.Equal(t, "disabled", got.GetUser().GetStatus())
+
+	_, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    newEmail,
+		Password: "user_password",
+	})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.AdminClient.DeleteUser(adminCtx, &au.DeleteUserRequest{UserId: target.GetUserId()})
+	require.NoError(t, err)
+
+	got, err = s.AdminClient.GetUser(adminCtx, &au.GetUserRequest{UserId: target.GetUserId()})
+	require.NoError(t, err)
+	assert.Equal(t, "deleted", got.GetUser().GetStatus())
+
+	_, err = s.AdminClient.DisableUser(adminCtx, &au.DisableUserRequest{UserId: target.GetUserId()})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestAdminService_ManageSessions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	adminUser, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "admin@example.com",
+		Password: "admin123",
+	})
+	require.NoError(t, err)
+
+	makeAdmin(t, adminUser.GetUserId())
+	verifyEmail(ctx, t, s, adminUser.GetUserId())
+
+	user, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "user@example.com",
+		Password: "user_password",
+	})
+	require.NoError(t, err)
+	verifyEmail(ctx, t, s, user.GetUserId())
+
+	adminToken, _ := loginAdmin(ctx, t, s, "admin@example.com", "admin123")
+	adminCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+adminToken)
+
+	userLogin, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "user@example.com",
+		Password: "user_password",
+	})
+	require.NoError(t, err)
+
+	list, err := s.AdminClient.ListUserSessions(adminCtx, &au.ListUserSessionsRequest{UserId: user.GetUserId()})
+	require.NoError(t, err)
+	require.Len(t, list.GetSessions(), 1)
+	assert.False(t, list.GetSessions()[0].GetCurrent())
+
+	_, err = s.AdminClient.RevokeUserSessions(adminCtx, &au.RevokeUserSessionsRequest{UserId: user.GetUserId()})
+	require.NoError(t, err)
+
+	userCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+userLogin.GetToken())
+	_, err = s.AuthClient.ListSessions(userCtx, &au.ListSessionsRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "revoked user's token must be rejected")
+
+	_, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "user@example.com",
+		Password: "user_password",
+	})
+	require.NoError(t, err, "revoking sessions must not lock the user out")
+}
@@ -4,6 +4,12 @@ import (
 	"context"
 	"os"
 	"testing"
+	"time"
+	"github.com/Tbits007/auth/internal/domain/models/eventModel"
+	"github.com/Tbits007/auth/internal/lib/passkey/passkeytest"
+	"github.com/Tbits007/auth/internal/lib/password"
+	"github.com/Tbits007/auth/internal/lib/secretbox"
+	"github.com/Tbits007/auth/internal/lib/totp"
 	"github.com/Tbits007/auth/tests/suite"
 	"github.com/Tbits007/auth/tests/testutils"
 	au "github.com/Tbits007/contract/gen/go/auth"
@@ -11,7 +17,9 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -96,6 +104,29 @@ func TestAuthService_Register(t *testing.T) {
 			assert.NotEmpty(t, resp.GetUserId())
 		})
 	}
+
+	t.Run("weak password", func(t *testing.T) {
+		_, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+			Email:    "weak@example.com",
+			Password: "a",
+		})
+		require.Error(t, err)
+
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+
+		var reasons []string
+		for _, detail := range st.Details() {
+			badRequest, ok := detail.(*errdetails.BadRequest)
+			require.True(t, ok)
+			for _, v := range badRequest.GetFieldViolations() {
+				assert.Equal(t, "password", v.GetField())
+				reasons = append(reasons, v.GetReason())
+			}
+		}
+		assert.Contains(t, reasons, password.RuleMinLength)
+	})
 }
 
 func TestAuthService_Login(t *testing.T) {
@@ -114,6 +145,8 @@ func TestAuthService_Login(t *testing.T) {
 	require.NoError(t, err)
 	require.NotEmpty(t, registerResp.GetUserId())
 
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
 	tests := []struct {
 		name        string
 		email       string
@@ -176,10 +209,412 @@ func TestAuthService_Login(t *testing.T) {
 			assert.NotEmpty(t, resp.GetToken(), "token should not be empty")
 			
 			assert.Greater(t, len(resp.GetToken()), 100, "token seems too short for JWT")
+			assert.NotEmpty(t, resp.GetRefreshToken(), "refresh token should not be empty")
 		})
 	}
 }
 
+func TestAuthService_VerifyEmail(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "verify@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "verify@example.com",
+		Password: "correct_password",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	_, err = s.AuthClient.ResendVerification(ctx, &au.ResendVerificationRequest{
+		Email: "verify@example.com",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.ResendVerification(ctx, &au.ResendVerificationRequest{
+		Email: "unknown@example.com",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	_, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "verify@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.VerifyEmail(ctx, &au.VerifyEmailRequest{Token: "unknown"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "reset@example.com",
+		Password: "old_password",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	loginResp, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "reset@example.com",
+		Password: "old_password",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.RequestPasswordReset(ctx, &au.RequestPasswordResetRequest{
+		Email: "unknown@example.com",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.RequestPasswordReset(ctx, &au.RequestPasswordResetRequest{
+		Email: "reset@example.com",
+	})
+	require.NoError(t, err)
+
+	token := mailedToken(ctx, t, s, eventModel.UserPasswordResetRequestedV1, registerResp.GetUserId())
+
+	_, err = s.AuthClient.ResetPassword(ctx, &au.ResetPasswordRequest{
+		Token:       token,
+		NewPassword: "new_password",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.ResetPassword(ctx, &au.ResetPasswordRequest{
+		Token:       token,
+		NewPassword: "another_password",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	introspection, err := s.AuthClient.Introspect(ctx, &au.IntrospectRequest{Token: loginResp.GetToken()})
+	require.NoError(t, err)
+	assert.False(t, introspection.GetActive())
+
+	_, err = s.AuthClient.Refresh(ctx, &au.RefreshRequest{RefreshToken: loginResp.GetRefreshToken()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "reset@example.com",
+		Password: "old_password",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "reset@example.com",
+		Password: "new_password",
+	})
+	require.NoError(t, err)
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "change@example.com",
+		Password: "old_password",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	loginResp, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "change@example.com",
+		Password: "old_password",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.ChangePassword(ctx, &au.ChangePasswordRequest{
+		OldPassword: "old_password",
+		NewPassword: "new_password",
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+loginResp.GetToken())
+
+	_, err = s.AuthClient.ChangePassword(authCtx, &au.ChangePasswordRequest{
+		OldPassword: "wrong_password",
+		NewPassword: "new_password",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.AuthClient.ChangePassword(authCtx, &au.ChangePasswordRequest{
+		OldPassword: "old_password",
+		NewPassword: "short",
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	changeResp, err := s.AuthClient.ChangePassword(authCtx, &au.ChangePasswordRequest{
+		OldPassword:         "old_password",
+		NewPassword:         "new_password",
+		RevokeOtherSessions: true,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, changeResp.GetToken())
+	require.NotEmpty(t, changeResp.GetRefreshToken())
+
+	introspection, err := s.AuthClient.Introspect(ctx, &au.IntrospectRequest{Token: loginResp.GetToken()})
+	require.NoError(t, err)
+	assert.False(t, introspection.GetActive())
+
+	introspection, err = s.AuthClient.Introspect(ctx, &au.IntrospectRequest{Token: changeResp.GetToken()})
+	require.NoError(t, err)
+	assert.True(t, introspection.GetActive())
+
+	_, err = s.AuthClient.Refresh(ctx, &au.RefreshRequest{RefreshToken: loginResp.GetRefreshToken()})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "change@example.com",
+		Password: "new_password",
+	})
+	require.NoError(t, err)
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "refresh@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	loginResp, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "refresh@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	rotated, err := s.AuthClient.Refresh(ctx, &au.RefreshRequest{
+		RefreshToken: loginResp.GetRefreshToken(),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, rotated.GetToken())
+	assert.NotEqual(t, loginResp.GetRefreshToken(), rotated.GetRefreshToken())
+
+	_, err = s.AuthClient.Refresh(ctx, &au.RefreshRequest{
+		RefreshToken: loginResp.GetRefreshToken(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "reused token must be rejected")
+
+	_, err = s.AuthClient.Refresh(ctx, &au.RefreshRequest{
+		RefreshToken: rotated.GetRefreshToken(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "reuse must revoke the whole family")
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "logout@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	loginResp, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "logout@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.Logout(ctx, &au.LogoutRequest{
+		Token:        loginResp.GetToken(),
+		RefreshToken: loginResp.GetRefreshToken(),
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.Logout(ctx, &au.LogoutRequest{
+		Token: loginResp.GetToken(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "revoked token must be rejected")
+
+	_, err = s.AuthClient.Refresh(ctx, &au.RefreshRequest{
+		RefreshToken: loginResp.GetRefreshToken(),
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "logout must revoke the refresh token")
+
+	relogin, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "logout@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, loginResp.GetToken(), relogin.GetToken(), "each login must issue a fresh token")
+}
+
+func TestAuthService_Sessions(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "sessions@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	laptop, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "sessions@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	phone, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "sessions@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+	assert.NotEqual(t, laptop.GetToken(), phone.GetToken(), "each login must get its own session")
+
+	laptopCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+laptop.GetToken())
+	phoneCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+phone.GetToken())
+
+	list, err := s.AuthClient.ListSessions(laptopCtx, &au.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetSessions(), 2)
+
+	var phoneSessionID string
+	for _, session := range list.GetSessions() {
+		assert.NotEmpty(t, session.GetUserAgent())
+		if !session.GetCurrent() {
+			phoneSessionID = session.GetSessionId()
+		}
+	}
+	require.NotEmpty(t, phoneSessionID)
+
+	_, err = s.AuthClient.RevokeSession(laptopCtx, &au.RevokeSessionRequest{SessionId: phoneSessionID})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.ListSessions(phoneCtx, &au.ListSessionsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "revoked session's token must be rejected")
+
+	_, err = s.AuthClient.Refresh(ctx, &au.RefreshRequest{RefreshToken: phone.GetRefreshToken()})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "revoked session's refresh token must be rejected")
+
+	_, err = s.AuthClient.RevokeSession(laptopCtx, &au.RevokeSessionRequest{SessionId: phoneSessionID})
+	require.Error(t, err)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	rotated, err := s.AuthClient.Refresh(ctx, &au.RefreshRequest{RefreshToken: laptop.GetRefreshToken()})
+	require.NoError(t, err)
+
+	rotatedCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+rotated.GetToken())
+	list, err = s.AuthClient.ListSessions(rotatedCtx, &au.ListSessionsRequest{})
+	require.NoError(t, err)
+	require.Len(t, list.GetSessions(), 1)
+	assert.True(t, list.GetSessions()[0].GetCurrent(), "refresh must keep the session")
+
+	_, err = s.AuthClient.RevokeAllSessions(rotatedCtx, &au.RevokeAllSessionsRequest{})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.ListSessions(rotatedCtx, &au.ListSessionsRequest{})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthService_Introspect(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "introspect@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	loginResp, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "introspect@example.com",
+		Password: "correct_password",
+	})
+	require.NoError(t, err)
+
+	resp, err := s.AuthClient.Introspect(ctx, &au.IntrospectRequest{
+		Token: loginResp.GetToken(),
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.GetActive())
+	assert.Equal(t, registerResp.GetUserId(), resp.GetUserId())
+	assert.Equal(t, "introspect@example.com", resp.GetEmail())
+	assert.False(t, resp.GetIsAdmin())
+	assert.Greater(t, resp.GetExpiresAt(), resp.GetIssuedAt())
+
+	resp, err = s.AuthClient.Introspect(ctx, &au.IntrospectRequest{
+		Token: "garbage",
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.GetActive())
+
+	_, err = s.AuthClient.Logout(ctx, &au.LogoutRequest{
+		Token: loginResp.GetToken(),
+	})
+	require.NoError(t, err)
+
+	resp, err = s.AuthClient.Introspect(ctx, &au.IntrospectRequest{
+		Token: loginResp.GetToken(),
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.GetActive(), "revoked token must be inactive")
+}
+
 func TestAuthService_IsAdmin(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -255,12 +690,349 @@ func TestAuthService_IsAdmin(t *testing.T) {
 	}
 }
 
+func TestAuthService_HasPermission(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	regularUser, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "regular@example.com",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	adminUser, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "admin@example.com",
+		Password: "admin123",
+	})
+	require.NoError(t, err)
+
+	makeAdmin(t, adminUser.GetUserId())
+	verifyEmail(ctx, t, s, adminUser.GetUserId())
+
+	resp, err := s.AuthClient.HasPermission(ctx, &au.HasPermissionRequest{
+		UserId:     adminUser.GetUserId(),
+		Permission: "roles:assign",
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.GetHasPermission())
+
+	resp, err = s.AuthClient.HasPermission(ctx, &au.HasPermissionRequest{
+		UserId:     regularUser.GetUserId(),
+		Permission: "roles:assign",
+	})
+	require.NoError(t, err)
+	assert.False(t, resp.GetHasPermission())
+
+	adminToken, _ := loginAdmin(ctx, t, s, "admin@example.com", "admin123")
+
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+adminToken)
+	_, err = s.AuthClient.AssignRole(authCtx, &au.AssignRoleRequest{
+		UserId: regularUser.GetUserId(),
+		Role:   "support",
+	})
+	require.NoError(t, err)
+
+	resp, err = s.AuthClient.HasPermission(ctx, &au.HasPermissionRequest{
+		UserId:     regularUser.GetUserId(),
+		Permission: "users:read",
+	})
+	require.NoError(t, err)
+	assert.True(t, resp.GetHasPermission())
+
+	_, err = s.AuthClient.AssignRole(ctx, &au.AssignRoleRequest{
+		UserId: regularUser.GetUserId(),
+		Role:   "admin",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestAuthService_MFA(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	adminUser, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "admin@example.com",
+		Password: "admin123",
+	})
+	require.NoError(t, err)
+
+	makeAdmin(t, adminUser.GetUserId())
+	verifyEmail(ctx, t, s, adminUser.GetUserId())
+
+	login, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "admin@example.com",
+		Password: "admin123",
+	})
+	require.NoError(t, err)
+	assert.True(t, login.GetMfaRequired())
+	assert.True(t, login.GetMfaEnrollmentRequired())
+	assert.Empty(t, login.GetToken())
+
+	_, err = s.AuthClient.VerifyMFA(ctx, &au.VerifyMFARequest{
+		ChallengeId: login.GetMfaChallengeId(),
+		Code:        "123456",
+	})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	enrollment, err := s.AuthClient.EnrollTOTP(ctx, &au.EnrollTOTPRequest{
+		ChallengeId: login.GetMfaChallengeId(),
+	})
+	require.NoError(t, err)
+	assert.Contains(t, enrollment.GetUri(), "otpauth://totp/")
+
+	code := totpCode(t, enrollment.GetSecret())
+	confirmed, err := s.AuthClient.ConfirmTOTP(ctx, &au.ConfirmTOTPRequest{
+		ChallengeId: login.GetMfaChallengeId(),
+		Code:        code,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, confirmed.GetRecoveryCodes())
+	assert.NotEmpty(t, confirmed.GetToken())
+
+	login, err = s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "admin@example.com",
+		Password: "admin123",
+	})
+	require.NoError(t, err)
+	assert.True(t, login.GetMfaRequired())
+	assert.False(t, login.GetMfaEnrollmentRequired())
+
+	_, err = s.AuthClient.VerifyMFA(ctx, &au.VerifyMFARequest{
+		ChallengeId: login.GetMfaChallengeId(),
+		Code:        code,
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err), "replayed code")
+
+	recoveryCode := confirmed.GetRecoveryCodes()[0]
+	verified, err := s.AuthClient.VerifyMFA(ctx, &au.VerifyMFARequest{
+		ChallengeId: login.GetMfaChallengeId(),
+		Code:        recoveryCode,
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, verified.GetToken())
+
+	_, err = s.AuthClient.VerifyMFA(ctx, &au.VerifyMFARequest{
+		ChallengeId: login.GetMfaChallengeId(),
+		Code:        recoveryCode,
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "consumed challenge")
+}
+
+// TestAuthService_Passkey relies on the default relying party, localhost.
+func TestAuthService_Passkey(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	registerResp, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "passkey@example.com",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	verifyEmail(ctx, t, s, registerResp.GetUserId())
+
+	loginResp, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    "passkey@example.com",
+		Password: "password123",
+	})
+	require.NoError(t, err)
+
+	_, err = s.AuthClient.BeginPasskeyRegistration(ctx, &au.BeginPasskeyRegistrationRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+loginResp.GetToken())
+
+	authenticator, err := passkeytest.New("http://localhost")
+	require.NoError(t, err)
+
+	creation, err := s.AuthClient.BeginPasskeyRegistration(authCtx, &au.BeginPasskeyRegistrationRequest{})
+	require.NoError(t, err)
+
+	attestation, err := authenticator.Register([]byte(creation.GetOptions()))
+	require.NoError(t, err)
+
+	registered, err := s.AuthClient.FinishPasskeyRegistration(authCtx, &au.FinishPasskeyRegistrationRequest{
+		Credential: string(attestation),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, registered.GetPasskeyId())
+
+	_, err = s.AuthClient.FinishPasskeyRegistration(authCtx, &au.FinishPasskeyRegistrationRequest{
+		Credential: string(attestation),
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "used registration challenge")
+
+	request, err := s.AuthClient.BeginPasskeyLogin(ctx, &au.BeginPasskeyLoginRequest{})
+	require.NoError(t, err)
+
+	assertion, err := authenticator.Assert([]byte(request.GetOptions()))
+	require.NoError(t, err)
+
+	login, err := s.AuthClient.FinishPasskeyLogin(ctx, &au.FinishPasskeyLoginRequest{
+		ChallengeId: request.GetChallengeId(),
+		Credential:  string(assertion),
+	})
+	require.NoError(t, err)
+	assert.NotEmpty(t, login.GetToken())
+	assert.NotEmpty(t, login.GetRefreshToken())
+
+	_, err = s.AuthClient.FinishPasskeyLogin(ctx, &au.FinishPasskeyLoginRequest{
+		ChallengeId: request.GetChallengeId(),
+		Credential:  string(assertion),
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err), "used login challenge")
+}
+
+func TestAuthService_Check(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	ctx, s := suite.NewSuite(t)
+
+	cleanTables(t)
+
+	adminUser, err := s.AuthClient.Register(ctx, &au.RegisterRequest{
+		Email:    "admin@example.com",
+		Password: "admin123",
+	})
+	require.NoError(t, err)
+
+	makeAdmin(t, adminUser.GetUserId())
+	verifyEmail(ctx, t, s, adminUser.GetUserId())
+
+	adminToken, _ := loginAdmin(ctx, t, s, "admin@example.com", "admin123")
+
+	subject := "user:" + adminUser.GetUserId()
+
+	check := func(relation string) bool {
+		resp, err := s.AuthClient.Check(ctx, &au.CheckRequest{
+			Subject:  subject,
+			Relation: relation,
+			Object:   "organization:42",
+		})
+		require.NoError(t, err)
+		return resp.GetAllowed()
+	}
+
+	assert.False(t, check("viewer"))
+
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+adminToken)
+	_, err = s.AuthClient.WriteRelation(authCtx, &au.WriteRelationRequest{
+		Subject:  subject,
+		Relation: "editor",
+		Object:   "organization:42",
+	})
+	require.NoError(t, err)
+
+	assert.True(t, check("viewer"))
+	assert.True(t, check("editor"))
+	assert.False(t, check("owner"))
+
+	_, err = s.AuthClient.Check(ctx, &au.CheckRequest{
+		Subject:  "nobody",
+		Relation: "viewer",
+		Object:   "organization:42",
+	})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+// verifyEmail activates a freshly registered user with the token carried
+// by its verification event, as the mailer would.
+func verifyEmail(ctx context.Context, t *testing.T, s *suite.Suite, userID string) {
+	t.Helper()
+
+	token := mailedToken(ctx, t, s, eventModel.UserVerificationRequestedV1, userID)
+
+	_, err := s.AuthClient.VerifyEmail(ctx, &au.VerifyEmailRequest{Token: token})
+	require.NoError(t, err)
+}
+
+// mailedToken opens the sealed token of the latest eventType event about
+// userID with the token encryption key, as the mailer would.
+func mailedToken(ctx context.Context, t *testing.T, s *suite.Suite, eventType string, userID string) string {
+	t.Helper()
+
+	var sealed []byte
+	err := testDB.QueryRow(ctx, `
+		SELECT decode(payload->'data'->>'encrypted_token', 'base64')
+		FROM outbox
+		WHERE event_type = $1 AND payload->>'subject' = $2
+		ORDER BY created_at DESC
+		LIMIT 1`,
+		eventType, userID,
+	).Scan(&sealed)
+	require.NoError(t, err)
+
+	box, err := secretbox.NewFromBase64(s.Cfg.Auth.TokenEncryptionKey)
+	require.NoError(t, err)
+
+	id := uuid.MustParse(userID)
+	token, err := box.Open(sealed, id[:])
+	require.NoError(t, err)
+
+	return string(token)
+}
+
+// loginAdmin logs in an admin without MFA, which has to enroll TOTP
+// first, and returns the access token along with the TOTP secret.
+func loginAdmin(ctx context.Context, t *testing.T, s *suite.Suite, email, password string) (string, string) {
+	t.Helper()
+
+	login, err := s.AuthClient.Login(ctx, &au.LoginRequest{
+		Email:    email,
+		Password: password,
+	})
+	require.NoError(t, err)
+	require.True(t, login.GetMfaEnrollmentRequired())
+
+	enrollment, err := s.AuthClient.EnrollTOTP(ctx, &au.EnrollTOTPRequest{
+		ChallengeId: login.GetMfaChallengeId(),
+	})
+	require.NoError(t, err)
+
+	confirmed, err := s.AuthClient.ConfirmTOTP(ctx, &au.ConfirmTOTPRequest{
+		ChallengeId: login.GetMfaChallengeId(),
+		Code:        totpCode(t, enrollment.GetSecret()),
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, confirmed.GetToken())
+
+	return confirmed.GetToken(), enrollment.GetSecret()
+}
+
+func totpCode(t *testing.T, secret string) string {
+	t.Helper()
+
+	code, err := totp.Code(secret, totp.Step(time.Now()))
+	require.NoError(t, err)
+
+	return code
+}
+
 func makeAdmin(t *testing.T, userID string) {
 	uuid, err := uuid.Parse(userID)
 	require.NoError(t, err)
 
 	_, err = testDB.Exec(context.Background(),
-		"UPDATE users SET is_admin = true WHERE id = $1", uuid)
+		"INSERT INTO user_roles (user_id, role) VALUES ($1, 'admin')", uuid)
 	require.NoError(t, err)
 }
 
@@ -270,4 +1042,7 @@ func cleanTables(t *testing.T) {
 	
 	_, err = testDB.Exec(context.Background(), "TRUNCATE TABLE outbox CASCADE")
 	require.NoError(t, err)
+
+	_, err = testDB.Exec(context.Background(), "TRUNCATE TABLE relation_tuples")
+	require.NoError(t, err)
 }
@@ -17,6 +17,7 @@ type Suite struct {
     T          *testing.T                  
     Cfg        *config.Config   
     AuthClient  au.AuthClient
+    AdminClient au.AdminClient
 }
 
 func NewSuite(t *testing.T) (context.Context, *Suite) {
@@ -43,10 +44,12 @@ func NewSuite(t *testing.T) (context.Context, *Suite) {
     }
 
     authClient := au.NewAuthClient(cc)
+    adminClient := au.NewAdminClient(cc)
 
     return ctx, &Suite{
         T:          t,
         Cfg:        cfg,
-        AuthClient: authClient,
+        AuthClient:  authClient,
+        AdminClient: adminClient,
     }
 }
\ No newline at end of file